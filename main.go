@@ -1,20 +1,298 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 	"urlmonitor/src/entity"
 )
 
+// shutdownTimeout bounds the graceful shutdown of the server and the flushing of spans
+const shutdownTimeout = 5 * time.Second
+
 func main() {
-	monitor := entity.NewUptimeMonitor()
+	debugToken := flag.String("debug-token", "", "bearer token required by /debug endpoints (empty disables auth)")
+	mergeGap := flag.Duration("merge-gap", 0, "failures within this gap after a recovery extend the previous downtime")
+	staleAfter := flag.Duration("stale-after", 0, "report a URL as stale when its latest check is older than this")
+	flapWindow := flag.Duration("flap-window", 30*time.Minute, "recent period in which up/down changes count towards flapping")
+	flapThreshold := flag.Int("flap-threshold", 5, "up/down changes within -flap-window that mark a monitor as flapping (0 disables)")
+	pacFile := flag.String("pac-file", "", "Proxy Auto-Config file used to route outbound checks")
+	proxyUser := flag.String("proxy-user", "", "username for proxies chosen by the PAC file")
+	proxyPass := flag.String("proxy-pass", "", "password for proxies chosen by the PAC file")
+	syslogNetwork := flag.String("syslog-network", "", "syslog network (udp, tcp); empty uses the local daemon")
+	syslogAddr := flag.String("syslog-addr", "", "syslog address, e.g. logs.example.com:514")
+	syslogFacility := flag.String("syslog-facility", "", "syslog facility (daemon, local0-local7, ...); enables syslog when set")
+	configFile := flag.String("config", "", "JSON or YAML file of monitor definitions added on startup")
+	configURL := flag.String("config-url", "", "HTTP endpoint serving the monitor definitions to keep in sync with")
+	configPoll := flag.Duration("config-poll", 30*time.Second, "how often to poll -config-url")
+	deadLetterFile := flag.String("dead-letter-file", "", "JSON file keeping notifications that could not be delivered")
+	notifyRetries := flag.Int("notify-retries", 3, "delivery attempts per notification before it is dead-lettered")
+	notifyBackoff := flag.Duration("notify-backoff", time.Second, "pause between notification attempts, growing with each retry")
+	maxLogs := flag.Int("max-logs", 10000, "log entries retained across all URLs before the oldest are evicted (0 keeps all)")
+	stateFile := flag.String("state-file", "", "JSON file the monitors, logs and downtimes are saved to and restored from on restart; holds monitor credentials in plaintext")
+	stateSaveInterval := flag.Duration("state-save-interval", time.Minute, "how often -state-file is written")
+	memoryLimit := flag.Uint64("memory-limit", 0, "heap bytes considered full; when set, log retention per URL adapts between -min-logs and -max-url-logs")
+	minLogs := flag.Int("min-logs", 100, "smallest log retention per URL under memory pressure")
+	maxURLLogs := flag.Int("max-url-logs", 1000, "largest log retention per URL while memory is plentiful")
+	maxInFlight := flag.Int("max-in-flight", 0, "reject new monitors with 503 while this many checks are running (0 disables)")
+	maxConcurrentChecks := flag.Int("max-concurrent-checks", 0, "run at most this many checks at once, queueing the rest (0 disables)")
+	skipSuccessLogs := flag.Bool("skip-success-logs", false, "keep only failed checks in the log to save memory")
+	waitFor := flag.String("wait-for", "", "URL of a dependency, e.g. the result store, that must answer 2xx before checks start")
+	waitTimeout := flag.Duration("wait-timeout", time.Minute, "how long to wait for -wait-for before starting checks anyway")
+	otelEndpoint := flag.String("otel-endpoint", "", "OTLP/HTTP collector URL receiving a span per check, e.g. http://localhost:4318")
+	incidentCreate := flag.String("incident-create-url", "", "incident API endpoint opening an incident per downtime; answers with {\"id\": ...}")
+	incidentResolve := flag.String("incident-resolve-url", "", "incident API endpoint resolving an incident; {id} is replaced with its ID")
+	incidentAuth := flag.String("incident-auth", "", "Authorization header sent to the incident API")
+	compareResolvers := flag.String("compare-resolvers", "", "comma-separated DNS servers for /monitor/compare-resolvers, e.g. 8.8.8.8:53,1.1.1.1:53")
+	agents := flag.String("agents", "", "comma-separated remote agents confirming failures, as name=url, e.g. eu=http://agent-eu:8080")
+	sampleDir := flag.String("sample-dir", "", "directory storing sampled response bodies for forensics")
+	sampleEvery := flag.Duration("sample-every", time.Minute, "minimum time between body samples of a URL")
+	sampleMaxBytes := flag.Int64("sample-max-bytes", 100<<20, "total size of stored body samples before the oldest are deleted")
+	digestEvery := flag.Duration("digest-every", 0, "send one digest of all events per interval instead of individual alerts")
+	digestHeartbeat := flag.Bool("digest-heartbeat", false, "send a digest even when no events occurred in the interval")
+	webhookURL := flag.String("webhook-url", "", "URL receiving a JSON POST when a URL goes down and when it recovers")
+	slackWebhookURL := flag.String("slack-webhook-url", "", "Slack incoming webhook URL notified when a URL goes down and when it recovers")
+	var latencyBuckets []float64
+	flag.Func("latency-buckets", "comma-separated response time histogram bounds in milliseconds (default 50,100,250,500,1000,2500,5000,10000)", func(text string) error {
+		var err error
+		latencyBuckets, err = entity.ParseLatencyBuckets(text)
+		return err
+	})
+	var logLevel slog.Level
+	flag.TextVar(&logLevel, "log-level", slog.LevelInfo, "minimum level of the JSON operational logs; debug includes every check result")
+	flag.Parse()
+
+	// Operational logs are JSON; the standard log package is routed through the same handler.
+	// With syslog enabled every record is also sent there at the priority of its level.
+	var logHandler slog.Handler = slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel})
+	var syslogWriter entity.SyslogWriter
+	var syslogErr error
+	if *syslogFacility != "" || *syslogAddr != "" {
+		syslogWriter, syslogErr = entity.DialSyslog(*syslogNetwork, *syslogAddr, *syslogFacility)
+		if syslogErr == nil {
+			logHandler = entity.NewTeeHandler(logHandler, entity.NewSyslogHandler(syslogWriter))
+		}
+	}
+	slog.SetDefault(slog.New(logHandler))
+	if syslogErr != nil {
+		slog.Warn("syslog unavailable, continuing without it", "error", syslogErr)
+	}
+
+	// Deferred first so it runs after every other deferred cleanup
+	exitCode := 0
+	defer func() { os.Exit(exitCode) }()
+
+	// SIGINT and SIGTERM shut the server down gracefully, saving the state and flushing spans
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	opts := []entity.Option{
+		entity.WithDebugToken(*debugToken),
+		entity.WithDowntimeMergeGap(*mergeGap),
+		entity.WithStaleAfter(*staleAfter),
+		entity.WithFlapDetection(*flapWindow, *flapThreshold),
+		entity.WithNotifyRetries(*notifyRetries, *notifyBackoff),
+	}
+	if latencyBuckets != nil {
+		opts = append(opts, entity.WithLatencyBuckets(latencyBuckets))
+	}
+	if *otelEndpoint != "" {
+		provider, err := entity.NewOTLPTracerProvider(ctx, *otelEndpoint)
+		if err != nil {
+			fatal("Failed to set up tracing", "error", err)
+		}
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+			if err := provider.Shutdown(shutdownCtx); err != nil {
+				slog.Warn("Failed to flush spans", "error", err)
+			}
+		}()
+		opts = append(opts, entity.WithTracerProvider(provider))
+	}
+	if *incidentCreate != "" {
+		api := &entity.HTTPIncidentAPI{CreateURL: *incidentCreate, ResolveURL: *incidentResolve}
+		if *incidentAuth != "" {
+			api.Headers = map[string]string{"Authorization": *incidentAuth}
+		}
+		opts = append(opts, entity.WithIncidentManager(api))
+	}
+	if *compareResolvers != "" {
+		resolvers := make(map[string]entity.DNSResolver)
+		for _, addr := range strings.Split(*compareResolvers, ",") {
+			addr = strings.TrimSpace(addr)
+			resolvers[addr] = entity.NameserverResolver{Address: addr}
+		}
+		opts = append(opts, entity.WithComparisonResolvers(resolvers))
+	}
+	if *agents != "" {
+		var remote []entity.RemoteAgent
+		for _, agent := range strings.Split(*agents, ",") {
+			name, agentURL, ok := strings.Cut(strings.TrimSpace(agent), "=")
+			if !ok {
+				fatal("Invalid agent, expected name=url", "agent", agent)
+			}
+			remote = append(remote, entity.RemoteAgent{Name: name, URL: agentURL})
+		}
+		opts = append(opts, entity.WithRemoteAgents(remote...))
+	}
+	if *sampleDir != "" {
+		opts = append(opts, entity.WithBodySampling(*sampleDir, *sampleEvery, *sampleMaxBytes))
+	}
+	if *waitFor != "" {
+		opts = append(opts, entity.WithReadinessGate(entity.HTTPReadiness(*waitFor), *waitTimeout))
+	}
+	opts = append(opts, entity.WithMaxLogs(*maxLogs), entity.WithMaxInFlight(*maxInFlight), entity.WithMaxConcurrentChecks(*maxConcurrentChecks))
+	if *skipSuccessLogs {
+		opts = append(opts, entity.WithoutSuccessLogs())
+	}
+	if *digestEvery > 0 {
+		opts = append(opts, entity.WithDigest(*digestEvery, *digestHeartbeat))
+	}
+	if *webhookURL != "" {
+		opts = append(opts, entity.WithWebhook(*webhookURL))
+	}
+	if *slackWebhookURL != "" {
+		opts = append(opts, entity.WithSlack(*slackWebhookURL))
+	}
+	if *deadLetterFile != "" {
+		opts = append(opts, entity.WithDeadLetterFile(*deadLetterFile))
+	}
+	if syslogWriter != nil {
+		opts = append(opts, entity.WithSyslog(syslogWriter))
+	}
+	if *pacFile != "" {
+		resolver, err := entity.LoadPACFile(*pacFile)
+		if err != nil {
+			fatal("Failed to load PAC file", "path", *pacFile, "error", err)
+		}
+		if *proxyUser != "" {
+			resolver.Credentials = url.UserPassword(*proxyUser, *proxyPass)
+		}
+		opts = append(opts, entity.WithProxyResolver(resolver))
+	}
+
+	var monitor *entity.UptimeMonitor
+	if *stateFile != "" {
+		var err error
+		monitor, err = entity.LoadState(*stateFile, opts...)
+		if err != nil {
+			fatal("Failed to load state", "path", *stateFile, "error", err)
+		}
+		go saveState(ctx, monitor, *stateFile, *stateSaveInterval)
+	} else {
+		monitor = entity.NewUptimeMonitor(opts...)
+	}
+	defer monitor.Close()
+	if *configFile != "" {
+		if err := monitor.LoadMonitorsFromFile(*configFile); err != nil {
+			fatal("Failed to load config file", "path", *configFile, "error", err)
+		}
+	}
+	if *configURL != "" {
+		source := &entity.HTTPConfigSource{URL: *configURL, Interval: *configPoll}
+		monitor.SyncFrom(source, ctx.Done())
+	}
+	if *memoryLimit > 0 {
+		policy := entity.RetentionPolicy{MinLogs: *minLogs, MaxLogs: *maxURLLogs, MemoryLimit: *memoryLimit, Interval: 10 * time.Second}
+		monitor.AdaptRetention(policy, ctx.Done())
+	}
 
 	// API endpoints
 	http.HandleFunc("/monitor/add", monitor.HandleAddMonitor)
 	http.HandleFunc("/monitor/remove", monitor.HandleRemoveMonitor)
+	http.HandleFunc("/monitor/update", monitor.HandleUpdateMonitor)
+	http.HandleFunc("/monitor/check-all", monitor.HandleCheckAll)
+	http.HandleFunc("/monitor/list", monitor.HandleListMonitors)
+	http.HandleFunc("/monitor/export", monitor.HandleExportConfig)
+	http.HandleFunc("/monitor/archive", monitor.HandleArchiveMonitor)
+	http.HandleFunc("/monitor/unarchive", monitor.HandleUnarchiveMonitor)
+	http.HandleFunc("/monitor/archived", monitor.HandleGetArchivedMonitors)
+	http.HandleFunc("/monitor/pause", monitor.HandlePauseMonitor)
+	http.HandleFunc("/monitor/resume", monitor.HandleResumeMonitor)
 	http.HandleFunc("/monitor/logs", monitor.HandleGetLogs)
 	http.HandleFunc("/monitor/downtimes", monitor.HandleGetDowntimes)
+	http.HandleFunc("/monitor/logs.csv", monitor.HandleGetLogsCSV)
+	http.HandleFunc("/monitor/downtimes.csv", monitor.HandleGetDowntimesCSV)
+	http.HandleFunc("/monitor/stats", monitor.HandleGetStats)
+	http.HandleFunc("/monitor/summary", monitor.HandleGetSummary)
+	http.HandleFunc("/monitor/bandwidth", monitor.HandleGetBandwidth)
+	http.HandleFunc("/monitor/status", monitor.HandleGetStatus)
+	http.HandleFunc("/monitor/transitions", monitor.HandleGetTransitions)
+	http.HandleFunc("/monitor/sparkline", monitor.HandleGetSparkline)
+	http.HandleFunc("/monitor/compare-agents", monitor.HandleCompareUserAgents)
+	http.HandleFunc("/monitor/replay", monitor.HandleReplayLogs)
+	http.HandleFunc("/monitor/samples", monitor.HandleGetBodySamples)
+	http.HandleFunc("/monitor/compare-resolvers", monitor.HandleCompareResolvers)
+
+	// Notifications
+	http.HandleFunc("/notifications/dead-letters", monitor.HandleGetDeadLetters)
+	http.HandleFunc("/notifications/replay", monitor.HandleReplayDeadLetters)
+
+	// Remote agent protocol
+	http.HandleFunc("/agent/check", monitor.HandleAgentCheck)
+
+	// Metrics
+	http.HandleFunc("/metrics", monitor.HandlePrometheusMetrics)
+	http.HandleFunc("/metrics.json", monitor.HandleMetricsJSON)
+
+	// Diagnostics
+	http.HandleFunc("/healthz", monitor.HandleHealthz)
+	http.HandleFunc("/readyz", monitor.HandleReadyz)
+	http.HandleFunc("/debug/monitors", monitor.HandleDebugMonitors)
+
+	server := &http.Server{Addr: ":8080"}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("Starting server", "addr", server.Addr)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("Server stopped", "error", err)
+		exitCode = 1
+		return
+	}
+	slog.Info("Server shut down")
+
+	// Save once more so nothing since the last periodic save is lost
+	if *stateFile != "" {
+		if err := monitor.SaveState(*stateFile); err != nil {
+			slog.Error("Failed to save state", "path", *stateFile, "error", err)
+			exitCode = 1
+		}
+	}
+}
+
+// saveState writes the state file periodically until ctx is done
+func saveState(ctx context.Context, monitor *entity.UptimeMonitor, path string, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := monitor.SaveState(path); err != nil {
+				slog.Error("Failed to save state", "path", path, "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
 
-	log.Printf("Starting server on :8080")
-	log.Fatal(http.ListenAndServe(":8080", nil))
+// fatal logs an error that keeps the service from running and exits
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
 }
@@ -0,0 +1,163 @@
+package entity
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "strings"
+    "sync"
+)
+
+// RemoteAgent is another urlmonitor instance, typically in a different location, that
+// performs checks on request through its /agent/check endpoint
+type RemoteAgent struct {
+    Name string `json:"name"` // location, e.g. "eu-west"
+    URL  string `json:"url"`  // base URL of the agent, e.g. http://agent-eu:8080
+}
+
+// AgentResult is the agent protocol's response: the outcome of one check from one location
+type AgentResult struct {
+    Location     string `json:"location"`
+    Success      bool   `json:"success"`
+    StatusCode   int    `json:"statusCode"`
+    ResponseTime int64  `json:"responseTime"` // in milliseconds
+    Error        string `json:"error,omitempty"`
+}
+
+// WithRemoteAgents registers the agents asked to confirm failures of monitors with ConfirmWithAgents
+func WithRemoteAgents(agents ...RemoteAgent) Option {
+    return func(um *UptimeMonitor) {
+        um.agents = agents
+    }
+}
+
+// agentCheck is the check agents perform: a single attempt of the monitor's own check, of any
+// monitor type and with all of its assertions. Credentials are redacted before monitors are sent
+// to agents, so an FTP agent check stops after the server greeting, and a request rejected with
+// 401 or 403 for lack of the withheld credentials counts as up, as the service did answer.
+func (um *UptimeMonitor) agentCheck(monitor Monitor) AgentResult {
+    withheld := withheldCredentials(monitor)
+    if monitor.FTPPassword == redactedSecret {
+        monitor.FTPUser, monitor.FTPPassword = "", ""
+    }
+
+    ctx := context.Background()
+    if monitor.CheckBudget > 0 {
+        var cancel context.CancelFunc
+        ctx, cancel = context.WithTimeout(ctx, monitor.CheckBudget)
+        defer cancel()
+    }
+    entry := um.attemptCheck(ctx, monitor)
+
+    result := AgentResult{
+        Success:      entry.Success,
+        StatusCode:   entry.StatusCode,
+        ResponseTime: entry.ResponseTime,
+        Error:        entry.Error,
+    }
+    if !result.Success && withheld &&
+        (result.StatusCode == http.StatusUnauthorized || result.StatusCode == http.StatusForbidden) {
+        result.Success = true
+        result.Error = ""
+    }
+    return result
+}
+
+// withheldCredentials reports whether a monitor received by an agent had credentials redacted
+func withheldCredentials(monitor Monitor) bool {
+    if monitor.BasicAuthPass == redactedSecret || monitor.BearerToken == redactedSecret ||
+        monitor.SigV4 != nil && monitor.SigV4.SecretAccessKey == redactedSecret {
+        return true
+    }
+    for name, value := range monitor.Headers {
+        if isSecretHeader(name) && value == redactedSecret {
+            return true
+        }
+    }
+    return false
+}
+
+// askAgent requests a check of monitor from one agent. The monitor's credentials are redacted,
+// agents are not trusted with them.
+func askAgent(client *http.Client, agent RemoteAgent, monitor Monitor) (AgentResult, error) {
+    payload, err := json.Marshal(redactSecrets(monitor).Config())
+    if err != nil {
+        return AgentResult{}, err
+    }
+    resp, err := client.Post(strings.TrimSuffix(agent.URL, "/")+"/agent/check", "application/json", bytes.NewReader(payload))
+    if err != nil {
+        return AgentResult{}, err
+    }
+    defer resp.Body.Close()
+    if resp.StatusCode != http.StatusOK {
+        return AgentResult{}, fmt.Errorf("agent %s answered %s", agent.Name, resp.Status)
+    }
+
+    var result AgentResult
+    if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+        return AgentResult{}, err
+    }
+    result.Location = agent.Name
+    return result, nil
+}
+
+// confirmFailure asks every agent to check the monitor concurrently and reports whether
+// the URL is down from a majority of the locations that answered, this one included.
+// Unreachable agents abstain. The per-location results are recorded on the entry.
+func (um *UptimeMonitor) confirmFailure(monitor Monitor, entry *LogEntry) bool {
+    results := make([]AgentResult, len(um.agents))
+    errs := make([]error, len(um.agents))
+    var wg sync.WaitGroup
+    // Agents answer once their own check has finished or timed out
+    client := &http.Client{Transport: um.client.Transport, Timeout: monitor.timeout() + defaultTimeout}
+    for i, agent := range um.agents {
+        wg.Add(1)
+        go func(i int, agent RemoteAgent) {
+            defer wg.Done()
+            results[i], errs[i] = askAgent(client, agent, monitor)
+        }(i, agent)
+    }
+    wg.Wait()
+
+    down, voters := 1, 1
+    for i, result := range results {
+        if errs[i] != nil {
+            entry.Warnings = append(entry.Warnings, fmt.Sprintf("agent %s unavailable: %v", um.agents[i].Name, errs[i]))
+            continue
+        }
+        entry.Locations = append(entry.Locations, result)
+        voters++
+        if !result.Success {
+            down++
+        }
+    }
+    if down*2 > voters {
+        return true
+    }
+    entry.Warnings = append(entry.Warnings, fmt.Sprintf("down from only %d of %d locations, not opening a downtime", down, voters))
+    return false
+}
+
+// HandleAgentCheck serves the agent side of the protocol: it checks the posted monitor
+// configuration once and returns an AgentResult without recording anything
+func (um *UptimeMonitor) HandleAgentCheck(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodPost {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    var req MonitorConfig
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeError(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    monitor := req.ToMonitor()
+    if err := normalizeMonitor(&monitor); err != nil {
+        writeError(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+
+    json.NewEncoder(w).Encode(um.agentCheck(monitor))
+}
@@ -0,0 +1,127 @@
+package entity
+
+import (
+    "encoding/json"
+    "io"
+    "net"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+// startAgent serves an agent's /agent/check endpoint and records the configs it was sent
+func startAgent(t *testing.T) (RemoteAgent, *[]MonitorConfig) {
+    agent := NewUptimeMonitor()
+    var received []MonitorConfig
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        body, _ := io.ReadAll(r.Body)
+        var config MonitorConfig
+        json.Unmarshal(body, &config)
+        received = append(received, config)
+        r.Body = io.NopCloser(strings.NewReader(string(body)))
+        agent.HandleAgentCheck(w, r)
+    }))
+    t.Cleanup(server.Close)
+    return RemoteAgent{Name: "remote", URL: server.URL}, &received
+}
+
+func TestAskAgentRedactsCredentials(t *testing.T) {
+    target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Header.Get("Authorization") != "Bearer s3cret" {
+            w.WriteHeader(http.StatusUnauthorized)
+        }
+    }))
+    defer target.Close()
+    agent, received := startAgent(t)
+
+    monitor := Monitor{URL: target.URL + "/", BearerToken: "s3cret", Headers: map[string]string{"X-Api-Key": "k3y"}}
+    if err := normalizeMonitor(&monitor); err != nil {
+        t.Fatal(err)
+    }
+    result, err := askAgent(http.DefaultClient, agent, monitor)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(*received) != 1 {
+        t.Fatalf("agent received %d requests", len(*received))
+    }
+    config := (*received)[0]
+    if config.BearerToken != redactedSecret || config.Headers["X-Api-Key"] != redactedSecret {
+        t.Errorf("agent received credentials: %+v", config)
+    }
+    // The target answered, only the withheld token was missing
+    if !result.Success || result.StatusCode != http.StatusUnauthorized || result.Location != "remote" {
+        t.Errorf("result = %+v, want the authentication challenge to count as up", result)
+    }
+}
+
+func TestAgentCheckAppliesAssertions(t *testing.T) {
+    target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        io.WriteString(w, "maintenance")
+    }))
+    defer target.Close()
+    agent, _ := startAgent(t)
+
+    monitor := Monitor{URL: target.URL + "/", ExpectedBody: "welcome"}
+    if err := normalizeMonitor(&monitor); err != nil {
+        t.Fatal(err)
+    }
+    result, err := askAgent(http.DefaultClient, agent, monitor)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if result.Success || !strings.Contains(result.Error, "welcome") {
+        t.Errorf("result = %+v, want the body assertion to fail", result)
+    }
+}
+
+func TestAgentCheckDispatchesByType(t *testing.T) {
+    listener, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer listener.Close()
+    go func() {
+        for {
+            conn, err := listener.Accept()
+            if err != nil {
+                return
+            }
+            conn.Close()
+        }
+    }()
+    agent, _ := startAgent(t)
+
+    monitor := Monitor{URL: "tcp://" + listener.Addr().String(), Type: MonitorTypeTCP}
+    if err := normalizeMonitor(&monitor); err != nil {
+        t.Fatal(err)
+    }
+    result, err := askAgent(http.DefaultClient, agent, monitor)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !result.Success {
+        t.Errorf("TCP monitor is down from the agent: %+v", result)
+    }
+}
+
+func TestConfirmFailureMajority(t *testing.T) {
+    up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+    defer up.Close()
+    first, _ := startAgent(t)
+    second, _ := startAgent(t)
+    um := NewUptimeMonitor(WithRemoteAgents(first, second, RemoteAgent{Name: "gone", URL: "http://127.0.0.1:1"}))
+
+    monitor := Monitor{URL: up.URL + "/", ConfirmWithAgents: true}
+    if err := normalizeMonitor(&monitor); err != nil {
+        t.Fatal(err)
+    }
+    entry := LogEntry{URL: monitor.URL}
+    if um.confirmFailure(monitor, &entry) {
+        t.Error("failure confirmed although both reachable agents see the URL up")
+    }
+    if len(entry.Locations) != 2 || len(entry.Warnings) != 2 {
+        t.Errorf("entry = %+v, want two locations, the unavailable agent and the vote as warnings", entry)
+    }
+}
@@ -0,0 +1,25 @@
+package entity
+
+import (
+    "encoding/json"
+    "net/http"
+    "strings"
+)
+
+// apiError is the body of every error response of the HTTP API
+type apiError struct {
+    Error string `json:"error"`
+    Code  string `json:"code"` // snake_case status text, e.g. "bad_request"
+}
+
+// writeError replies with a JSON error body; it mirrors http.Error's signature
+func writeError(w http.ResponseWriter, message string, status int) {
+    w.Header().Set("Content-Type", "application/json")
+    w.Header().Set("X-Content-Type-Options", "nosniff")
+    w.WriteHeader(status)
+    json.NewEncoder(w).Encode(apiError{Error: message, Code: errorCode(status)})
+}
+
+func errorCode(status int) string {
+    return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
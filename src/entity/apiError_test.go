@@ -0,0 +1,67 @@
+package entity
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+)
+
+func TestWriteError(t *testing.T) {
+    rec := httptest.NewRecorder()
+    writeError(rec, "URL parameter is required", http.StatusBadRequest)
+
+    if rec.Code != http.StatusBadRequest {
+        t.Errorf("status = %d, want 400", rec.Code)
+    }
+    if got := rec.Header().Get("Content-Type"); got != "application/json" {
+        t.Errorf("Content-Type = %q, want application/json", got)
+    }
+    var body apiError
+    if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+        t.Fatal(err)
+    }
+    if body.Error != "URL parameter is required" || body.Code != "bad_request" {
+        t.Errorf("body = %+v", body)
+    }
+}
+
+func TestErrorCode(t *testing.T) {
+    tests := map[int]string{
+        http.StatusNotFound:            "not_found",
+        http.StatusMethodNotAllowed:    "method_not_allowed",
+        http.StatusServiceUnavailable:  "service_unavailable",
+        http.StatusInternalServerError: "internal_server_error",
+    }
+    for status, want := range tests {
+        if got := errorCode(status); got != want {
+            t.Errorf("errorCode(%d) = %q, want %q", status, got, want)
+        }
+    }
+}
+
+func TestHandlersReturnJSONErrors(t *testing.T) {
+    um := NewUptimeMonitor()
+    tests := []struct {
+        name    string
+        handler http.HandlerFunc
+        method  string
+        target  string
+        status  int
+    }{
+        {"wrong method", um.HandleGetLogs, http.MethodPost, "/monitor/logs?url=x", http.StatusMethodNotAllowed},
+        {"missing url", um.HandleGetStats, http.MethodGet, "/monitor/stats", http.StatusBadRequest},
+        {"unknown monitor", um.HandleRemoveMonitor, http.MethodDelete, "/monitor/remove?url=https://example.com/", http.StatusNotFound},
+    }
+    for _, tt := range tests {
+        rec := httptest.NewRecorder()
+        tt.handler(rec, httptest.NewRequest(tt.method, tt.target, nil))
+        var body apiError
+        if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || body.Error == "" {
+            t.Errorf("%s: body is not a JSON error: %v", tt.name, err)
+        }
+        if rec.Code != tt.status || body.Code != errorCode(tt.status) {
+            t.Errorf("%s: status %d code %q, want %d", tt.name, rec.Code, body.Code, tt.status)
+        }
+    }
+}
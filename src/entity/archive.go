@@ -0,0 +1,95 @@
+package entity
+
+import (
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "sort"
+)
+
+// ArchiveMonitor stops checking url and frees its goroutine, but keeps its configuration,
+// logs and downtimes so it can be revived with UnarchiveMonitor
+func (um *UptimeMonitor) ArchiveMonitor(url string) error {
+    url = monitorKey(url)
+
+    um.mu.Lock()
+    defer um.mu.Unlock()
+
+    monitor, exists := um.monitors[url]
+    if !exists {
+        return fmt.Errorf("URL %s is not being monitored", url)
+    }
+    if err := um.removeLocked(url); err != nil {
+        return err
+    }
+    um.archived[url] = monitor
+    return nil
+}
+
+// UnarchiveMonitor resumes checks of an archived monitor with its previous configuration
+func (um *UptimeMonitor) UnarchiveMonitor(url string) error {
+    url = monitorKey(url)
+
+    um.mu.Lock()
+    defer um.mu.Unlock()
+
+    monitor, archived := um.archived[url]
+    if !archived {
+        return fmt.Errorf("URL %s is not archived", url)
+    }
+    delete(um.archived, url)
+    um.startLocked(monitor)
+    return nil
+}
+
+// GetArchivedMonitors returns the archived monitors sorted by URL
+func (um *UptimeMonitor) GetArchivedMonitors() []Monitor {
+    um.mu.RLock()
+    defer um.mu.RUnlock()
+
+    monitors := make([]Monitor, 0, len(um.archived))
+    for _, monitor := range um.archived {
+        monitors = append(monitors, monitor)
+    }
+    sort.Slice(monitors, func(i, j int) bool {
+        return monitors[i].URL < monitors[j].URL
+    })
+    return monitors
+}
+
+func (um *UptimeMonitor) HandleArchiveMonitor(w http.ResponseWriter, r *http.Request) {
+    um.handleArchiveAction(w, r, um.ArchiveMonitor)
+}
+
+func (um *UptimeMonitor) HandleUnarchiveMonitor(w http.ResponseWriter, r *http.Request) {
+    um.handleArchiveAction(w, r, um.UnarchiveMonitor)
+}
+
+func (um *UptimeMonitor) handleArchiveAction(w http.ResponseWriter, r *http.Request, action func(string) error) {
+    if r.Method != http.MethodPost {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    url := r.URL.Query().Get("url")
+    if url == "" {
+        writeError(w, "URL parameter is required", http.StatusBadRequest)
+        return
+    }
+
+    if err := action(url); err != nil {
+        writeError(w, err.Error(), http.StatusNotFound)
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+}
+
+func (um *UptimeMonitor) HandleGetArchivedMonitors(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    json.NewEncoder(w).Encode(um.GetArchivedMonitors())
+}
@@ -0,0 +1,45 @@
+package entity
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+)
+
+func TestListMonitorsFlagsArchived(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+    defer server.Close()
+
+    um := NewUptimeMonitor()
+    for _, path := range []string{"/b-active", "/a-archived"} {
+        if err := um.AddMonitor(server.URL+path, time.Hour); err != nil {
+            t.Fatal(err)
+        }
+    }
+    defer um.RemoveMonitor(server.URL + "/b-active")
+    if err := um.ArchiveMonitor(server.URL + "/a-archived"); err != nil {
+        t.Fatal(err)
+    }
+
+    rec := httptest.NewRecorder()
+    um.HandleListMonitors(rec, httptest.NewRequest(http.MethodGet, "/monitor/list", nil))
+    var configs []MonitorConfig
+    if err := json.NewDecoder(rec.Body).Decode(&configs); err != nil {
+        t.Fatal(err)
+    }
+
+    if len(configs) != 2 {
+        t.Fatalf("listed %d monitors, want 2: %+v", len(configs), configs)
+    }
+    if configs[0].URL != server.URL+"/a-archived" || !configs[0].Archived {
+        t.Errorf("first entry = %s archived=%v, want the archived monitor flagged", configs[0].URL, configs[0].Archived)
+    }
+    if configs[1].URL != server.URL+"/b-active" || configs[1].Archived {
+        t.Errorf("second entry = %s archived=%v, want the active monitor unflagged", configs[1].URL, configs[1].Archived)
+    }
+    if configs[0].Interval != ConfigDuration(time.Hour) {
+        t.Errorf("archived interval = %v, want the kept configuration", time.Duration(configs[0].Interval))
+    }
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+    "fmt"
+    "net/http"
+)
+
+// validateAuth allows at most one way of authenticating a monitor's requests
+func validateAuth(monitor Monitor) error {
+    basic := monitor.BasicAuthUser != "" || monitor.BasicAuthPass != ""
+    if basic && monitor.BasicAuthUser == "" {
+        return fmt.Errorf("basicAuthPass requires basicAuthUser")
+    }
+    if basic && monitor.BearerToken != "" {
+        return fmt.Errorf("basicAuthUser and bearerToken are mutually exclusive")
+    }
+    if (basic || monitor.BearerToken != "") && monitor.SigV4 != nil {
+        return fmt.Errorf("sigv4 cannot be combined with basic auth or a bearer token")
+    }
+    return nil
+}
+
+// setAuth adds the monitor's credentials to the request's Authorization header
+func setAuth(req *http.Request, monitor Monitor) {
+    switch {
+    case monitor.BasicAuthUser != "":
+        req.SetBasicAuth(monitor.BasicAuthUser, monitor.BasicAuthPass)
+    case monitor.BearerToken != "":
+        req.Header.Set("Authorization", "Bearer "+monitor.BearerToken)
+    }
+}
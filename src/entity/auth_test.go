@@ -0,0 +1,98 @@
+package entity
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestValidateAuth(t *testing.T) {
+    sigV4 := &SigV4Config{AccessKeyID: "id", SecretAccessKey: "secret", Region: "us-east-1", Service: "execute-api"}
+    tests := []struct {
+        name    string
+        monitor Monitor
+        wantErr bool
+    }{
+        {name: "none"},
+        {name: "basic", monitor: Monitor{BasicAuthUser: "user", BasicAuthPass: "pass"}},
+        {name: "basic without password", monitor: Monitor{BasicAuthUser: "user"}},
+        {name: "bearer", monitor: Monitor{BearerToken: "token"}},
+        {name: "sigv4", monitor: Monitor{SigV4: sigV4}},
+        {name: "password without user", monitor: Monitor{BasicAuthPass: "pass"}, wantErr: true},
+        {name: "basic and bearer", monitor: Monitor{BasicAuthUser: "user", BearerToken: "token"}, wantErr: true},
+        {name: "bearer and sigv4", monitor: Monitor{BearerToken: "token", SigV4: sigV4}, wantErr: true},
+        {name: "basic and sigv4", monitor: Monitor{BasicAuthUser: "user", SigV4: sigV4}, wantErr: true},
+    }
+    for _, tt := range tests {
+        if err := validateAuth(tt.monitor); (err != nil) != tt.wantErr {
+            t.Errorf("%s: validateAuth() = %v, want error %v", tt.name, err, tt.wantErr)
+        }
+    }
+}
+
+func TestCheckSendsCredentials(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        user, pass, ok := r.BasicAuth()
+        switch {
+        case ok && user == "user" && pass == "pass":
+        case r.Header.Get("Authorization") == "Bearer token":
+        default:
+            w.WriteHeader(http.StatusUnauthorized)
+        }
+    }))
+    defer server.Close()
+
+    um := NewUptimeMonitor()
+    tests := []struct {
+        name    string
+        monitor Monitor
+        success bool
+    }{
+        {"basic", Monitor{BasicAuthUser: "user", BasicAuthPass: "pass"}, true},
+        {"bearer", Monitor{BearerToken: "token"}, true},
+        {"wrong password", Monitor{BasicAuthUser: "user", BasicAuthPass: "guess"}, false},
+        {"anonymous", Monitor{}, false},
+    }
+    for _, tt := range tests {
+        monitor := tt.monitor
+        monitor.URL = server.URL + "/"
+        monitor.Interval = time.Minute
+        if err := normalizeMonitor(&monitor); err != nil {
+            t.Fatalf("%s: %v", tt.name, err)
+        }
+        if entry := um.attemptCheck(context.Background(), monitor); entry.Success != tt.success {
+            t.Errorf("%s: success = %v (%d %s), want %v", tt.name, entry.Success, entry.StatusCode, entry.Error, tt.success)
+        }
+    }
+}
+
+func TestListMonitorsRedactsCredentials(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+    defer server.Close()
+
+    um := NewUptimeMonitor()
+    active := Monitor{URL: server.URL + "/active", Interval: time.Hour, BasicAuthUser: "user", BasicAuthPass: "hunter2"}
+    archived := Monitor{URL: server.URL + "/archived", Interval: time.Hour, BearerToken: "s3cr3t-token"}
+    for _, monitor := range []Monitor{active, archived} {
+        if err := um.AddMonitorConfig(monitor); err != nil {
+            t.Fatal(err)
+        }
+    }
+    defer um.RemoveMonitor(active.URL)
+    if err := um.ArchiveMonitor(archived.URL); err != nil {
+        t.Fatal(err)
+    }
+
+    rec := httptest.NewRecorder()
+    um.HandleListMonitors(rec, httptest.NewRequest(http.MethodGet, "/monitor/list", nil))
+    body := rec.Body.String()
+    if strings.Contains(body, "hunter2") || strings.Contains(body, "s3cr3t-token") {
+        t.Fatalf("list exposes credentials: %s", body)
+    }
+    if strings.Count(body, redactedSecret) != 2 {
+        t.Errorf("list = %s, want both credentials redacted", body)
+    }
+}
@@ -0,0 +1,25 @@
+package entity
+
+import (
+    "errors"
+    "time"
+)
+
+// ErrSaturated is returned by AddMonitor while too many checks are in flight to take on more monitors
+var ErrSaturated = errors.New("too many checks in flight, retry later")
+
+// saturatedRetryAfter is the pause suggested to clients turned away by ErrSaturated
+const saturatedRetryAfter = 5 * time.Second
+
+// WithMaxInFlight rejects new monitors with ErrSaturated while n or more checks are running,
+// protecting a busy instance during bulk imports; zero disables the guard
+func WithMaxInFlight(n int) Option {
+    return func(um *UptimeMonitor) {
+        um.maxInFlight = n
+    }
+}
+
+// saturatedLocked reports whether new monitors must wait for in-flight checks; callers must hold um.mu
+func (um *UptimeMonitor) saturatedLocked() bool {
+    return um.maxInFlight > 0 && um.inFlight >= um.maxInFlight
+}
@@ -0,0 +1,73 @@
+package entity
+
+import (
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestAddMonitorRejectedWhileSaturated(t *testing.T) {
+    um := NewUptimeMonitor(WithMaxInFlight(2))
+    doneA := um.beginCheck("https://a.example.com/")
+    if err := um.AddMonitor("https://new.example.com/", time.Hour); err != nil {
+        t.Fatalf("one check in flight: %v", err)
+    }
+    defer um.RemoveMonitor("https://new.example.com/")
+
+    um.beginCheck("https://b.example.com/")
+    if err := um.AddMonitor("https://other.example.com/", time.Hour); !errors.Is(err, ErrSaturated) {
+        t.Fatalf("two checks in flight: err = %v, want ErrSaturated", err)
+    }
+
+    rec := httptest.NewRecorder()
+    body := strings.NewReader(`{"url": "https://other.example.com/", "interval": 60}`)
+    um.HandleAddMonitor(rec, httptest.NewRequest(http.MethodPost, "/monitor/add", body))
+    if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") != "5" {
+        t.Errorf("add endpoint answered %d with Retry-After %q, want 503 and 5", rec.Code, rec.Header().Get("Retry-After"))
+    }
+
+    doneA()
+    if err := um.AddMonitor("https://other.example.com/", time.Hour); err != nil {
+        t.Errorf("after a check finished: %v", err)
+    }
+    um.RemoveMonitor("https://other.example.com/")
+}
+
+func TestMaxInFlightDisabled(t *testing.T) {
+    um := NewUptimeMonitor()
+    for _, url := range []string{"https://a.example.com/", "https://b.example.com/", "https://c.example.com/"} {
+        um.beginCheck(url)
+    }
+    if err := um.AddMonitor("https://new.example.com/", time.Hour); err != nil {
+        t.Errorf("without a limit: %v", err)
+    }
+    um.RemoveMonitor("https://new.example.com/")
+}
+
+func TestOverlappingChecksCountedSeparately(t *testing.T) {
+    const url = "https://a.example.com/"
+    um := NewUptimeMonitor(WithMaxInFlight(2))
+    um.monitors[url] = Monitor{URL: url}
+
+    // A check-all run overlapping a scheduled check of the same URL
+    scheduled := um.beginCheck(url)
+    onDemand := um.beginCheck(url)
+    if err := um.AddMonitor("https://new.example.com/", time.Hour); !errors.Is(err, ErrSaturated) {
+        t.Fatalf("two checks of one URL in flight: err = %v, want ErrSaturated", err)
+    }
+
+    onDemand()
+    if m := um.GetDiagnostics().Monitors[0]; m.State != "checking" || m.InFlight != 1 {
+        t.Errorf("after one check finished: state %q with %d in flight, want the other still checking", m.State, m.InFlight)
+    }
+    scheduled()
+    if m := um.GetDiagnostics().Monitors[0]; m.State != "idle" || m.InFlight != 0 {
+        t.Errorf("after both finished: state %q with %d in flight, want idle", m.State, m.InFlight)
+    }
+    if len(um.checking) != 0 || um.inFlight != 0 {
+        t.Errorf("%d URLs and %d checks still counted", len(um.checking), um.inFlight)
+    }
+}
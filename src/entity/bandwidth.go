@@ -0,0 +1,76 @@
+package entity
+
+import (
+    "encoding/json"
+    "net/http"
+    "sort"
+)
+
+// headerSize approximates the wire size of header lines, "Name: value\r\n" each
+func headerSize(header http.Header) int64 {
+    var size int64
+    for name, values := range header {
+        for _, value := range values {
+            size += int64(len(name) + len(value) + 4)
+        }
+    }
+    return size
+}
+
+// requestSize approximates the bytes sent for req: request line, headers and body
+func requestSize(req *http.Request) int64 {
+    size := int64(len(req.Method)+len(req.URL.RequestURI())+len(req.Proto)+4) + headerSize(req.Header)
+    host := req.Host
+    if host == "" {
+        host = req.URL.Host
+    }
+    size += int64(len("Host: ")+len(host)+2) + 2
+    if req.ContentLength > 0 {
+        size += req.ContentLength
+    }
+    return size
+}
+
+// responseSize approximates the bytes received for resp: status line, headers and the bytes read of its body
+func responseSize(resp *http.Response, bodyBytes int64) int64 {
+    return int64(len(resp.Proto)+len(resp.Status)+3) + headerSize(resp.Header) + 2 + bodyBytes
+}
+
+// Bandwidth totals the approximate bytes exchanged by checks
+type Bandwidth struct {
+    URL           string `json:"url,omitempty"`
+    BytesSent     int64  `json:"bytesSent"`
+    BytesReceived int64  `json:"bytesReceived"`
+}
+
+// BandwidthReport breaks the monitoring traffic down per URL
+type BandwidthReport struct {
+    Total    Bandwidth   `json:"total"`
+    Monitors []Bandwidth `json:"monitors"`
+}
+
+// GetBandwidth reports the traffic of every URL checked so far, sorted by URL
+func (um *UptimeMonitor) GetBandwidth() BandwidthReport {
+    um.mu.RLock()
+    defer um.mu.RUnlock()
+
+    report := BandwidthReport{Monitors: make([]Bandwidth, 0, len(um.states))}
+    for url, state := range um.states {
+        report.Monitors = append(report.Monitors, Bandwidth{URL: url, BytesSent: state.bytesSent, BytesReceived: state.bytesReceived})
+        report.Total.BytesSent += state.bytesSent
+        report.Total.BytesReceived += state.bytesReceived
+    }
+    sort.Slice(report.Monitors, func(i, j int) bool {
+        return report.Monitors[i].URL < report.Monitors[j].URL
+    })
+    return report
+}
+
+func (um *UptimeMonitor) HandleGetBandwidth(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    json.NewEncoder(w).Encode(um.GetBandwidth())
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+func TestRequestAndResponseSize(t *testing.T) {
+    req := httptest.NewRequest(http.MethodGet, "http://example.com/path", nil)
+    req.Header = http.Header{"Accept": {"x"}}
+    // "GET /path HTTP/1.1\r\n" + "Accept: x\r\n" + "Host: example.com\r\n" + "\r\n"
+    if got, want := requestSize(req), int64(20+11+19+2); got != want {
+        t.Errorf("requestSize = %d, want %d", got, want)
+    }
+
+    req = httptest.NewRequest(http.MethodPost, "http://example.com/", strings.NewReader("hello"))
+    req.Header = http.Header{}
+    if got, want := requestSize(req), int64(len("POST / HTTP/1.1\r\n")+len("Host: example.com\r\n")+2+5); got != want {
+        t.Errorf("requestSize with body = %d, want %d", got, want)
+    }
+
+    resp := &http.Response{Proto: "HTTP/1.1", Status: "200 OK", Header: http.Header{"Content-Length": {"5"}}}
+    // "HTTP/1.1 200 OK\r\n" + "Content-Length: 5\r\n" + "\r\n" + body
+    if got, want := responseSize(resp, 5), int64(17+19+2+5); got != want {
+        t.Errorf("responseSize = %d, want %d", got, want)
+    }
+}
+
+func TestGetBandwidthTotalsChecks(t *testing.T) {
+    body := strings.Repeat("x", 1000)
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Write([]byte(body))
+    }))
+    defer server.Close()
+
+    um := NewUptimeMonitor()
+    var sent, received int64
+    for _, path := range []string{"/a", "/b", "/a"} {
+        entry := um.checkURL(Monitor{URL: server.URL + path})
+        if !entry.Success {
+            t.Fatalf("check of %s failed: %s", path, entry.Error)
+        }
+        if entry.BytesSent <= 0 || entry.BytesReceived <= int64(len(body)) {
+            t.Errorf("check of %s recorded %d bytes sent and %d received", path, entry.BytesSent, entry.BytesReceived)
+        }
+        sent += entry.BytesSent
+        received += entry.BytesReceived
+    }
+
+    report := um.GetBandwidth()
+    if report.Total.BytesSent != sent || report.Total.BytesReceived != received {
+        t.Errorf("total = %+v, want %d sent and %d received", report.Total, sent, received)
+    }
+    if len(report.Monitors) != 2 || report.Monitors[0].URL != server.URL+"/a" {
+        t.Fatalf("monitors = %+v, want /a and /b sorted", report.Monitors)
+    }
+    if a, b := report.Monitors[0], report.Monitors[1]; a.BytesReceived <= b.BytesReceived {
+        t.Errorf("/a checked twice received %d bytes, /b once %d", a.BytesReceived, b.BytesReceived)
+    }
+}
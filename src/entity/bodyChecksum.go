@@ -0,0 +1,33 @@
+package entity
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "fmt"
+    "strings"
+)
+
+// validateBodyChecksum checks that the expected checksum is a hex SHA-256 digest
+func validateBodyChecksum(monitor Monitor) error {
+    if monitor.BodySHA256 == "" {
+        return nil
+    }
+    if digest, err := hex.DecodeString(monitor.BodySHA256); err != nil || len(digest) != sha256.Size {
+        return fmt.Errorf("bodySha256 %q must be a hex SHA-256 digest", monitor.BodySHA256)
+    }
+    return nil
+}
+
+// checkBodyChecksum records the body's SHA-256 and fails when it differs from the expected one.
+// Only the first maxBodyBytes of the body are hashed.
+func checkBodyChecksum(monitor Monitor, body []byte, entry *LogEntry) error {
+    if monitor.BodySHA256 == "" {
+        return nil
+    }
+    sum := sha256.Sum256(body)
+    entry.BodySHA256 = hex.EncodeToString(sum[:])
+    if !strings.EqualFold(entry.BodySHA256, monitor.BodySHA256) {
+        return fmt.Errorf("body SHA-256 %s does not match the expected %s", entry.BodySHA256, strings.ToLower(monitor.BodySHA256))
+    }
+    return nil
+}
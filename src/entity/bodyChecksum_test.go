@@ -0,0 +1,58 @@
+package entity
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+func TestValidateBodyChecksum(t *testing.T) {
+    valid := strings.Repeat("ab", sha256.Size)
+    tests := map[string]bool{
+        "":                      true,
+        valid:                   true,
+        strings.ToUpper(valid):  true,
+        valid[:62]:              false,
+        "not-hex":               false,
+        strings.Repeat("g", 64): false,
+    }
+    for checksum, ok := range tests {
+        if err := validateBodyChecksum(Monitor{BodySHA256: checksum}); (err == nil) != ok {
+            t.Errorf("validateBodyChecksum(%q) = %v, want ok %v", checksum, err, ok)
+        }
+    }
+}
+
+func TestCheckBodyChecksum(t *testing.T) {
+    const body = "release 1.2.3"
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Write([]byte(body))
+    }))
+    defer server.Close()
+
+    sum := sha256.Sum256([]byte(body))
+    digest := hex.EncodeToString(sum[:])
+    other := sha256.Sum256([]byte("release 1.2.4"))
+
+    um := NewUptimeMonitor()
+    tests := []struct {
+        expected string
+        success  bool
+    }{
+        {digest, true},
+        {strings.ToUpper(digest), true},
+        {hex.EncodeToString(other[:]), false},
+    }
+    for _, tt := range tests {
+        entry := um.checkURL(Monitor{URL: server.URL + "/", BodySHA256: tt.expected})
+        if entry.Success != tt.success {
+            t.Errorf("expected %s: success = %v (%s), want %v", tt.expected, entry.Success, entry.Error, tt.success)
+        }
+        if entry.BodySHA256 != digest {
+            t.Errorf("recorded checksum %q, want %s", entry.BodySHA256, digest)
+        }
+    }
+}
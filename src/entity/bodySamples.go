@@ -0,0 +1,207 @@
+package entity
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "log/slog"
+    "net/http"
+    "os"
+    "path/filepath"
+    "sort"
+    "sync"
+    "time"
+)
+
+// BodySample is a response body captured for post-incident forensics
+type BodySample struct {
+    URL        string    `json:"url"`
+    Timestamp  time.Time `json:"timestamp"`
+    StatusCode int       `json:"statusCode"`
+    Body       []byte    `json:"body"` // base64 in JSON
+}
+
+// sampleFile indexes a stored sample without keeping its body in memory
+type sampleFile struct {
+    url       string
+    timestamp time.Time
+    path      string
+    size      int64
+}
+
+// bodySampleStore keeps at most one sample per URL per interval as files in dir,
+// deleting the oldest once their total size exceeds maxBytes
+type bodySampleStore struct {
+    mu       sync.Mutex
+    dir      string
+    every    time.Duration
+    maxBytes int64
+    total    int64
+    files    []sampleFile // oldest first
+    last     map[string]time.Time
+}
+
+func newBodySampleStore(dir string, every time.Duration, maxBytes int64) (*bodySampleStore, error) {
+    if err := os.MkdirAll(dir, 0o700); err != nil {
+        return nil, err
+    }
+    store := &bodySampleStore{dir: dir, every: every, maxBytes: maxBytes, last: make(map[string]time.Time)}
+
+    // Index samples kept from previous runs
+    paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+    if err != nil {
+        return nil, err
+    }
+    for _, path := range paths {
+        sample, err := readBodySample(path)
+        if err != nil {
+            slog.Warn("Skipping unreadable body sample", "path", path, "error", err)
+            continue
+        }
+        info, err := os.Stat(path)
+        if err != nil {
+            continue
+        }
+        store.files = append(store.files, sampleFile{url: sample.URL, timestamp: sample.Timestamp, path: path, size: info.Size()})
+        store.total += info.Size()
+        if sample.Timestamp.After(store.last[sample.URL]) {
+            store.last[sample.URL] = sample.Timestamp
+        }
+    }
+    sort.Slice(store.files, func(i, j int) bool {
+        return store.files[i].timestamp.Before(store.files[j].timestamp)
+    })
+    store.rotateLocked()
+    return store, nil
+}
+
+// WithBodySampling stores one response body per URL every interval under dir, bounded to maxBytes in total
+func WithBodySampling(dir string, every time.Duration, maxBytes int64) Option {
+    return func(um *UptimeMonitor) {
+        store, err := newBodySampleStore(dir, every, maxBytes)
+        if err != nil {
+            slog.Error("Body sampling disabled", "error", err)
+            return
+        }
+        um.samples = store
+    }
+}
+
+func readBodySample(path string) (BodySample, error) {
+    var sample BodySample
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return sample, err
+    }
+    err = json.Unmarshal(data, &sample)
+    return sample, err
+}
+
+// record stores the body when the URL's previous sample is at least one interval old
+func (s *bodySampleStore) record(url string, statusCode int, body []byte, timestamp time.Time) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if last, ok := s.last[url]; ok && timestamp.Sub(last) < s.every {
+        return
+    }
+    s.last[url] = timestamp
+
+    data, err := json.Marshal(BodySample{URL: url, Timestamp: timestamp, StatusCode: statusCode, Body: body})
+    if err != nil {
+        return
+    }
+    sum := sha256.Sum256([]byte(url))
+    path := filepath.Join(s.dir, fmt.Sprintf("%s-%d.json", hex.EncodeToString(sum[:8]), timestamp.UnixNano()))
+    if err := os.WriteFile(path, data, 0o600); err != nil {
+        slog.Warn("Failed to store body sample", "url", url, "error", err)
+        return
+    }
+
+    s.files = append(s.files, sampleFile{url: url, timestamp: timestamp, path: path, size: int64(len(data))})
+    s.total += int64(len(data))
+    s.rotateLocked()
+}
+
+// rotateLocked deletes the oldest samples until the store fits its size limit
+func (s *bodySampleStore) rotateLocked() {
+    for s.total > s.maxBytes && len(s.files) > 0 {
+        oldest := s.files[0]
+        if err := os.Remove(oldest.path); err != nil && !os.IsNotExist(err) {
+            slog.Warn("Failed to rotate body sample", "path", oldest.path, "error", err)
+        }
+        s.total -= oldest.size
+        s.files = s.files[1:]
+    }
+}
+
+// query returns the samples of url taken within [since, until]; zero bounds are open
+func (s *bodySampleStore) query(url string, since, until time.Time) ([]BodySample, error) {
+    s.mu.Lock()
+    var matches []sampleFile
+    for _, file := range s.files {
+        if file.url != url || (!since.IsZero() && file.timestamp.Before(since)) || (!until.IsZero() && file.timestamp.After(until)) {
+            continue
+        }
+        matches = append(matches, file)
+    }
+    s.mu.Unlock()
+
+    samples := make([]BodySample, 0, len(matches))
+    for _, file := range matches {
+        sample, err := readBodySample(file.path)
+        if os.IsNotExist(err) {
+            continue // rotated away meanwhile
+        }
+        if err != nil {
+            return nil, err
+        }
+        samples = append(samples, sample)
+    }
+    return samples, nil
+}
+
+// GetBodySamples returns the stored body samples of url taken within [since, until]
+func (um *UptimeMonitor) GetBodySamples(url string, since, until time.Time) ([]BodySample, error) {
+    url = monitorKey(url)
+    if um.samples == nil {
+        return nil, fmt.Errorf("body sampling is not enabled")
+    }
+    return um.samples.query(url, since, until)
+}
+
+func (um *UptimeMonitor) HandleGetBodySamples(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    url := r.URL.Query().Get("url")
+    if url == "" {
+        writeError(w, "URL parameter is required", http.StatusBadRequest)
+        return
+    }
+
+    since, err := parseTimeParam(r, "since")
+    if err != nil {
+        writeError(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    until, err := parseTimeParam(r, "until")
+    if err != nil {
+        writeError(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+
+    if um.samples == nil {
+        writeError(w, "Body sampling is not enabled", http.StatusNotFound)
+        return
+    }
+    samples, err := um.GetBodySamples(url, since, until)
+    if err != nil {
+        writeError(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    json.NewEncoder(w).Encode(samples)
+}
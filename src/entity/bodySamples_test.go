@@ -0,0 +1,91 @@
+package entity
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+)
+
+func TestBodySampleStore(t *testing.T) {
+    dir := t.TempDir()
+    store, err := newBodySampleStore(dir, time.Minute, 1<<20)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    const url = "https://example.com/"
+    start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    store.record(url, 200, []byte("first"), start)
+    store.record(url, 500, []byte("too soon"), start.Add(30*time.Second))
+    store.record(url, 503, []byte("second"), start.Add(time.Minute))
+    store.record("https://other.example.com/", 200, []byte("other"), start)
+
+    samples, err := store.query(url, time.Time{}, time.Time{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(samples) != 2 || string(samples[0].Body) != "first" || samples[1].StatusCode != 503 {
+        t.Fatalf("samples = %+v, want the first and the one a minute later", samples)
+    }
+
+    samples, _ = store.query(url, start.Add(time.Second), time.Time{})
+    if len(samples) != 1 || string(samples[0].Body) != "second" {
+        t.Errorf("samples since +1s = %+v, want only the second", samples)
+    }
+    samples, _ = store.query(url, time.Time{}, start)
+    if len(samples) != 1 || string(samples[0].Body) != "first" {
+        t.Errorf("samples until the start = %+v, want only the first", samples)
+    }
+
+    // A new store indexes the samples kept by the previous run
+    reopened, err := newBodySampleStore(dir, time.Minute, 1<<20)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if samples, _ := reopened.query(url, time.Time{}, time.Time{}); len(samples) != 2 {
+        t.Errorf("reopened store has %d samples of %s, want 2", len(samples), url)
+    }
+    reopened.record(url, 200, []byte("gated"), start.Add(90*time.Second))
+    if samples, _ := reopened.query(url, time.Time{}, time.Time{}); len(samples) != 2 {
+        t.Errorf("reopened store sampled again within the interval of the last sample")
+    }
+}
+
+func TestBodySampleStoreRotates(t *testing.T) {
+    dir := t.TempDir()
+    store, err := newBodySampleStore(dir, 0, 1100)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    const url = "https://example.com/"
+    start := time.Now()
+    body := make([]byte, 300) // about 500 bytes per file as base64 JSON
+    for i := 0; i < 5; i++ {
+        store.record(url, 200, body, start.Add(time.Duration(i)*time.Second))
+    }
+
+    samples, err := store.query(url, time.Time{}, time.Time{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(samples) != 2 || !samples[1].Timestamp.Equal(start.Add(4*time.Second)) {
+        t.Errorf("kept %d samples, want the newest 2", len(samples))
+    }
+    files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+    var total int64
+    for _, file := range files {
+        info, _ := os.Stat(file)
+        total += info.Size()
+    }
+    if len(files) != 2 || total > 1100 {
+        t.Errorf("%d files of %d bytes on disk, want 2 within the limit", len(files), total)
+    }
+}
+
+func TestGetBodySamplesDisabled(t *testing.T) {
+    if _, err := NewUptimeMonitor().GetBodySamples("https://example.com/", time.Time{}, time.Time{}); err == nil {
+        t.Error("expected an error without body sampling")
+    }
+}
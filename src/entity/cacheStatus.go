@@ -0,0 +1,108 @@
+package entity
+
+import (
+    "fmt"
+    "strings"
+)
+
+// Cache statuses recorded on log entries
+const (
+    CacheHit  = "hit"
+    CacheMiss = "miss"
+)
+
+// cacheHitWindow is the number of recent cache observations the hit rate alert is based on
+const cacheHitWindow = 20
+
+// minCacheSamples avoids alerting on the first few observations after startup
+const minCacheSamples = 10
+
+// defaultCacheHitValues are matched case-insensitively against the cache header, e.g. "HIT, MISS" from chained caches
+var defaultCacheHitValues = []string{"HIT"}
+
+func validateCache(monitor Monitor) error {
+    if monitor.ExpectCache != "" && monitor.ExpectCache != CacheHit && monitor.ExpectCache != CacheMiss {
+        return fmt.Errorf("expectCache must be %q or %q", CacheHit, CacheMiss)
+    }
+    if (monitor.ExpectCache != "" || monitor.MinCacheHitRate > 0) && monitor.CacheHeader == "" {
+        return fmt.Errorf("cache assertions require cacheHeader")
+    }
+    if monitor.MinCacheHitRate < 0 || monitor.MinCacheHitRate > 100 {
+        return fmt.Errorf("minCacheHitRate must be a percentage")
+    }
+    return nil
+}
+
+// cacheStatus classifies the response as a hit or miss from the monitor's cache header.
+// The first value of the header decides, as the edge cache closest to the client lists first.
+func cacheStatus(monitor Monitor, value string) string {
+    if monitor.CacheHeader == "" || value == "" {
+        return ""
+    }
+    first := strings.ToUpper(strings.TrimSpace(strings.Split(value, ",")[0]))
+
+    hitValues := monitor.CacheHitValues
+    if len(hitValues) == 0 {
+        hitValues = defaultCacheHitValues
+    }
+    for _, hit := range hitValues {
+        if strings.Contains(first, strings.ToUpper(hit)) {
+            return CacheHit
+        }
+    }
+    return CacheMiss
+}
+
+// checkCache records the cache status on the entry and enforces ExpectCache
+func checkCache(monitor Monitor, value string, entry *LogEntry) error {
+    entry.CacheStatus = cacheStatus(monitor, value)
+    if monitor.ExpectCache == "" || entry.CacheStatus == monitor.ExpectCache {
+        return nil
+    }
+    if entry.CacheStatus == "" {
+        return fmt.Errorf("response has no %s header", monitor.CacheHeader)
+    }
+    return fmt.Errorf("expected cache %s, got %s (%s: %s)", monitor.ExpectCache, entry.CacheStatus, monitor.CacheHeader, value)
+}
+
+// observeCacheLocked updates the cache counters of a URL and alerts once when the recent hit
+// rate falls below the monitor's threshold; callers must hold um.mu
+func (um *UptimeMonitor) observeCacheLocked(state *urlState, entry LogEntry) {
+    if entry.CacheStatus == "" {
+        return
+    }
+    hit := entry.CacheStatus == CacheHit
+    state.cacheLookups++
+    if hit {
+        state.cacheHits++
+    }
+    state.recentCache = append(state.recentCache, hit)
+    if len(state.recentCache) > cacheHitWindow {
+        state.recentCache = state.recentCache[1:]
+    }
+
+    threshold := um.monitors[entry.URL].MinCacheHitRate
+    if threshold <= 0 || len(state.recentCache) < minCacheSamples {
+        return
+    }
+    var hits int
+    for _, recent := range state.recentCache {
+        if recent {
+            hits++
+        }
+    }
+    rate := float64(hits) / float64(len(state.recentCache)) * 100
+    if rate >= threshold {
+        state.cacheAlerted = false
+        return
+    }
+    if !state.cacheAlerted {
+        state.cacheAlerted = true
+        um.notifyLocked(DowntimeEvent{
+            Type:      EventCacheHitRateLow,
+            URL:       entry.URL,
+            Timestamp: entry.Timestamp,
+            Error:     fmt.Sprintf("cache hit rate %.0f%% over the last %d checks is below %.0f%%", rate, len(state.recentCache), threshold),
+        })
+    }
+}
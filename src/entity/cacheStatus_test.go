@@ -0,0 +1,85 @@
+package entity
+
+import (
+    "testing"
+    "time"
+)
+
+func TestCacheStatus(t *testing.T) {
+    tests := []struct {
+        monitor Monitor
+        value   string
+        want    string
+    }{
+        {Monitor{}, "HIT", ""},
+        {Monitor{CacheHeader: "X-Cache"}, "", ""},
+        {Monitor{CacheHeader: "X-Cache"}, "TCP_HIT", CacheHit},
+        {Monitor{CacheHeader: "X-Cache"}, "hit", CacheHit},
+        {Monitor{CacheHeader: "X-Cache"}, "MISS, HIT", CacheMiss},
+        {Monitor{CacheHeader: "X-Cache"}, " Hit from cloudfront, MISS", CacheHit},
+        {Monitor{CacheHeader: "CF-Cache-Status", CacheHitValues: []string{"HIT", "REVALIDATED"}}, "REVALIDATED", CacheHit},
+        {Monitor{CacheHeader: "CF-Cache-Status", CacheHitValues: []string{"REVALIDATED"}}, "HIT", CacheMiss},
+    }
+    for _, test := range tests {
+        if got := cacheStatus(test.monitor, test.value); got != test.want {
+            t.Errorf("cacheStatus(%+v, %q) = %q, want %q", test.monitor, test.value, got, test.want)
+        }
+    }
+}
+
+func TestCheckCache(t *testing.T) {
+    monitor := Monitor{CacheHeader: "X-Cache", ExpectCache: CacheHit}
+    var entry LogEntry
+    if err := checkCache(monitor, "HIT", &entry); err != nil || entry.CacheStatus != CacheHit {
+        t.Errorf("hit = %v, status %q", err, entry.CacheStatus)
+    }
+    if err := checkCache(monitor, "MISS", &entry); err == nil || entry.CacheStatus != CacheMiss {
+        t.Errorf("miss = %v, status %q, want an error", err, entry.CacheStatus)
+    }
+    if err := checkCache(monitor, "", &entry); err == nil {
+        t.Error("expected an error for a missing cache header")
+    }
+    if err := checkCache(Monitor{CacheHeader: "X-Cache"}, "MISS", &entry); err != nil {
+        t.Errorf("miss without an expectation = %v", err)
+    }
+
+    if err := validateCache(Monitor{ExpectCache: CacheHit}); err == nil {
+        t.Error("expected an error for expectCache without cacheHeader")
+    }
+    if err := validateCache(Monitor{CacheHeader: "X-Cache", ExpectCache: "stale"}); err == nil {
+        t.Error("expected an error for an unknown expectCache")
+    }
+}
+
+func TestCacheHitRateAlert(t *testing.T) {
+    um := NewUptimeMonitor()
+    alerter := newRecordingAlerter()
+    um.AddAlerter(alerter)
+
+    const url = "http://example.com/"
+    um.monitors[url] = Monitor{URL: url, CacheHeader: "X-Cache", MinCacheHitRate: 50}
+    observe := func(status string, count int) {
+        um.mu.Lock()
+        defer um.mu.Unlock()
+        for i := 0; i < count; i++ {
+            um.appendLogLocked(LogEntry{URL: url, Success: true, CacheStatus: status, Timestamp: time.Now()})
+        }
+    }
+
+    // No alert before minCacheSamples observations
+    observe(CacheMiss, minCacheSamples-1)
+    alerter.expectNone(t)
+    observe(CacheMiss, 1)
+    alerter.expect(t, EventCacheHitRateLow)
+    observe(CacheMiss, 5)
+    alerter.expectNone(t)
+
+    // Once the rate recovers, the next drop alerts again
+    observe(CacheHit, cacheHitWindow)
+    observe(CacheMiss, cacheHitWindow/2+1)
+    alerter.expect(t, EventCacheHitRateLow)
+
+    if stats := um.GetStats(url); stats.CacheHitRate != float64(cacheHitWindow)/float64(minCacheSamples+5+cacheHitWindow+cacheHitWindow/2+1)*100 {
+        t.Errorf("cache hit rate = %v", stats.CacheHitRate)
+    }
+}
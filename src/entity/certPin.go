@@ -0,0 +1,60 @@
+package entity
+
+import (
+    "bytes"
+    "crypto/sha256"
+    "crypto/tls"
+    "encoding/base64"
+    "encoding/hex"
+    "fmt"
+    "net/http"
+    "strings"
+)
+
+const spkiPinPrefix = "sha256/"
+
+// spkiFingerprint returns the SHA-256 digest of the leaf certificate's public key, in the
+// "sha256/<base64>" form used by HTTP public key pinning
+func spkiFingerprint(state *tls.ConnectionState) string {
+    if state == nil || len(state.PeerCertificates) == 0 {
+        return ""
+    }
+    sum := sha256.Sum256(state.PeerCertificates[0].RawSubjectPublicKeyInfo)
+    return spkiPinPrefix + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// parseFingerprint decodes a pin given either as "sha256/<base64>" or as hex, with or without colons
+func parseFingerprint(pin string) ([]byte, error) {
+    var digest []byte
+    var err error
+    if strings.HasPrefix(pin, spkiPinPrefix) {
+        digest, err = base64.StdEncoding.DecodeString(strings.TrimPrefix(pin, spkiPinPrefix))
+    } else {
+        digest, err = hex.DecodeString(strings.ReplaceAll(pin, ":", ""))
+    }
+    if err != nil || len(digest) != sha256.Size {
+        return nil, fmt.Errorf("certFingerprint %q must be a SHA-256 digest as sha256/<base64> or hex", pin)
+    }
+    return digest, nil
+}
+
+// checkCertPin records the presented key's fingerprint and fails when it differs from the pinned one
+func checkCertPin(monitor Monitor, resp *http.Response, entry *LogEntry) error {
+    entry.CertFingerprint = spkiFingerprint(resp.TLS)
+    if monitor.CertFingerprint == "" {
+        return nil
+    }
+    if entry.CertFingerprint == "" {
+        return fmt.Errorf("certificate pinning requires an HTTPS response")
+    }
+
+    expected, err := parseFingerprint(monitor.CertFingerprint)
+    if err != nil {
+        return err
+    }
+    observed, _ := parseFingerprint(entry.CertFingerprint)
+    if !bytes.Equal(expected, observed) {
+        return fmt.Errorf("certificate fingerprint %s does not match the pinned %s", entry.CertFingerprint, monitor.CertFingerprint)
+    }
+    return nil
+}
@@ -0,0 +1,59 @@
+package entity
+
+import (
+    "context"
+    "crypto/sha256"
+    "encoding/base64"
+    "encoding/hex"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+func TestParseFingerprint(t *testing.T) {
+    sum := sha256.Sum256([]byte("key"))
+    hexPin := hex.EncodeToString(sum[:])
+    var colons []string
+    for i := 0; i < len(hexPin); i += 2 {
+        colons = append(colons, strings.ToUpper(hexPin[i:i+2]))
+    }
+
+    for _, pin := range []string{
+        spkiPinPrefix + base64.StdEncoding.EncodeToString(sum[:]),
+        hexPin,
+        strings.Join(colons, ":"),
+    } {
+        if digest, err := parseFingerprint(pin); err != nil || string(digest) != string(sum[:]) {
+            t.Errorf("parseFingerprint(%q) = %x, %v", pin, digest, err)
+        }
+    }
+    for _, pin := range []string{"", "sha256/not base64", hexPin[:40], "sha256/" + base64.StdEncoding.EncodeToString(sum[:16])} {
+        if _, err := parseFingerprint(pin); err == nil {
+            t.Errorf("parseFingerprint(%q) succeeded, want an error", pin)
+        }
+    }
+}
+
+func TestCertPin(t *testing.T) {
+    server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+    defer server.Close()
+    sum := sha256.Sum256(server.Certificate().RawSubjectPublicKeyInfo)
+    pin := spkiPinPrefix + base64.StdEncoding.EncodeToString(sum[:])
+    um := NewUptimeMonitor()
+
+    entry := um.attemptCheck(context.Background(), Monitor{URL: server.URL + "/", InsecureSkipVerify: true, CertFingerprint: hex.EncodeToString(sum[:])})
+    if !entry.Success || entry.CertFingerprint != pin {
+        t.Errorf("pinned check = %+v, want success with fingerprint %s", entry, pin)
+    }
+
+    other := sha256.Sum256([]byte("another key"))
+    entry = um.attemptCheck(context.Background(), Monitor{URL: server.URL + "/", InsecureSkipVerify: true, CertFingerprint: hex.EncodeToString(other[:])})
+    if entry.Success || !strings.Contains(entry.Error, "does not match") {
+        t.Errorf("mismatched pin = %+v, want a failure", entry)
+    }
+
+    if err := checkCertPin(Monitor{CertFingerprint: pin}, &http.Response{}, &LogEntry{}); err == nil {
+        t.Error("expected an error for a pinned plain HTTP response")
+    }
+}
@@ -0,0 +1,82 @@
+package entity
+
+import (
+    "encoding/json"
+    "net/http"
+    "sync"
+)
+
+// checkAllConcurrency bounds how many checks CheckAll runs at once
+const checkAllConcurrency = 8
+
+// CheckResult summarizes one check run on demand
+type CheckResult struct {
+    URL          string `json:"url"`
+    Success      bool   `json:"success"`
+    StatusCode   int    `json:"statusCode,omitempty"`
+    ResponseTime int64  `json:"responseTime"` // in milliseconds
+    Error        string `json:"error,omitempty"`
+}
+
+// CheckAllSummary is the outcome of checking every active monitor at once
+type CheckAllSummary struct {
+    Checked int           `json:"checked"`
+    Up      int           `json:"up"`
+    Down    int           `json:"down"`
+    Results []CheckResult `json:"results"` // sorted by URL, like ListMonitors
+}
+
+// CheckAll checks every active monitor now, at most checkAllConcurrency at a time, and waits
+// for the results. The checks are recorded like scheduled ones and use each monitor's timeouts.
+// Paused and archived monitors are skipped, as they are by the scheduler.
+func (um *UptimeMonitor) CheckAll() CheckAllSummary {
+    var monitors []Monitor
+    for _, monitor := range um.ListMonitors() {
+        if !monitor.Paused {
+            monitors = append(monitors, monitor)
+        }
+    }
+    results := make([]CheckResult, len(monitors))
+
+    slots := make(chan struct{}, checkAllConcurrency)
+    var wg sync.WaitGroup
+    for i, monitor := range monitors {
+        wg.Add(1)
+        slots <- struct{}{}
+        go func(i int, monitor Monitor) {
+            defer wg.Done()
+            defer func() { <-slots }()
+
+            done := um.beginCheck(monitor.URL)
+            entry := um.checkURL(monitor)
+            done()
+            results[i] = CheckResult{
+                URL:          monitor.URL,
+                Success:      entry.Success,
+                StatusCode:   entry.StatusCode,
+                ResponseTime: entry.ResponseTime,
+                Error:        entry.Error,
+            }
+        }(i, monitor)
+    }
+    wg.Wait()
+
+    summary := CheckAllSummary{Checked: len(results), Results: results}
+    for _, result := range results {
+        if result.Success {
+            summary.Up++
+        } else {
+            summary.Down++
+        }
+    }
+    return summary
+}
+
+func (um *UptimeMonitor) HandleCheckAll(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodPost {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    json.NewEncoder(w).Encode(um.CheckAll())
+}
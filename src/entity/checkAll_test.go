@@ -0,0 +1,46 @@
+package entity
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+func TestCheckAllSkipsPausedAndArchived(t *testing.T) {
+    var requests atomic.Int32
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        requests.Add(1)
+        w.WriteHeader(http.StatusServiceUnavailable)
+    }))
+    defer server.Close()
+
+    um := NewUptimeMonitor()
+    alerter := newRecordingAlerter()
+    um.AddAlerter(alerter)
+    for _, path := range []string{"/active", "/paused", "/archived"} {
+        if err := um.AddMonitor(server.URL+path, time.Hour); err != nil {
+            t.Fatal(err)
+        }
+    }
+    defer um.RemoveMonitor(server.URL + "/active")
+    defer um.RemoveMonitor(server.URL + "/paused")
+    um.PauseMonitor(server.URL + "/paused")
+    um.ArchiveMonitor(server.URL + "/archived")
+
+    summary := um.CheckAll()
+    if summary.Checked != 1 || summary.Results[0].URL != server.URL+"/active" {
+        t.Fatalf("summary = %+v, want only the active monitor checked", summary)
+    }
+    if n := requests.Load(); n != 1 {
+        t.Errorf("server saw %d requests, want 1", n)
+    }
+    alerter.expect(t, EventDown)
+    alerter.expectNone(t)
+    for _, path := range []string{"/paused", "/archived"} {
+        if downtimes := um.GetDowntimes(server.URL + path); len(downtimes) != 0 {
+            t.Errorf("%s has downtimes %+v", path, downtimes)
+        }
+    }
+}
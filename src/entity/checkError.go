@@ -0,0 +1,77 @@
+package entity
+
+import (
+    "crypto/tls"
+    "crypto/x509"
+    "errors"
+    "net"
+    "net/http"
+    "strings"
+    "syscall"
+)
+
+// Error classes recorded on a LogEntry for failed checks
+const (
+    ErrorClassConnReset   = "connection_reset"
+    ErrorClassConnRefused = "connection_refused"
+    ErrorClassTimeout     = "timeout"
+    ErrorClassDNS         = "dns"
+    ErrorClassTLS         = "tls"
+    ErrorClassOther       = "other"
+)
+
+// classifyError maps a transport error to one of the ErrorClass constants
+func classifyError(err error) string {
+    if err == nil {
+        return ""
+    }
+
+    if errors.Is(err, syscall.ECONNRESET) || strings.Contains(err.Error(), "connection reset by peer") {
+        return ErrorClassConnReset
+    }
+    if errors.Is(err, syscall.ECONNREFUSED) {
+        return ErrorClassConnRefused
+    }
+
+    var dnsErr *net.DNSError
+    if errors.As(err, &dnsErr) {
+        return ErrorClassDNS
+    }
+
+    var netErr net.Error
+    if errors.As(err, &netErr) && netErr.Timeout() {
+        return ErrorClassTimeout
+    }
+
+    var certErr *tls.CertificateVerificationError
+    var unknownAuthErr x509.UnknownAuthorityError
+    var hostnameErr x509.HostnameError
+    if errors.As(err, &certErr) || errors.As(err, &unknownAuthErr) || errors.As(err, &hostnameErr) {
+        return ErrorClassTLS
+    }
+
+    return ErrorClassOther
+}
+
+// isRetryable reports whether a failed attempt of the monitor may be retried within the same
+// check. Connection resets, timeouts and 5xx responses are frequently transient, while
+// certificate errors and 4xx responses other than 429 would fail the same way again, so they
+// fail the check at once. FTP reply codes read the other way round: 4xx replies such as 421
+// are transient and 5xx replies such as 530 permanent. Monitors may opt out of retrying resets.
+func isRetryable(monitor Monitor, entry LogEntry) bool {
+    switch entry.ErrorClass {
+    case ErrorClassTLS:
+        return false
+    case ErrorClassConnReset:
+        return monitor.RetryConnReset == nil || *monitor.RetryConnReset
+    }
+    switch monitor.Type {
+    case MonitorTypeFTP:
+        return entry.StatusCode < 500
+    case "", MonitorTypeHTTP, MonitorTypeHTTP3:
+        if entry.StatusCode >= 400 && entry.StatusCode < 500 {
+            return entry.StatusCode == http.StatusTooManyRequests
+        }
+    }
+    return true
+}
@@ -0,0 +1,159 @@
+package entity
+
+import (
+    "crypto/x509"
+    "errors"
+    "fmt"
+    "net"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "sync/atomic"
+    "syscall"
+    "testing"
+)
+
+func TestClassifyError(t *testing.T) {
+    tests := []struct {
+        name string
+        err  error
+        want string
+    }{
+        {"nil", nil, ""},
+        {"reset", fmt.Errorf("read: %w", syscall.ECONNRESET), ErrorClassConnReset},
+        {"reset message", errors.New("read tcp 127.0.0.1:1234: connection reset by peer"), ErrorClassConnReset},
+        {"refused", fmt.Errorf("dial: %w", syscall.ECONNREFUSED), ErrorClassConnRefused},
+        {"dns", &net.DNSError{Err: "no such host", Name: "example.invalid"}, ErrorClassDNS},
+        {"timeout", &net.OpError{Op: "read", Err: os.ErrDeadlineExceeded}, ErrorClassTimeout},
+        {"tls", x509.UnknownAuthorityError{}, ErrorClassTLS},
+        {"other", errors.New("boom"), ErrorClassOther},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := classifyError(tt.err); got != tt.want {
+                t.Errorf("classifyError(%v) = %q, want %q", tt.err, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestIsRetryable(t *testing.T) {
+    ftp := Monitor{Type: MonitorTypeFTP}
+    noResetRetry := false
+    tests := []struct {
+        name    string
+        monitor Monitor
+        entry   LogEntry
+        want    bool
+    }{
+        {"connection reset", Monitor{}, LogEntry{ErrorClass: ErrorClassConnReset}, true},
+        {"connection reset as failure", Monitor{RetryConnReset: &noResetRetry}, LogEntry{ErrorClass: ErrorClassConnReset}, false},
+        {"timeout with resets as failures", Monitor{RetryConnReset: &noResetRetry}, LogEntry{ErrorClass: ErrorClassTimeout}, true},
+        {"timeout", Monitor{}, LogEntry{ErrorClass: ErrorClassTimeout}, true},
+        {"bad gateway", Monitor{}, LogEntry{StatusCode: http.StatusBadGateway}, true},
+        {"too many requests", Monitor{}, LogEntry{StatusCode: http.StatusTooManyRequests}, true},
+        {"assertion on 200", Monitor{}, LogEntry{StatusCode: http.StatusOK, Error: "expected body substring"}, true},
+        {"tls", Monitor{}, LogEntry{ErrorClass: ErrorClassTLS}, false},
+        {"not found", Monitor{}, LogEntry{StatusCode: http.StatusNotFound}, false},
+        {"forbidden", Monitor{Type: MonitorTypeHTTP3}, LogEntry{StatusCode: http.StatusForbidden}, false},
+        {"ftp service not available", ftp, LogEntry{StatusCode: 421}, true},
+        {"ftp file unavailable", ftp, LogEntry{StatusCode: 450}, true},
+        {"ftp login incorrect", ftp, LogEntry{StatusCode: 530}, false},
+        {"ftp connection refused", ftp, LogEntry{ErrorClass: ErrorClassConnRefused}, true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := isRetryable(tt.monitor, tt.entry); got != tt.want {
+                t.Errorf("isRetryable(%+v) = %v, want %v", tt.entry, got, tt.want)
+            }
+        })
+    }
+}
+
+// resetOnceServer resets the connection of its first request and answers 200 afterwards
+func resetOnceServer(t *testing.T) (*httptest.Server, *int32) {
+    var requests int32
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if atomic.AddInt32(&requests, 1) == 1 {
+            conn, _, err := w.(http.Hijacker).Hijack()
+            if err != nil {
+                t.Errorf("hijacking connection: %v", err)
+                return
+            }
+            // Closing with a zero linger sends a RST instead of a FIN
+            conn.(*net.TCPConn).SetLinger(0)
+            conn.Close()
+            return
+        }
+        w.WriteHeader(http.StatusOK)
+    }))
+    t.Cleanup(server.Close)
+    return server, &requests
+}
+
+func TestCheckRetriesConnectionReset(t *testing.T) {
+    server, requests := resetOnceServer(t)
+    um := NewUptimeMonitor(WithRetryJitter(0))
+
+    url := server.URL + "/"
+    entry := um.checkURL(Monitor{URL: url, Retries: 1})
+    if !entry.Success {
+        t.Fatalf("check failed after retry: %s", entry.Error)
+    }
+    if entry.Attempt != 2 || atomic.LoadInt32(requests) != 2 {
+        t.Errorf("attempt = %d after %d requests, want the second attempt to succeed", entry.Attempt, *requests)
+    }
+
+    logs := um.GetLogs(url)
+    if len(logs) != 2 || !logs[0].Retried || logs[0].ErrorClass != ErrorClassConnReset {
+        t.Fatalf("logs = %+v, want a retried connection reset followed by the success", logs)
+    }
+    if stats := um.GetStats(url); stats.TotalChecks != 1 || stats.FailureCount != 0 {
+        t.Errorf("stats count %d checks and %d failures, want the retried attempt excluded", stats.TotalChecks, stats.FailureCount)
+    }
+    if downtimes := um.GetDowntimes(url); len(downtimes) != 0 {
+        t.Errorf("downtimes = %+v, want none", downtimes)
+    }
+}
+
+func TestCheckWithoutRetriesRecordsReset(t *testing.T) {
+    server, _ := resetOnceServer(t)
+    um := NewUptimeMonitor()
+
+    entry := um.checkURL(Monitor{URL: server.URL})
+    if entry.Success || entry.ErrorClass != ErrorClassConnReset {
+        t.Fatalf("entry = %+v, want a failed check classified as a connection reset", entry)
+    }
+}
+
+func TestCheckWithResetsAsFailuresDoesNotRetry(t *testing.T) {
+    server, requests := resetOnceServer(t)
+    um := NewUptimeMonitor(WithRetryJitter(0))
+
+    retryReset := false
+    entry := um.checkURL(Monitor{URL: server.URL, Retries: 2, RetryConnReset: &retryReset})
+    if entry.Success || entry.ErrorClass != ErrorClassConnReset {
+        t.Fatalf("entry = %+v, want a failed check classified as a connection reset", entry)
+    }
+    if n := atomic.LoadInt32(requests); n != 1 {
+        t.Errorf("server saw %d requests, want the reset not to be retried", n)
+    }
+}
+
+func TestCheckDoesNotRetryClientErrors(t *testing.T) {
+    var requests int32
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        atomic.AddInt32(&requests, 1)
+        w.WriteHeader(http.StatusNotFound)
+    }))
+    defer server.Close()
+    um := NewUptimeMonitor(WithRetryJitter(0))
+
+    entry := um.checkURL(Monitor{URL: server.URL, Retries: 3})
+    if entry.Success || entry.StatusCode != http.StatusNotFound {
+        t.Fatalf("entry = %+v, want a failed 404 check", entry)
+    }
+    if n := atomic.LoadInt32(&requests); n != 1 {
+        t.Errorf("server saw %d requests, want a 404 not to be retried", n)
+    }
+}
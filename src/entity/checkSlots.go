@@ -0,0 +1,43 @@
+package entity
+
+import (
+    "context"
+    "fmt"
+)
+
+// WithMaxConcurrentChecks lets at most n check attempts run at once across all monitors, so
+// monitors sharing a tick queue for a slot instead of opening connections all at once. Zero
+// leaves checks unlimited.
+func WithMaxConcurrentChecks(n int) Option {
+    return func(um *UptimeMonitor) {
+        um.checkSlots = nil
+        if n > 0 {
+            um.checkSlots = make(chan struct{}, n)
+        }
+    }
+}
+
+// acquireCheckSlot waits for a free check slot and returns the function releasing it.
+// It fails when ctx, e.g. the check budget, ends first.
+func (um *UptimeMonitor) acquireCheckSlot(ctx context.Context) (func(), error) {
+    if um.checkSlots == nil {
+        return func() {}, nil
+    }
+    select {
+    case um.checkSlots <- struct{}{}:
+        return func() { <-um.checkSlots }, nil
+    case <-ctx.Done():
+        return nil, fmt.Errorf("waiting for a check slot: %w", ctx.Err())
+    }
+}
+
+// withCheckSlot runs check while holding a check slot. When no slot frees up before ctx ends,
+// the check is not run and its entry records the failure to get one.
+func (um *UptimeMonitor) withCheckSlot(ctx context.Context, monitor Monitor, check func(context.Context, Monitor) LogEntry) LogEntry {
+    release, err := um.acquireCheckSlot(ctx)
+    if err != nil {
+        return LogEntry{URL: monitor.URL, Timestamp: um.now(), Error: err.Error(), ErrorClass: classifyError(err)}
+    }
+    defer release()
+    return check(ctx, monitor)
+}
@@ -0,0 +1,93 @@
+package entity
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+func TestAcquireCheckSlot(t *testing.T) {
+    um := NewUptimeMonitor(WithMaxConcurrentChecks(1))
+
+    release, err := um.acquireCheckSlot(context.Background())
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // The only slot is taken, so a second check gives up when its budget ends
+    ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+    defer cancel()
+    if _, err := um.acquireCheckSlot(ctx); !errors.Is(err, context.DeadlineExceeded) {
+        t.Errorf("second acquire = %v, want a deadline error", err)
+    }
+
+    acquired := make(chan struct{})
+    go func() {
+        release, err := um.acquireCheckSlot(context.Background())
+        if err == nil {
+            release()
+        }
+        close(acquired)
+    }()
+    select {
+    case <-acquired:
+        t.Fatal("acquired a slot while it was taken")
+    case <-time.After(50 * time.Millisecond):
+    }
+    release()
+    select {
+    case <-acquired:
+    case <-time.After(2 * time.Second):
+        t.Fatal("the waiting check did not get the released slot")
+    }
+}
+
+func TestAcquireCheckSlotUnlimited(t *testing.T) {
+    um := NewUptimeMonitor(WithMaxConcurrentChecks(1), WithMaxConcurrentChecks(0))
+    for i := 0; i < 10; i++ {
+        if _, err := um.acquireCheckSlot(context.Background()); err != nil {
+            t.Fatal(err)
+        }
+    }
+}
+
+func TestMultiRequestChecksTakeASlotPerRequest(t *testing.T) {
+    var inFlight, peak int32
+    handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        n := atomic.AddInt32(&inFlight, 1)
+        defer atomic.AddInt32(&inFlight, -1)
+        for {
+            p := atomic.LoadInt32(&peak)
+            if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+                break
+            }
+        }
+        time.Sleep(20 * time.Millisecond)
+    })
+    var ports []int
+    for i := 0; i < 3; i++ {
+        server := httptest.NewServer(handler)
+        defer server.Close()
+        ports = append(ports, serverPort(t, server))
+    }
+    um := NewUptimeMonitor(WithMaxConcurrentChecks(2))
+
+    monitors := []Monitor{
+        {URL: "http://127.0.0.1/", Type: MonitorTypeHTTP, Ports: ports},
+        {URL: fmt.Sprintf("http://127.0.0.1:%d/", ports[0]), Type: MonitorTypeHTTP, Locales: []LocaleVariant{{Name: "en"}, {Name: "de"}, {Name: "fr"}}},
+    }
+    for _, monitor := range monitors {
+        atomic.StoreInt32(&peak, 0)
+        if entry := um.attemptCheck(context.Background(), monitor); !entry.Success {
+            t.Fatalf("check = %+v, want success", entry)
+        }
+        if n := atomic.LoadInt32(&peak); n > 2 {
+            t.Errorf("%d requests ran at once, want at most the 2 slots", n)
+        }
+    }
+}
@@ -0,0 +1,41 @@
+package entity
+
+import (
+    "net/http"
+    "net/http/httptrace"
+    "time"
+)
+
+// checkTrace collects connection-level timings for a single check attempt
+type checkTrace struct {
+    start      time.Time
+    firstByte  time.Duration // zero until the first response byte arrives
+    remoteAddr string        // IP:port of the connection that served the request
+
+    // Offsets from start at which a connection was obtained and the request fully written
+    connected    time.Duration
+    wroteRequest time.Duration
+}
+
+func newCheckTrace() *checkTrace {
+    return &checkTrace{start: time.Now()}
+}
+
+// attach returns a copy of req that reports into the trace
+func (ct *checkTrace) attach(req *http.Request) *http.Request {
+    trace := &httptrace.ClientTrace{
+        GotConn: func(info httptrace.GotConnInfo) {
+            ct.remoteAddr = info.Conn.RemoteAddr().String()
+            ct.connected = time.Since(ct.start)
+        },
+        WroteRequest: func(info httptrace.WroteRequestInfo) {
+            if info.Err == nil {
+                ct.wroteRequest = time.Since(ct.start)
+            }
+        },
+        GotFirstResponseByte: func() {
+            ct.firstByte = time.Since(ct.start)
+        },
+    }
+    return req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
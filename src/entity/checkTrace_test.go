@@ -0,0 +1,43 @@
+package entity
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestCheckTrace(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        time.Sleep(30 * time.Millisecond)
+        w.Write([]byte("ok"))
+    }))
+    defer server.Close()
+
+    entry := NewUptimeMonitor().attemptCheck(context.Background(), Monitor{URL: server.URL + "/"})
+    if !entry.Success {
+        t.Fatalf("check failed: %s", entry.Error)
+    }
+    if entry.TTFB < 30 || entry.TTFB > entry.ResponseTime {
+        t.Errorf("TTFB = %dms with a response time of %dms, want at least the server's 30ms delay", entry.TTFB, entry.ResponseTime)
+    }
+    if want := strings.TrimPrefix(server.URL, "http://"); entry.RemoteAddr != want {
+        t.Errorf("remote address = %q, want %q", entry.RemoteAddr, want)
+    }
+}
+
+func TestCheckTraceWithoutResponse(t *testing.T) {
+    server := httptest.NewServer(http.NotFoundHandler())
+    server.Close()
+
+    trace := newCheckTrace()
+    req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+    if _, err := http.DefaultClient.Do(trace.attach(req)); err == nil {
+        t.Fatal("expected a connection error")
+    }
+    if trace.firstByte != 0 || trace.remoteAddr != "" || trace.connected != 0 {
+        t.Errorf("trace of a refused connection = %+v, want no timings", trace)
+    }
+}
@@ -0,0 +1,83 @@
+package entity
+
+import (
+    "encoding/json"
+    "net/http"
+    "strings"
+)
+
+// redactedSecret replaces secrets in exported configs; it must be filled in before reloading
+const redactedSecret = "REDACTED"
+
+// ExportConfig returns the active monitors in the format accepted by the add endpoint and
+// HTTPConfigSource, sorted by URL. Unless includeSecrets is set, credentials are replaced by
+// a placeholder.
+func (um *UptimeMonitor) ExportConfig(includeSecrets bool) []MonitorConfig {
+    monitors := um.ListMonitors()
+    configs := make([]MonitorConfig, 0, len(monitors))
+    for _, monitor := range monitors {
+        if !includeSecrets {
+            monitor = redactSecrets(monitor)
+        }
+        configs = append(configs, monitor.Config())
+    }
+    return configs
+}
+
+// redactSecrets returns a copy of the monitor with its credentials replaced
+func redactSecrets(monitor Monitor) Monitor {
+    if monitor.FTPPassword != "" {
+        monitor.FTPPassword = redactedSecret
+    }
+    if monitor.BasicAuthPass != "" {
+        monitor.BasicAuthPass = redactedSecret
+    }
+    if monitor.BearerToken != "" {
+        monitor.BearerToken = redactedSecret
+    }
+    if len(monitor.Headers) > 0 {
+        headers := make(map[string]string, len(monitor.Headers))
+        for name, value := range monitor.Headers {
+            if isSecretHeader(name) {
+                value = redactedSecret
+            }
+            headers[name] = value
+        }
+        monitor.Headers = headers
+    }
+    if monitor.SigV4 != nil {
+        sigV4 := *monitor.SigV4
+        sigV4.SecretAccessKey = redactedSecret
+        if sigV4.SessionToken != "" {
+            sigV4.SessionToken = redactedSecret
+        }
+        monitor.SigV4 = &sigV4
+    }
+    return monitor
+}
+
+// isSecretHeader reports whether a request header usually carries credentials
+func isSecretHeader(name string) bool {
+    name = strings.ToLower(name)
+    switch name {
+    case "authorization", "proxy-authorization", "cookie":
+        return true
+    }
+    return strings.Contains(name, "token") || strings.Contains(name, "secret") || strings.Contains(name, "key")
+}
+
+// HandleExportConfig serves /monitor/export as a downloadable config file; secrets=true
+// includes credentials
+func (um *UptimeMonitor) HandleExportConfig(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    includeSecrets := r.URL.Query().Get("secrets") == "true"
+    w.Header().Set("Content-Type", "application/json")
+    w.Header().Set("Content-Disposition", `attachment; filename="monitors.json"`)
+    encoder := json.NewEncoder(w)
+    encoder.SetIndent("", "  ")
+    encoder.Encode(um.ExportConfig(includeSecrets))
+}
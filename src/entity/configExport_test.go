@@ -0,0 +1,86 @@
+package entity
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+)
+
+func TestExportConfig(t *testing.T) {
+    um := NewUptimeMonitor()
+    defer um.Close()
+
+    secret := Monitor{
+        URL:           "https://api.example.com/",
+        Interval:      time.Hour,
+        BasicAuthUser: "monitor",
+        BasicAuthPass: "hunter2",
+        Headers:       map[string]string{"X-Api-Key": "k", "Accept": "application/json"},
+    }
+    if err := um.AddMonitorConfig(secret); err != nil {
+        t.Fatal(err)
+    }
+    signed := Monitor{
+        URL:      "https://www.example.com/",
+        Interval: 90 * time.Second,
+        SigV4:    &SigV4Config{AccessKeyID: "AKID", SecretAccessKey: "secret", Region: "us-east-1", Service: "execute-api"},
+    }
+    if err := um.AddMonitorConfig(signed); err != nil {
+        t.Fatal(err)
+    }
+
+    configs := um.ExportConfig(false)
+    if len(configs) != 2 || configs[0].URL != "https://api.example.com/" {
+        t.Fatalf("exported %+v, want both monitors sorted by URL", configs)
+    }
+    if redacted := configs[0]; redacted.BasicAuthUser != "monitor" || redacted.BasicAuthPass != redactedSecret ||
+        redacted.Headers["X-Api-Key"] != redactedSecret || redacted.Headers["Accept"] != "application/json" {
+        t.Errorf("redacted export = %+v", redacted)
+    }
+    if redacted := configs[1]; redacted.SigV4.AccessKeyID != "AKID" || redacted.SigV4.SecretAccessKey != redactedSecret {
+        t.Errorf("redacted SigV4 credentials = %+v", redacted.SigV4)
+    }
+    if monitors := um.ListMonitors(); monitors[0].BasicAuthPass != "hunter2" || monitors[0].Headers["X-Api-Key"] != "k" || monitors[1].SigV4.SecretAccessKey != "secret" {
+        t.Errorf("redacting changed the active monitors: %+v", monitors)
+    }
+
+    // With secrets, the export is accepted as a config as is
+    data, err := json.Marshal(um.ExportConfig(true))
+    if err != nil {
+        t.Fatal(err)
+    }
+    monitors, err := decodeMonitorConfigs(data)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(monitors) != 2 || monitors[0].BasicAuthPass != "hunter2" || monitors[1].SigV4.SecretAccessKey != "secret" || monitors[1].Interval != 90*time.Second {
+        t.Errorf("reloaded export = %+v", monitors)
+    }
+}
+
+func TestHandleExportConfig(t *testing.T) {
+    um := NewUptimeMonitor()
+    defer um.Close()
+    if err := um.AddMonitorConfig(Monitor{URL: "https://example.com/", Interval: time.Hour, BearerToken: "token"}); err != nil {
+        t.Fatal(err)
+    }
+
+    rec := httptest.NewRecorder()
+    um.HandleExportConfig(rec, httptest.NewRequest(http.MethodGet, "/monitor/export", nil))
+    if rec.Header().Get("Content-Disposition") == "" {
+        t.Error("export is not served as an attachment")
+    }
+    var configs []MonitorConfig
+    if err := json.NewDecoder(rec.Body).Decode(&configs); err != nil || len(configs) != 1 || configs[0].BearerToken != redactedSecret {
+        t.Errorf("export = %+v, %v, want the bearer token redacted", configs, err)
+    }
+
+    rec = httptest.NewRecorder()
+    um.HandleExportConfig(rec, httptest.NewRequest(http.MethodGet, "/monitor/export?secrets=true", nil))
+    configs = nil
+    if err := json.NewDecoder(rec.Body).Decode(&configs); err != nil || len(configs) != 1 || configs[0].BearerToken != "token" {
+        t.Errorf("export with secrets = %+v, %v", configs, err)
+    }
+}
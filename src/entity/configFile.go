@@ -0,0 +1,47 @@
+package entity
+
+import (
+    "encoding/json"
+    "fmt"
+    "log/slog"
+    "os"
+    "path/filepath"
+    "strings"
+
+    "gopkg.in/yaml.v3"
+)
+
+// LoadMonitorsFromFile adds the monitors listed in a JSON or YAML (.yaml, .yml) file, in the
+// format accepted by the add endpoint. Entries that cannot be added, such as duplicates of a
+// monitor restored from the state file, are logged and skipped; only an unreadable file fails.
+func (um *UptimeMonitor) LoadMonitorsFromFile(path string) error {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return err
+    }
+    if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+        if data, err = yamlToJSON(data); err != nil {
+            return fmt.Errorf("parsing %s: %w", path, err)
+        }
+    }
+    monitors, err := decodeMonitorConfigs(data)
+    if err != nil {
+        return fmt.Errorf("parsing %s: %w", path, err)
+    }
+
+    for _, monitor := range monitors {
+        if err := um.AddMonitorConfig(monitor); err != nil {
+            slog.Warn("Skipping monitor from config file", "path", path, "url", monitor.URL, "error", err)
+        }
+    }
+    return nil
+}
+
+// yamlToJSON converts a YAML document to JSON, so YAML files share the JSON field names and decoding
+func yamlToJSON(data []byte) ([]byte, error) {
+    var document interface{}
+    if err := yaml.Unmarshal(data, &document); err != nil {
+        return nil, err
+    }
+    return json.Marshal(document)
+}
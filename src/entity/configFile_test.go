@@ -0,0 +1,77 @@
+package entity
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+)
+
+func TestLoadMonitorsFromFile(t *testing.T) {
+    dir := t.TempDir()
+    files := map[string]string{
+        "monitors.json": `[
+            {"url": "https://a.example.com", "interval": 60},
+            {"url": "https://b.example.com", "interval": "90s", "method": "HEAD"}
+        ]`,
+        "monitors.yaml": `
+- url: https://c.example.com
+  interval: 2m
+  headers:
+    Accept: text/html
+- url: https://a.example.com
+  interval: 30
+`,
+    }
+    for name, content := range files {
+        if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    um := NewUptimeMonitor()
+    defer um.Close()
+    if err := um.LoadMonitorsFromFile(filepath.Join(dir, "monitors.json")); err != nil {
+        t.Fatal(err)
+    }
+    // The duplicate of a.example.com is skipped rather than failing the file
+    if err := um.LoadMonitorsFromFile(filepath.Join(dir, "monitors.yaml")); err != nil {
+        t.Fatal(err)
+    }
+
+    monitors := um.ListMonitors()
+    if len(monitors) != 3 {
+        t.Fatalf("loaded %d monitors, want 3", len(monitors))
+    }
+    if monitors[0].Interval != time.Minute {
+        t.Errorf("a.example.com interval = %v, want the first file's 1m", monitors[0].Interval)
+    }
+    if monitors[1].Interval != 90*time.Second || monitors[1].Method != "HEAD" {
+        t.Errorf("b.example.com = %+v", monitors[1])
+    }
+    if monitors[2].Interval != 2*time.Minute || monitors[2].Headers["Accept"] != "text/html" {
+        t.Errorf("c.example.com from YAML = %+v", monitors[2])
+    }
+}
+
+func TestLoadMonitorsFromFileErrors(t *testing.T) {
+    dir := t.TempDir()
+    um := NewUptimeMonitor()
+    defer um.Close()
+
+    if err := um.LoadMonitorsFromFile(filepath.Join(dir, "missing.json")); err == nil {
+        t.Error("expected an error for a missing file")
+    }
+    for name, content := range map[string]string{
+        "bad.json": `{"url": "https://example.com"}`,
+        "bad.yml":  "- url: [unterminated",
+    } {
+        path := filepath.Join(dir, name)
+        if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+            t.Fatal(err)
+        }
+        if err := um.LoadMonitorsFromFile(path); err == nil {
+            t.Errorf("expected an error parsing %s", name)
+        }
+    }
+}
@@ -0,0 +1,155 @@
+package entity
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "io"
+    "log/slog"
+    "net/http"
+    "reflect"
+    "time"
+)
+
+// ConfigSource supplies the desired set of monitors and announces changes to it
+type ConfigSource interface {
+    // Watch sends the complete desired monitor set initially and whenever it changes,
+    // until stop is closed. The returned channel is closed when watching ends.
+    Watch(stop <-chan struct{}) <-chan []Monitor
+}
+
+// SyncFrom reconciles the running monitors with every set the source emits.
+// The source is authoritative: monitors it does not list are removed.
+func (um *UptimeMonitor) SyncFrom(source ConfigSource, stop <-chan struct{}) {
+    go func() {
+        for desired := range source.Watch(stop) {
+            added, removed, updated := um.ApplyConfig(desired)
+            slog.Info("Applied monitor config", "added", added, "removed", removed, "updated", updated)
+        }
+    }()
+}
+
+// ApplyConfig makes the running monitors match desired: monitors missing from it are removed,
+// new ones are started and changed ones are restarted with their new configuration. Logs and
+// downtimes are kept throughout. Invalid entries are logged and skipped, as are archived URLs,
+// which stay archived until an operator unarchives them.
+func (um *UptimeMonitor) ApplyConfig(desired []Monitor) (added, removed, updated int) {
+    um.mu.Lock()
+    defer um.mu.Unlock()
+
+    wanted := make(map[string]Monitor, len(desired))
+    for _, monitor := range desired {
+        if err := normalizeMonitor(&monitor); err != nil {
+            slog.Warn("Skipping invalid monitor", "url", monitor.URL, "source", "config", "error", err)
+            continue
+        }
+        if _, archived := um.archived[monitor.URL]; archived {
+            slog.Info("Skipping archived monitor", "url", monitor.URL, "source", "config")
+            continue
+        }
+        wanted[monitor.URL] = monitor
+    }
+
+    for url := range um.monitors {
+        if _, keep := wanted[url]; !keep {
+            um.removeLocked(url)
+            slog.Info("Monitor removed", "url", url, "source", "config")
+            removed++
+        }
+    }
+
+    for url, monitor := range wanted {
+        current, exists := um.monitors[url]
+        if exists && current.Paused {
+            // Pausing is an operator action that config sources know nothing about
+            monitor.Paused = true
+        }
+        switch {
+        case !exists:
+            um.startLocked(monitor)
+            slog.Info("Monitor added", "url", url, "interval", monitor.Interval.String(), "source", "config")
+            added++
+        case !reflect.DeepEqual(current, monitor):
+            um.removeLocked(url)
+            um.startLocked(monitor)
+            slog.Info("Monitor updated", "url", url, "source", "config")
+            updated++
+        }
+    }
+    return added, removed, updated
+}
+
+// HTTPConfigSource polls an HTTP endpoint that serves a JSON array of monitor
+// definitions in the add endpoint's format, e.g. a KV store's HTTP API
+type HTTPConfigSource struct {
+    URL      string
+    Interval time.Duration
+    Client   *http.Client
+}
+
+func (s *HTTPConfigSource) Watch(stop <-chan struct{}) <-chan []Monitor {
+    updates := make(chan []Monitor)
+    go func() {
+        defer close(updates)
+
+        ticker := time.NewTicker(s.Interval)
+        defer ticker.Stop()
+
+        var last []byte
+        for {
+            data, err := s.fetch()
+            if err != nil {
+                slog.Warn("Failed to poll monitor config", "configUrl", s.URL, "error", err)
+            } else if !bytes.Equal(data, last) {
+                monitors, err := decodeMonitorConfigs(data)
+                if err != nil {
+                    slog.Warn("Invalid monitor config", "configUrl", s.URL, "error", err)
+                } else {
+                    select {
+                    case updates <- monitors:
+                        last = data
+                    case <-stop:
+                        return
+                    }
+                }
+            }
+
+            select {
+            case <-stop:
+                return
+            case <-ticker.C:
+            }
+        }
+    }()
+    return updates
+}
+
+func (s *HTTPConfigSource) fetch() ([]byte, error) {
+    client := s.Client
+    if client == nil {
+        client = http.DefaultClient
+    }
+    resp, err := client.Get(s.URL)
+    if err != nil {
+        return nil, err
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode != http.StatusOK {
+        return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+    }
+    return io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+}
+
+// decodeMonitorConfigs parses a JSON array of MonitorConfig
+func decodeMonitorConfigs(data []byte) ([]Monitor, error) {
+    var configs []MonitorConfig
+    if err := json.Unmarshal(data, &configs); err != nil {
+        return nil, err
+    }
+    monitors := make([]Monitor, 0, len(configs))
+    for _, config := range configs {
+        monitors = append(monitors, config.ToMonitor())
+    }
+    return monitors, nil
+}
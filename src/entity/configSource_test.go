@@ -0,0 +1,124 @@
+package entity
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+func TestApplyConfig(t *testing.T) {
+    um := NewUptimeMonitor()
+    defer um.Close()
+
+    added, removed, updated := um.ApplyConfig([]Monitor{
+        {URL: "https://a.example.com", Interval: time.Hour},
+        {URL: "https://b.example.com", Interval: time.Hour},
+        {URL: "ftp://", Interval: time.Hour},
+    })
+    if added != 2 || removed != 0 || updated != 0 {
+        t.Fatalf("initial config = +%d -%d ~%d, want two added and the invalid entry skipped", added, removed, updated)
+    }
+    if err := um.PauseMonitor("https://b.example.com"); err != nil {
+        t.Fatal(err)
+    }
+    um.mu.Lock()
+    um.appendLogLocked(LogEntry{URL: "https://b.example.com/", Success: true, Timestamp: time.Now()})
+    um.mu.Unlock()
+
+    // The same set again changes nothing, even though b was paused by an operator
+    added, removed, updated = um.ApplyConfig([]Monitor{
+        {URL: "https://a.example.com", Interval: time.Hour},
+        {URL: "https://b.example.com", Interval: time.Hour},
+    })
+    if added != 0 || removed != 0 || updated != 0 {
+        t.Errorf("unchanged config = +%d -%d ~%d, want no changes", added, removed, updated)
+    }
+
+    added, removed, updated = um.ApplyConfig([]Monitor{
+        {URL: "https://b.example.com", Interval: 2 * time.Hour},
+        {URL: "https://c.example.com", Interval: time.Hour},
+    })
+    if added != 1 || removed != 1 || updated != 1 {
+        t.Errorf("changed config = +%d -%d ~%d, want one of each", added, removed, updated)
+    }
+
+    monitors := um.ListMonitors()
+    if len(monitors) != 2 || monitors[0].URL != "https://b.example.com/" || monitors[1].URL != "https://c.example.com/" {
+        t.Fatalf("monitors = %+v, want b and c", monitors)
+    }
+    if !monitors[0].Paused || monitors[0].Interval != 2*time.Hour {
+        t.Errorf("updated b = %+v, want the new interval and still paused", monitors[0])
+    }
+    if logs := um.GetLogs("https://b.example.com/"); len(logs) != 1 {
+        t.Errorf("b has %d logs after the update, want them kept", len(logs))
+    }
+}
+
+func TestApplyConfigSkipsArchived(t *testing.T) {
+    um := NewUptimeMonitor()
+    defer um.Close()
+
+    const url = "https://a.example.com/"
+    if err := um.AddMonitor(url, time.Hour); err != nil {
+        t.Fatal(err)
+    }
+    if err := um.ArchiveMonitor(url); err != nil {
+        t.Fatal(err)
+    }
+
+    added, removed, updated := um.ApplyConfig([]Monitor{{URL: url, Interval: 2 * time.Hour}})
+    if added != 0 || removed != 0 || updated != 0 {
+        t.Errorf("config listing an archived URL = +%d -%d ~%d, want no changes", added, removed, updated)
+    }
+    if monitors := um.ListMonitors(); len(monitors) != 0 {
+        t.Errorf("monitors = %+v, want the archived URL not started", monitors)
+    }
+    if archived := um.GetArchivedMonitors(); len(archived) != 1 || archived[0].Interval != time.Hour {
+        t.Errorf("archived = %+v, want the archived monitor unchanged", archived)
+    }
+}
+
+func TestHTTPConfigSource(t *testing.T) {
+    var config atomic.Value
+    config.Store(`[{"url": "https://a.example.com", "interval": 60}]`)
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Write([]byte(config.Load().(string)))
+    }))
+    defer server.Close()
+
+    stop := make(chan struct{})
+    source := &HTTPConfigSource{URL: server.URL, Interval: 10 * time.Millisecond}
+    updates := source.Watch(stop)
+
+    next := func() []Monitor {
+        t.Helper()
+        select {
+        case monitors := <-updates:
+            return monitors
+        case <-time.After(2 * time.Second):
+            t.Fatal("no config update")
+        }
+        return nil
+    }
+    if monitors := next(); len(monitors) != 1 || monitors[0].Interval != time.Minute {
+        t.Errorf("initial set = %+v", monitors)
+    }
+
+    // Polls returning the same document are not sent again
+    select {
+    case monitors := <-updates:
+        t.Errorf("unchanged config was sent again: %+v", monitors)
+    case <-time.After(50 * time.Millisecond):
+    }
+
+    config.Store(`[{"url": "https://a.example.com", "interval": 60}, {"url": "https://b.example.com", "interval": 60}]`)
+    if monitors := next(); len(monitors) != 2 {
+        t.Errorf("changed set = %+v, want 2 monitors", monitors)
+    }
+
+    close(stop)
+    for range updates {
+    }
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+    "bytes"
+    "compress/gzip"
+    "fmt"
+    "io"
+    "strings"
+
+    "github.com/andybalholm/brotli"
+)
+
+// acceptedEncodings is advertised by monitors verifying content encodings. Setting it
+// explicitly stops the transport from transparently decoding gzip on its own.
+const acceptedEncodings = "gzip, br"
+
+// decodeContentEncoding decodes a body according to its Content-Encoding header.
+// Bodies without an encoding, or with "identity", are returned unchanged.
+func decodeContentEncoding(encoding string, body []byte) ([]byte, error) {
+    var reader io.Reader
+    switch strings.ToLower(strings.TrimSpace(encoding)) {
+    case "", "identity":
+        return body, nil
+    case "gzip", "x-gzip":
+        gz, err := gzip.NewReader(bytes.NewReader(body))
+        if err != nil {
+            return nil, fmt.Errorf("invalid gzip content encoding: %w", err)
+        }
+        defer gz.Close()
+        reader = gz
+    case "br":
+        reader = brotli.NewReader(bytes.NewReader(body))
+    default:
+        return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+    }
+
+    decoded, err := io.ReadAll(io.LimitReader(reader, maxBodyBytes))
+    if err != nil {
+        return nil, fmt.Errorf("invalid %s content encoding: %w", encoding, err)
+    }
+    return decoded, nil
+}
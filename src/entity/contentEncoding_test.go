@@ -0,0 +1,89 @@
+package entity
+
+import (
+    "bytes"
+    "compress/gzip"
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/andybalholm/brotli"
+)
+
+func compressGzip(t *testing.T, data string) []byte {
+    t.Helper()
+    var buf bytes.Buffer
+    gz := gzip.NewWriter(&buf)
+    gz.Write([]byte(data))
+    if err := gz.Close(); err != nil {
+        t.Fatal(err)
+    }
+    return buf.Bytes()
+}
+
+func compressBrotli(t *testing.T, data string) []byte {
+    t.Helper()
+    var buf bytes.Buffer
+    br := brotli.NewWriter(&buf)
+    br.Write([]byte(data))
+    if err := br.Close(); err != nil {
+        t.Fatal(err)
+    }
+    return buf.Bytes()
+}
+
+func TestDecodeContentEncoding(t *testing.T) {
+    const body = "hello, encoded world"
+    tests := []struct {
+        encoding string
+        data     []byte
+    }{
+        {"", []byte(body)},
+        {"identity", []byte(body)},
+        {"gzip", compressGzip(t, body)},
+        {"X-Gzip", compressGzip(t, body)},
+        {" br ", compressBrotli(t, body)},
+    }
+    for _, test := range tests {
+        decoded, err := decodeContentEncoding(test.encoding, test.data)
+        if err != nil || string(decoded) != body {
+            t.Errorf("decodeContentEncoding(%q) = %q, %v", test.encoding, decoded, err)
+        }
+    }
+
+    for _, encoding := range []string{"gzip", "br", "deflate"} {
+        if _, err := decodeContentEncoding(encoding, []byte(body)); err == nil {
+            t.Errorf("decoding a plain body as %q succeeded, want an error", encoding)
+        }
+    }
+}
+
+func TestVerifyEncoding(t *testing.T) {
+    var encoding string
+    var body []byte
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Header.Get("Accept-Encoding") != acceptedEncodings {
+            http.Error(w, "missing Accept-Encoding", http.StatusBadRequest)
+            return
+        }
+        w.Header().Set("Content-Encoding", encoding)
+        w.Write(body)
+    }))
+    defer server.Close()
+    um := NewUptimeMonitor()
+    monitor := Monitor{URL: server.URL + "/", VerifyEncoding: true, ExpectedBody: "welcome"}
+
+    encoding, body = "br", compressBrotli(t, "welcome home")
+    if entry := um.attemptCheck(context.Background(), monitor); !entry.Success || entry.ContentEncoding != "br" {
+        t.Errorf("brotli check = %+v, want the decoded body to match", entry)
+    }
+    encoding, body = "gzip", compressGzip(t, "welcome home")
+    if entry := um.attemptCheck(context.Background(), monitor); !entry.Success || entry.ContentEncoding != "gzip" {
+        t.Errorf("gzip check = %+v, want the decoded body to match", entry)
+    }
+    encoding, body = "gzip", []byte("welcome home")
+    if entry := um.attemptCheck(context.Background(), monitor); entry.Success {
+        t.Error("a body that does not match its gzip encoding passed")
+    }
+}
@@ -0,0 +1,96 @@
+package entity
+
+import (
+    "encoding/csv"
+    "fmt"
+    "net/http"
+    "net/url"
+    "strconv"
+    "strings"
+    "time"
+)
+
+var logCSVHeader = []string{"timestamp", "url", "statusCode", "responseTime", "ttfb", "success", "attempt", "retried", "error", "errorClass", "remoteAddr", "finalUrl"}
+
+var downtimeCSVHeader = []string{"url", "startTime", "endTime", "duration", "ongoing", "statusCode", "errorDetail", "upstreamDown", "incidentId"}
+
+// HandleGetLogsCSV serves the same logs as HandleGetLogs, with the same parameters, as CSV
+func (um *UptimeMonitor) HandleGetLogsCSV(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    logs, ok := um.queryLogs(w, r)
+    if !ok {
+        return
+    }
+
+    writer := startCSV(w, "logs", r.URL.Query().Get("url"), logCSVHeader)
+    for _, log := range logs {
+        writer.Write([]string{
+            log.Timestamp.Format(time.RFC3339),
+            log.URL,
+            strconv.Itoa(log.StatusCode),
+            strconv.FormatInt(log.ResponseTime, 10),
+            strconv.FormatInt(log.TTFB, 10),
+            strconv.FormatBool(log.Success),
+            strconv.Itoa(log.Attempt),
+            strconv.FormatBool(log.Retried),
+            log.Error,
+            log.ErrorClass,
+            log.RemoteAddr,
+            log.FinalURL,
+        })
+    }
+    writer.Flush()
+}
+
+// HandleGetDowntimesCSV serves the same downtimes as HandleGetDowntimes as CSV. The end
+// time of an ongoing downtime is left empty.
+func (um *UptimeMonitor) HandleGetDowntimesCSV(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    url := r.URL.Query().Get("url")
+    if url == "" {
+        writeError(w, "URL parameter is required", http.StatusBadRequest)
+        return
+    }
+
+    writer := startCSV(w, "downtimes", url, downtimeCSVHeader)
+    for _, downtime := range um.GetDowntimes(url) {
+        end := ""
+        if !downtime.EndTime.IsZero() {
+            end = downtime.EndTime.Format(time.RFC3339)
+        }
+        writer.Write([]string{
+            downtime.URL,
+            downtime.StartTime.Format(time.RFC3339),
+            end,
+            downtime.Duration,
+            strconv.FormatBool(downtime.Ongoing),
+            strconv.Itoa(downtime.StatusCode),
+            downtime.ErrorDetail,
+            strings.Join(downtime.UpstreamDown, " "),
+            downtime.IncidentID,
+        })
+    }
+    writer.Flush()
+}
+
+// startCSV sets the CSV headers, naming the download after kind and the URL's host, and writes the header row
+func startCSV(w http.ResponseWriter, kind, rawURL string, header []string) *csv.Writer {
+    name := kind
+    if target, err := url.Parse(rawURL); err == nil && target.Hostname() != "" {
+        name += "-" + target.Hostname()
+    }
+    w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+    w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", strings.ReplaceAll(name, ":", "_")+".csv"))
+
+    writer := csv.NewWriter(w)
+    writer.Write(header)
+    return writer
+}
@@ -0,0 +1,74 @@
+package entity
+
+import (
+    "encoding/csv"
+    "net/http"
+    "net/http/httptest"
+    "net/url"
+    "testing"
+    "time"
+)
+
+func TestHandleGetLogsCSV(t *testing.T) {
+    um := NewUptimeMonitor()
+    const target = "https://example.com:8443/"
+    start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    um.mu.Lock()
+    um.appendLogLocked(LogEntry{URL: target, StatusCode: 200, ResponseTime: 12, Success: true, Timestamp: start})
+    um.appendLogLocked(LogEntry{URL: target, StatusCode: 502, Success: false, Error: `bad gateway, "upstream"`, Timestamp: start.Add(time.Minute)})
+    um.mu.Unlock()
+
+    rec := httptest.NewRecorder()
+    um.HandleGetLogsCSV(rec, httptest.NewRequest(http.MethodGet, "/monitor/logs.csv?"+url.Values{"url": {target}, "limit": {"1"}}.Encode(), nil))
+    if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="logs-example.com.csv"` {
+        t.Errorf("Content-Disposition = %q", got)
+    }
+    if rec.Header().Get("X-Total-Count") != "2" {
+        t.Errorf("X-Total-Count = %q, want 2", rec.Header().Get("X-Total-Count"))
+    }
+
+    records, err := csv.NewReader(rec.Body).ReadAll()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(records) != 2 || len(records[0]) != len(logCSVHeader) || records[0][0] != "timestamp" {
+        t.Fatalf("CSV = %q, want the header and one row", records)
+    }
+    if row := records[1]; len(row) != len(logCSVHeader) || row[2] != "502" || row[5] != "false" || row[8] != `bad gateway, "upstream"` {
+        t.Errorf("row = %q, want the newest log with its error quoted intact", row)
+    }
+
+    rec = httptest.NewRecorder()
+    um.HandleGetLogsCSV(rec, httptest.NewRequest(http.MethodGet, "/monitor/logs.csv", nil))
+    if rec.Code != http.StatusBadRequest {
+        t.Errorf("status without a URL = %d, want 400", rec.Code)
+    }
+}
+
+func TestHandleGetDowntimesCSV(t *testing.T) {
+    um := NewUptimeMonitor()
+    const target = "https://example.com/"
+    start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    um.mu.Lock()
+    um.downtimes[target] = []DowntimeEntry{
+        {URL: target, StartTime: start, EndTime: start.Add(5 * time.Minute), Duration: "5m0s", StatusCode: 503, UpstreamDown: []string{"https://a.example.com/", "https://b.example.com/"}},
+        {URL: target, StartTime: start.Add(time.Hour), StatusCode: 500},
+    }
+    um.mu.Unlock()
+
+    rec := httptest.NewRecorder()
+    um.HandleGetDowntimesCSV(rec, httptest.NewRequest(http.MethodGet, "/monitor/downtimes.csv?url="+url.QueryEscape(target), nil))
+    records, err := csv.NewReader(rec.Body).ReadAll()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(records) != 3 {
+        t.Fatalf("CSV = %q, want the header and two rows", records)
+    }
+    if row := records[1]; row[2] != start.Add(5*time.Minute).Format(time.RFC3339) || row[4] != "false" || row[7] != "https://a.example.com/ https://b.example.com/" {
+        t.Errorf("closed downtime row = %q", row)
+    }
+    if row := records[2]; row[2] != "" || row[4] != "true" || row[3] == "" {
+        t.Errorf("ongoing downtime row = %q, want no end time and a live duration", row)
+    }
+}
@@ -0,0 +1,220 @@
+package entity
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "log/slog"
+    "net/http"
+    "os"
+    "strconv"
+    "sync"
+    "time"
+)
+
+// Default delivery policy for alerters
+const (
+    defaultNotifyAttempts = 3
+    defaultNotifyBackoff  = time.Second
+)
+
+// DeadLetter is a notification that could not be delivered after every attempt
+type DeadLetter struct {
+    ID       int64         `json:"id"`
+    Alerter  string        `json:"alerter"`
+    Event    DowntimeEvent `json:"event"`
+    Error    string        `json:"error"`
+    Attempts int           `json:"attempts"`
+    FailedAt time.Time     `json:"failedAt"`
+}
+
+// deadLetterQueue keeps undeliverable notifications, optionally persisted to a JSON file
+type deadLetterQueue struct {
+    mu      sync.Mutex
+    path    string
+    nextID  int64
+    letters []DeadLetter
+}
+
+// loadDeadLetterQueue opens the queue stored at path; a missing file starts an empty queue
+func loadDeadLetterQueue(path string) (*deadLetterQueue, error) {
+    q := &deadLetterQueue{path: path, nextID: 1}
+    if path == "" {
+        return q, nil
+    }
+
+    data, err := os.ReadFile(path)
+    if errors.Is(err, os.ErrNotExist) {
+        return q, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    if err := json.Unmarshal(data, &q.letters); err != nil {
+        return nil, fmt.Errorf("reading dead-letter file %s: %w", path, err)
+    }
+    for _, letter := range q.letters {
+        if letter.ID >= q.nextID {
+            q.nextID = letter.ID + 1
+        }
+    }
+    return q, nil
+}
+
+func (q *deadLetterQueue) add(letter DeadLetter) {
+    q.mu.Lock()
+    defer q.mu.Unlock()
+
+    letter.ID = q.nextID
+    q.nextID++
+    q.letters = append(q.letters, letter)
+    q.saveLocked()
+}
+
+func (q *deadLetterQueue) list() []DeadLetter {
+    q.mu.Lock()
+    defer q.mu.Unlock()
+
+    return append([]DeadLetter(nil), q.letters...)
+}
+
+// take removes and returns the letter with the given ID, or every letter when id is zero
+func (q *deadLetterQueue) take(id int64) []DeadLetter {
+    q.mu.Lock()
+    defer q.mu.Unlock()
+
+    var taken, kept []DeadLetter
+    for _, letter := range q.letters {
+        if id == 0 || letter.ID == id {
+            taken = append(taken, letter)
+        } else {
+            kept = append(kept, letter)
+        }
+    }
+    q.letters = kept
+    q.saveLocked()
+    return taken
+}
+
+func (q *deadLetterQueue) saveLocked() {
+    if q.path == "" {
+        return
+    }
+    data, err := json.MarshalIndent(q.letters, "", "  ")
+    if err == nil {
+        err = os.WriteFile(q.path, data, 0o600)
+    }
+    if err != nil {
+        slog.Error("Failed to persist dead-letter queue", "path", q.path, "error", err)
+    }
+}
+
+// WithDeadLetterFile persists undeliverable notifications to path so they survive restarts
+func WithDeadLetterFile(path string) Option {
+    return func(um *UptimeMonitor) {
+        q, err := loadDeadLetterQueue(path)
+        if err != nil {
+            slog.Warn("Dead-letter file unusable, keeping dead letters in memory", "error", err)
+            return
+        }
+        um.deadLetters = q
+    }
+}
+
+// WithNotifyRetries sets how many times a notification is attempted per alerter and the pause between attempts
+func WithNotifyRetries(attempts int, backoff time.Duration) Option {
+    return func(um *UptimeMonitor) {
+        um.notifyTries = attempts
+        um.notifyWait = backoff
+    }
+}
+
+// alerterName identifies an alerter in the dead-letter queue
+func alerterName(alerter Alerter) string {
+    if named, ok := alerter.(interface{ Name() string }); ok {
+        return named.Name()
+    }
+    return fmt.Sprintf("%T", alerter)
+}
+
+// deliver sends the event with retries and dead-letters it once every attempt has failed
+func (um *UptimeMonitor) deliver(alerter Alerter, event DowntimeEvent) {
+    attempts := um.notifyTries
+    if attempts < 1 {
+        attempts = 1
+    }
+
+    var err error
+    for attempt := 1; attempt <= attempts; attempt++ {
+        if err = alerter.Notify(event); err == nil {
+            return
+        }
+        if attempt < attempts {
+            time.Sleep(um.notifyWait * time.Duration(attempt))
+        }
+    }
+
+    slog.Error("Failed to deliver event", "type", event.Type, "url", event.URL, "attempts", attempts, "error", err)
+    um.deadLetters.add(DeadLetter{
+        Alerter:  alerterName(alerter),
+        Event:    event,
+        Error:    err.Error(),
+        Attempts: attempts,
+        FailedAt: time.Now(),
+    })
+}
+
+func (um *UptimeMonitor) GetDeadLetters() []DeadLetter {
+    return um.deadLetters.list()
+}
+
+// ReplayDeadLetters redelivers the dead letter with the given ID, or all of them when id is zero.
+// Letters whose alerter is no longer registered are dropped. Failed replays are dead-lettered again.
+func (um *UptimeMonitor) ReplayDeadLetters(id int64) int {
+    um.mu.RLock()
+    alerters := append([]Alerter(nil), um.alerters...)
+    um.mu.RUnlock()
+
+    letters := um.deadLetters.take(id)
+    for _, letter := range letters {
+        for _, alerter := range alerters {
+            if alerterName(alerter) == letter.Alerter {
+                go um.deliver(alerter, letter.Event)
+            }
+        }
+    }
+    return len(letters)
+}
+
+func (um *UptimeMonitor) HandleGetDeadLetters(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    json.NewEncoder(w).Encode(um.GetDeadLetters())
+}
+
+func (um *UptimeMonitor) HandleReplayDeadLetters(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodPost {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    // Without an id every dead letter is replayed
+    var id int64
+    if value := r.URL.Query().Get("id"); value != "" {
+        var err error
+        if id, err = strconv.ParseInt(value, 10, 64); err != nil || id <= 0 {
+            writeError(w, "id must be a positive integer", http.StatusBadRequest)
+            return
+        }
+    }
+
+    replayed := um.ReplayDeadLetters(id)
+    if id != 0 && replayed == 0 {
+        writeError(w, fmt.Sprintf("dead letter %d not found", id), http.StatusNotFound)
+        return
+    }
+    json.NewEncoder(w).Encode(map[string]int{"replayed": replayed})
+}
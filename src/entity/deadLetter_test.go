@@ -0,0 +1,102 @@
+package entity
+
+import (
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "path/filepath"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+// flakyAlerter fails its first failures notifications, then passes events to delivered
+type flakyAlerter struct {
+    failures  int64
+    calls     atomic.Int64
+    delivered chan DowntimeEvent
+}
+
+func (a *flakyAlerter) Notify(event DowntimeEvent) error {
+    if a.calls.Add(1) <= a.failures {
+        return errors.New("webhook unavailable")
+    }
+    a.delivered <- event
+    return nil
+}
+
+func (a *flakyAlerter) Name() string {
+    return "flaky"
+}
+
+func TestDeliverRetries(t *testing.T) {
+    um := NewUptimeMonitor(WithNotifyRetries(3, time.Millisecond))
+    alerter := &flakyAlerter{failures: 2, delivered: make(chan DowntimeEvent, 1)}
+
+    um.deliver(alerter, DowntimeEvent{Type: EventDown, URL: "https://example.com/"})
+    if alerter.calls.Load() != 3 || len(alerter.delivered) != 1 {
+        t.Errorf("%d attempts, %d delivered, want delivery on the third attempt", alerter.calls.Load(), len(alerter.delivered))
+    }
+    if letters := um.GetDeadLetters(); len(letters) != 0 {
+        t.Errorf("dead letters = %+v, want none", letters)
+    }
+}
+
+func TestDeadLetters(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "dead-letters.json")
+    um := NewUptimeMonitor(WithNotifyRetries(2, time.Millisecond), WithDeadLetterFile(path))
+    alerter := &flakyAlerter{failures: 4, delivered: make(chan DowntimeEvent, 2)}
+    um.AddAlerter(alerter)
+
+    um.deliver(alerter, DowntimeEvent{Type: EventDown, URL: "https://a.example.com/"})
+    um.deliver(alerter, DowntimeEvent{Type: EventDown, URL: "https://b.example.com/"})
+    letters := um.GetDeadLetters()
+    if len(letters) != 2 || letters[0].ID != 1 || letters[1].ID != 2 || letters[0].Alerter != "flaky" || letters[0].Attempts != 2 {
+        t.Fatalf("dead letters = %+v", letters)
+    }
+
+    // The queue survives a restart and keeps numbering after the stored letters
+    restarted := NewUptimeMonitor(WithDeadLetterFile(path))
+    if letters := restarted.GetDeadLetters(); len(letters) != 2 || letters[1].Event.URL != "https://b.example.com/" {
+        t.Fatalf("reloaded dead letters = %+v", letters)
+    }
+    restarted.deadLetters.add(DeadLetter{Alerter: "flaky"})
+    if letters := restarted.GetDeadLetters(); letters[2].ID != 3 {
+        t.Errorf("new dead letter got ID %d, want 3", letters[2].ID)
+    }
+
+    // The alerter recovered, so replaying the second letter delivers it
+    if replayed := um.ReplayDeadLetters(2); replayed != 1 {
+        t.Errorf("replayed %d letters, want 1", replayed)
+    }
+    select {
+    case event := <-alerter.delivered:
+        if event.URL != "https://b.example.com/" {
+            t.Errorf("replayed event for %s", event.URL)
+        }
+    case <-time.After(2 * time.Second):
+        t.Fatal("replayed letter was not delivered")
+    }
+    if letters := um.GetDeadLetters(); len(letters) != 1 || letters[0].ID != 1 {
+        t.Errorf("dead letters after replay = %+v, want only the first", letters)
+    }
+}
+
+func TestHandleReplayDeadLetters(t *testing.T) {
+    um := NewUptimeMonitor()
+    for _, query := range []struct {
+        target string
+        status int
+    }{
+        {"/deadletters/replay?id=abc", http.StatusBadRequest},
+        {"/deadletters/replay?id=0", http.StatusBadRequest},
+        {"/deadletters/replay?id=7", http.StatusNotFound},
+        {"/deadletters/replay", http.StatusOK},
+    } {
+        rec := httptest.NewRecorder()
+        um.HandleReplayDeadLetters(rec, httptest.NewRequest(http.MethodPost, query.target, nil))
+        if rec.Code != query.status {
+            t.Errorf("POST %s = %d, want %d", query.target, rec.Code, query.status)
+        }
+    }
+}
@@ -0,0 +1,97 @@
+package entity
+
+import (
+    "crypto/subtle"
+    "encoding/json"
+    "net/http"
+    "runtime"
+    "sort"
+    "sync/atomic"
+    "time"
+)
+
+// MonitorDiagnostics describes the goroutine backing a single monitor
+type MonitorDiagnostics struct {
+    URL      string        `json:"url"`
+    Interval time.Duration `json:"interval"`
+    State    string        `json:"state"`              // "checking" while a check is in flight, otherwise "idle"
+    InFlight int           `json:"inFlight,omitempty"` // checks in flight, e.g. a scheduled and an on-demand one
+}
+
+// Diagnostics is a point-in-time view of the monitor's resource usage
+type Diagnostics struct {
+    ActiveMonitors    int                  `json:"activeMonitors"`
+    MonitorGoroutines int64                `json:"monitorGoroutines"`
+    TotalGoroutines   int                  `json:"totalGoroutines"`
+    HeapAllocBytes    uint64               `json:"heapAllocBytes"`
+    HeapObjects       uint64               `json:"heapObjects"`
+    SysBytes          uint64               `json:"sysBytes"`
+    NumGC             uint32               `json:"numGC"`
+    LogEntries        int                  `json:"logEntries"`
+    DowntimeEntries   int                  `json:"downtimeEntries"`
+    Monitors          []MonitorDiagnostics `json:"monitors"`
+}
+
+func (um *UptimeMonitor) GetDiagnostics() Diagnostics {
+    var mem runtime.MemStats
+    runtime.ReadMemStats(&mem)
+
+    um.mu.RLock()
+    defer um.mu.RUnlock()
+
+    diag := Diagnostics{
+        ActiveMonitors:    len(um.monitors),
+        MonitorGoroutines: atomic.LoadInt64(&um.activeLoops),
+        TotalGoroutines:   runtime.NumGoroutine(),
+        HeapAllocBytes:    mem.HeapAlloc,
+        HeapObjects:       mem.HeapObjects,
+        SysBytes:          mem.Sys,
+        NumGC:             mem.NumGC,
+        LogEntries:        um.logCount,
+        Monitors:          make([]MonitorDiagnostics, 0, len(um.monitors)),
+    }
+    for _, downtimes := range um.downtimes {
+        diag.DowntimeEntries += len(downtimes)
+    }
+    for url, monitor := range um.monitors {
+        state := "idle"
+        if um.checking[url] > 0 {
+            state = "checking"
+        }
+        diag.Monitors = append(diag.Monitors, MonitorDiagnostics{
+            URL:      url,
+            Interval: monitor.Interval,
+            State:    state,
+            InFlight: um.checking[url],
+        })
+    }
+    sort.Slice(diag.Monitors, func(i, j int) bool {
+        return diag.Monitors[i].URL < diag.Monitors[j].URL
+    })
+    return diag
+}
+
+func (um *UptimeMonitor) HandleDebugMonitors(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    if !um.debugAuthorized(r) {
+        writeError(w, "Unauthorized", http.StatusUnauthorized)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(um.GetDiagnostics())
+}
+
+// debugAuthorized reports whether the request carries the debug token, comparing in constant time
+// so the response time does not reveal how much of a guess matched
+func (um *UptimeMonitor) debugAuthorized(r *http.Request) bool {
+    if um.debugToken == "" {
+        return true
+    }
+    given := []byte(r.Header.Get("Authorization"))
+    return subtle.ConstantTimeCompare(given, []byte("Bearer "+um.debugToken)) == 1
+}
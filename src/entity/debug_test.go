@@ -0,0 +1,38 @@
+package entity
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+)
+
+func TestDebugMonitorsRequiresToken(t *testing.T) {
+    um := NewUptimeMonitor(WithDebugToken("s3cret"))
+    tests := []struct {
+        authorization string
+        want          int
+    }{
+        {"Bearer s3cret", http.StatusOK},
+        {"", http.StatusUnauthorized},
+        {"Bearer s3cre", http.StatusUnauthorized},
+        {"Bearer s3cretx", http.StatusUnauthorized},
+        {"s3cret", http.StatusUnauthorized},
+    }
+    for _, tt := range tests {
+        req := httptest.NewRequest(http.MethodGet, "/debug/monitors", nil)
+        if tt.authorization != "" {
+            req.Header.Set("Authorization", tt.authorization)
+        }
+        rec := httptest.NewRecorder()
+        um.HandleDebugMonitors(rec, req)
+        if rec.Code != tt.want {
+            t.Errorf("Authorization %q: status %d, want %d", tt.authorization, rec.Code, tt.want)
+        }
+    }
+
+    rec := httptest.NewRecorder()
+    NewUptimeMonitor().HandleDebugMonitors(rec, httptest.NewRequest(http.MethodGet, "/debug/monitors", nil))
+    if rec.Code != http.StatusOK {
+        t.Errorf("without a configured token: status %d, want 200", rec.Code)
+    }
+}
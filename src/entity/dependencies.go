@@ -0,0 +1,25 @@
+package entity
+
+import "fmt"
+
+// validateDependencies rejects monitors that depend on themselves
+func validateDependencies(monitor Monitor) error {
+    for _, dependency := range monitor.DependsOn {
+        if dependency == monitor.URL {
+            return fmt.Errorf("monitor %s cannot depend on itself", monitor.URL)
+        }
+    }
+    return nil
+}
+
+// downDependenciesLocked lists the dependencies of url that currently have an open downtime.
+// Dependencies that are not monitored never count as down. Callers must hold um.mu.
+func (um *UptimeMonitor) downDependenciesLocked(url string) []string {
+    var down []string
+    for _, dependency := range um.monitors[url].DependsOn {
+        if downtime := um.getLastDowntime(dependency); downtime != nil && downtime.EndTime.IsZero() {
+            down = append(down, dependency)
+        }
+    }
+    return down
+}
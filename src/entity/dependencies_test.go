@@ -0,0 +1,61 @@
+package entity
+
+import (
+    "testing"
+    "time"
+)
+
+func TestValidateDependencies(t *testing.T) {
+    um := NewUptimeMonitor()
+    defer um.Close()
+
+    err := um.AddMonitorConfig(Monitor{URL: "https://example.com", Interval: time.Hour, DependsOn: []string{"https://EXAMPLE.com/"}})
+    if err == nil {
+        t.Error("expected an error for a monitor depending on itself")
+    }
+    if err := um.AddMonitorConfig(Monitor{URL: "https://example.com", Interval: time.Hour, DependsOn: []string{"https://db.example.com"}}); err != nil {
+        t.Fatal(err)
+    }
+    if monitors := um.ListMonitors(); monitors[0].DependsOn[0] != "https://db.example.com/" {
+        t.Errorf("dependency = %q, want it normalized like monitor URLs", monitors[0].DependsOn[0])
+    }
+}
+
+func TestDependencySuppressesAlerts(t *testing.T) {
+    um := NewUptimeMonitor()
+    alerter := newRecordingAlerter()
+    um.AddAlerter(alerter)
+
+    const app, db, cache = "https://app.example.com/", "https://db.example.com/", "https://cache.example.com/"
+    um.monitors[app] = Monitor{URL: app, DependsOn: []string{db, cache}}
+    um.monitors[db] = Monitor{URL: db}
+    um.monitors[cache] = Monitor{URL: cache}
+
+    now := time.Now()
+    um.recordCheck(LogEntry{URL: db, Success: false, Error: "connection refused", Timestamp: now})
+    alerter.expect(t, EventDown)
+
+    // The app fails while its database is down: the downtime is recorded, the alert is not sent
+    um.recordCheck(LogEntry{URL: app, Success: false, StatusCode: 500, Timestamp: now.Add(time.Second)})
+    alerter.expectNone(t)
+    downtimes := um.GetDowntimes(app)
+    if len(downtimes) != 1 || len(downtimes[0].UpstreamDown) != 1 || downtimes[0].UpstreamDown[0] != db {
+        t.Fatalf("app downtimes = %+v, want one naming the database", downtimes)
+    }
+
+    // Its recovery is not announced either, as its failure never was
+    um.recordCheck(LogEntry{URL: app, Success: true, StatusCode: 200, Timestamp: now.Add(2 * time.Second)})
+    alerter.expectNone(t)
+
+    // Once the database is back, the app's failures alert again
+    um.recordCheck(LogEntry{URL: db, Success: true, Timestamp: now.Add(3 * time.Second)})
+    alerter.expect(t, EventUp)
+    um.mu.RLock()
+    down := um.downDependenciesLocked(app)
+    um.mu.RUnlock()
+    if len(down) != 0 {
+        t.Errorf("down dependencies = %v after the database recovered", down)
+    }
+    um.recordCheck(LogEntry{URL: app, Success: false, StatusCode: 500, Timestamp: now.Add(4 * time.Second)})
+    alerter.expect(t, EventDown)
+}
@@ -0,0 +1,85 @@
+package entity
+
+import (
+    "fmt"
+    "time"
+)
+
+// EventDigest is a periodic summary of the events collected in digest mode
+const EventDigest = "digest"
+
+// digestBuffer collects events between digests; guarded by um.mu
+type digestBuffer struct {
+    interval  time.Duration
+    heartbeat bool // send a digest even when the window had no events
+    events    []DowntimeEvent
+    since     time.Time
+}
+
+// WithDigest replaces individual alerts with one summary notification every interval listing
+// the window's events. With heartbeat set, empty windows still send a digest, so a silent
+// alerting pipeline can be told apart from a quiet period.
+func WithDigest(interval time.Duration, heartbeat bool) Option {
+    return func(um *UptimeMonitor) {
+        um.digest = &digestBuffer{interval: interval, heartbeat: heartbeat}
+    }
+}
+
+// runDigest sends a digest at the end of every window until the monitor is closed, when the
+// events of the unfinished window are sent right away
+func (um *UptimeMonitor) runDigest() {
+    um.mu.Lock()
+    um.digest.since = um.now()
+    um.mu.Unlock()
+
+    ticker := time.NewTicker(um.digest.interval)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-ticker.C:
+            um.flushDigest()
+        case <-um.closed:
+            um.flushDigest()
+            return
+        }
+    }
+}
+
+// flushDigest delivers the collected events as one digest and starts a new window
+func (um *UptimeMonitor) flushDigest() {
+    um.mu.Lock()
+    defer um.mu.Unlock()
+
+    events := um.digest.events
+    start, end := um.digest.since, um.now()
+    um.digest.events, um.digest.since = nil, end
+    if len(events) == 0 && !um.digest.heartbeat {
+        return
+    }
+
+    digest := DowntimeEvent{
+        Type:      EventDigest,
+        Timestamp: end,
+        Duration:  end.Sub(start).String(),
+        Message:   digestMessage(events, start, end),
+        Events:    events,
+    }
+    for _, alerter := range um.alerters {
+        go um.deliver(alerter, digest)
+    }
+}
+
+// digestMessage summarizes the window in one line per event
+func digestMessage(events []DowntimeEvent, start, end time.Time) string {
+    message := fmt.Sprintf("%d events between %s and %s", len(events), start.Format(time.RFC3339), end.Format(time.RFC3339))
+    for _, event := range events {
+        message += fmt.Sprintf("\n%s %s %s", event.Timestamp.Format(time.RFC3339), event.Type, event.URL)
+        if event.Duration != "" {
+            message += " after " + event.Duration
+        }
+        if event.Error != "" {
+            message += ": " + event.Error
+        }
+    }
+    return message
+}
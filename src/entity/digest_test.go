@@ -0,0 +1,36 @@
+package entity
+
+import (
+    "testing"
+    "time"
+)
+
+func TestCloseSendsPendingDigest(t *testing.T) {
+    um := NewUptimeMonitor(WithDigest(time.Hour, false))
+    alerter := newRecordingAlerter()
+    um.AddAlerter(alerter)
+
+    um.mu.Lock()
+    um.notifyLocked(DowntimeEvent{Type: EventDown, URL: "https://example.com/", Timestamp: time.Now()})
+    um.mu.Unlock()
+    alerter.expectNone(t)
+
+    um.Close()
+    um.Close() // closing twice is harmless
+    digest := alerter.expect(t, EventDigest)
+    if len(digest.Events) != 1 || digest.Events[0].Type != EventDown {
+        t.Errorf("digest events = %+v, want the pending down event", digest.Events)
+    }
+    alerter.expectNone(t)
+}
+
+func TestCloseStopsMonitors(t *testing.T) {
+    um := NewUptimeMonitor()
+    if err := um.AddMonitor("http://127.0.0.1:1/", time.Hour); err != nil {
+        t.Fatal(err)
+    }
+    um.Close()
+    if active := um.GetHealth().ActiveMonitors; active != 0 {
+        t.Errorf("%d monitors still active after Close", active)
+    }
+}
@@ -0,0 +1,137 @@
+package entity
+
+import (
+    "context"
+    "fmt"
+    "net"
+    "net/url"
+    "sort"
+    "strings"
+    "time"
+)
+
+// DNSRecord is one resolved record of a monitored hostname
+type DNSRecord struct {
+    Type  string `json:"type"` // A, AAAA or CNAME
+    Value string `json:"value"`
+    TTL   uint32 `json:"ttl,omitempty"` // zero when the resolver does not expose TTLs
+}
+
+// DNSResolver looks up the records of a hostname
+type DNSResolver interface {
+    LookupRecords(ctx context.Context, host string) ([]DNSRecord, error)
+}
+
+// systemResolver uses the system resolver, which does not expose TTLs
+type systemResolver struct{}
+
+func (systemResolver) LookupRecords(ctx context.Context, host string) ([]DNSRecord, error) {
+    var records []DNSRecord
+    if cname, err := net.DefaultResolver.LookupCNAME(ctx, host); err == nil && cname != "" &&
+        strings.TrimSuffix(cname, ".") != strings.TrimSuffix(host, ".") {
+        records = append(records, DNSRecord{Type: "CNAME", Value: cname})
+    }
+
+    ips, err := net.DefaultResolver.LookupIP(ctx, "ip", host)
+    if err != nil {
+        return nil, err
+    }
+    for _, ip := range ips {
+        recordType := "AAAA"
+        if ip.To4() != nil {
+            recordType = "A"
+        }
+        records = append(records, DNSRecord{Type: recordType, Value: ip.String()})
+    }
+    return records, nil
+}
+
+// checkDNS resolves a dns:// monitor's hostname and compares the records with those of the
+// previous check. The records become the new baseline only once the check's final attempt is
+// recorded, so a retry compares against the same baseline and a change is not missed.
+func (um *UptimeMonitor) checkDNS(ctx context.Context, monitor Monitor) LogEntry {
+    entry := LogEntry{URL: monitor.URL}
+
+    target, err := url.Parse(monitor.URL)
+    if err != nil {
+        entry.Timestamp = um.now()
+        entry.Error = err.Error()
+        return entry
+    }
+
+    timeout := monitor.timeout()
+    if monitor.CheckBudget > 0 {
+        timeout = monitor.CheckBudget
+    }
+    ctx, cancel := context.WithTimeout(ctx, timeout)
+    defer cancel()
+
+    start := time.Now()
+    records, err := um.dnsResolver.LookupRecords(ctx, target.Hostname())
+    entry.Timestamp = um.now()
+    entry.ResponseTime = time.Since(start).Milliseconds()
+    if err != nil {
+        entry.Error = err.Error()
+        entry.ErrorClass = classifyError(err)
+        return entry
+    }
+
+    sortDNSRecords(records)
+    entry.DNSRecords = records
+    entry.Success = true
+
+    um.mu.RLock()
+    var previous []DNSRecord
+    if state, ok := um.states[monitor.URL]; ok {
+        previous = state.dnsRecords
+    }
+    um.mu.RUnlock()
+
+    if previous != nil && !equalDNSRecords(previous, records) {
+        entry.DNSChanged = true
+        change := fmt.Sprintf("DNS records changed from %s to %s", formatDNSRecords(previous), formatDNSRecords(records))
+        if monitor.FailOnDNSChange {
+            entry.Success = false
+            entry.Error = change
+        } else {
+            entry.Warnings = append(entry.Warnings, change)
+        }
+    }
+    return entry
+}
+
+func sortDNSRecords(records []DNSRecord) {
+    sort.Slice(records, func(i, j int) bool {
+        if records[i].Type != records[j].Type {
+            return records[i].Type < records[j].Type
+        }
+        return records[i].Value < records[j].Value
+    })
+}
+
+// equalDNSRecords compares sorted record sets. TTLs only count when both sides know them.
+func equalDNSRecords(a, b []DNSRecord) bool {
+    if len(a) != len(b) {
+        return false
+    }
+    for i := range a {
+        if a[i].Type != b[i].Type || a[i].Value != b[i].Value {
+            return false
+        }
+        if a[i].TTL != 0 && b[i].TTL != 0 && a[i].TTL != b[i].TTL {
+            return false
+        }
+    }
+    return true
+}
+
+func formatDNSRecords(records []DNSRecord) string {
+    parts := make([]string, len(records))
+    for i, r := range records {
+        parts[i] = r.Type + " " + r.Value
+        if r.TTL != 0 {
+            parts[i] += fmt.Sprintf(" (ttl %d)", r.TTL)
+        }
+    }
+    return "[" + strings.Join(parts, ", ") + "]"
+}
@@ -0,0 +1,86 @@
+package entity
+
+import (
+    "context"
+    "sync"
+    "testing"
+)
+
+// fakeResolver answers every lookup with its current records
+type fakeResolver struct {
+    mu      sync.Mutex
+    records []DNSRecord
+}
+
+func (r *fakeResolver) set(records ...DNSRecord) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    r.records = records
+}
+
+func (r *fakeResolver) LookupRecords(ctx context.Context, host string) ([]DNSRecord, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    return append([]DNSRecord(nil), r.records...), nil
+}
+
+func TestDNSChangeSurvivesRetries(t *testing.T) {
+    resolver := &fakeResolver{}
+    um := NewUptimeMonitor(WithDNSResolver(resolver), WithRetryJitter(0))
+    monitor := Monitor{URL: "dns://example.com", Type: MonitorTypeDNS, Retries: 2, FailOnDNSChange: true}
+
+    resolver.set(DNSRecord{Type: "A", Value: "192.0.2.1"})
+    if entry := um.checkURL(monitor); !entry.Success {
+        t.Fatalf("first check failed: %s", entry.Error)
+    }
+
+    resolver.set(DNSRecord{Type: "A", Value: "192.0.2.2"})
+    entry := um.checkURL(monitor)
+    if entry.Success || !entry.DNSChanged {
+        t.Fatalf("check after the change = %+v, want a failure reporting the change", entry)
+    }
+    for _, log := range um.GetLogs(monitor.URL)[1:] {
+        if !log.DNSChanged {
+            t.Errorf("attempt %d compared against the changed records: %+v", log.Attempt, log)
+        }
+    }
+
+    // The change is reported once; the new records are the baseline from now on
+    if entry := um.checkURL(monitor); !entry.Success || entry.DNSChanged {
+        t.Errorf("check after the reported change = %+v, want success", entry)
+    }
+}
+
+func TestDNSChangeWarning(t *testing.T) {
+    resolver := &fakeResolver{}
+    um := NewUptimeMonitor(WithDNSResolver(resolver))
+    monitor := Monitor{URL: "dns://example.com", Type: MonitorTypeDNS}
+
+    resolver.set(DNSRecord{Type: "A", Value: "192.0.2.1"})
+    um.checkURL(monitor)
+    resolver.set(DNSRecord{Type: "A", Value: "192.0.2.1"}, DNSRecord{Type: "AAAA", Value: "2001:db8::1"})
+    entry := um.checkURL(monitor)
+    if !entry.Success || !entry.DNSChanged || len(entry.Warnings) != 1 {
+        t.Errorf("entry = %+v, want a successful check warning about the change", entry)
+    }
+}
+
+func TestEqualDNSRecords(t *testing.T) {
+    a := []DNSRecord{{Type: "A", Value: "192.0.2.1", TTL: 300}}
+    tests := []struct {
+        name string
+        b    []DNSRecord
+        want bool
+    }{
+        {"same", []DNSRecord{{Type: "A", Value: "192.0.2.1", TTL: 300}}, true},
+        {"unknown ttl", []DNSRecord{{Type: "A", Value: "192.0.2.1"}}, true},
+        {"ttl changed", []DNSRecord{{Type: "A", Value: "192.0.2.1", TTL: 60}}, false},
+        {"value changed", []DNSRecord{{Type: "A", Value: "192.0.2.2", TTL: 300}}, false},
+        {"record added", []DNSRecord{{Type: "A", Value: "192.0.2.1"}, {Type: "A", Value: "192.0.2.2"}}, false},
+    }
+    for _, tt := range tests {
+        if got := equalDNSRecords(a, tt.b); got != tt.want {
+            t.Errorf("%s: equalDNSRecords = %v, want %v", tt.name, got, tt.want)
+        }
+    }
+}
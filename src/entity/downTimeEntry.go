@@ -7,7 +7,14 @@ type DowntimeEntry struct {
     URL         string    `json:"url"`
     StartTime   time.Time `json:"startTime"`
     EndTime     time.Time `json:"endTime"`
-    Duration    string    `json:"duration"`
+    Duration    string    `json:"duration"` // up to now while the downtime is ongoing
+    Ongoing     bool      `json:"ongoing"`  // set by GetDowntimes for a downtime that has not ended
     StatusCode  int       `json:"statusCode"`
     ErrorDetail string    `json:"errorDetail,omitempty"`
+
+    // UpstreamDown lists the dependencies that were already down when this downtime started
+    UpstreamDown []string `json:"upstreamDown,omitempty"`
+
+    // IncidentID identifies the incident opened for this downtime in an external incident system
+    IncidentID string `json:"incidentId,omitempty"`
 }
\ No newline at end of file
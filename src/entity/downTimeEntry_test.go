@@ -0,0 +1,44 @@
+package entity
+
+import (
+    "encoding/json"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestGetDowntimesOngoing(t *testing.T) {
+    start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    now := start.Add(time.Hour)
+    um := NewUptimeMonitor(WithClock(func() time.Time { return now }))
+
+    const url = "https://example.com/"
+    um.downtimes[url] = []DowntimeEntry{
+        {URL: url, StartTime: start, EndTime: start.Add(5 * time.Minute), Duration: "5m0s"},
+        {URL: url, StartTime: start.Add(48 * time.Minute)},
+    }
+
+    downtimes := um.GetDowntimes(url)
+    if downtimes[0].Ongoing || downtimes[0].Duration != "5m0s" {
+        t.Errorf("closed downtime = %+v", downtimes[0])
+    }
+    if !downtimes[1].Ongoing || downtimes[1].Duration != "12m0s" || !downtimes[1].EndTime.IsZero() {
+        t.Errorf("open downtime = %+v, want ongoing for 12m0s", downtimes[1])
+    }
+    if stored := um.downtimes[url][1]; stored.Ongoing || stored.Duration != "" {
+        t.Errorf("stored downtime was changed: %+v", stored)
+    }
+
+    now = now.Add(3 * time.Minute)
+    if downtimes := um.GetDowntimes(url); downtimes[1].Duration != "15m0s" {
+        t.Errorf("open downtime lasted %s three minutes later, want 15m0s", downtimes[1].Duration)
+    }
+
+    data, err := json.Marshal(downtimes)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !strings.Contains(string(data), `"ongoing":false`) || !strings.Contains(string(data), `"ongoing":true`) {
+        t.Errorf("JSON = %s, want ongoing on every downtime", data)
+    }
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+    "fmt"
+    "net/http"
+    "regexp"
+    "strings"
+)
+
+// defaultPOPPatterns extract the POP code from well-known CDN headers, e.g. the "FRA" of
+// "CF-Ray: 8a1b2c3d4e5f6789-FRA". Headers without a pattern are recorded verbatim.
+var defaultPOPPatterns = map[string]*regexp.Regexp{
+    "cf-ray":      regexp.MustCompile(`-([A-Za-z]{3})$`),
+    "x-served-by": regexp.MustCompile(`-([A-Za-z]{3})$`), // Fastly, e.g. cache-fra19123-FRA
+}
+
+func validatePOP(monitor Monitor) error {
+    if monitor.POPPattern == "" {
+        return nil
+    }
+    if monitor.POPHeader == "" {
+        return fmt.Errorf("popPattern requires popHeader")
+    }
+    pattern, err := regexp.Compile(monitor.POPPattern)
+    if err != nil {
+        return fmt.Errorf("invalid popPattern: %w", err)
+    }
+    if pattern.NumSubexp() > 1 {
+        return fmt.Errorf("popPattern must have at most one capture group")
+    }
+    return nil
+}
+
+// edgePOP extracts the edge location that served the response from the monitor's POP header
+func edgePOP(monitor Monitor, header http.Header) string {
+    if monitor.POPHeader == "" {
+        return ""
+    }
+    value := header.Get(monitor.POPHeader)
+    if value == "" {
+        return ""
+    }
+    // With shielding each cache appends itself, so the last entry is the edge the check reached
+    entries := strings.Split(value, ",")
+    value = strings.TrimSpace(entries[len(entries)-1])
+
+    pattern := defaultPOPPatterns[strings.ToLower(monitor.POPHeader)]
+    if monitor.POPPattern != "" {
+        pattern = regexp.MustCompile(monitor.POPPattern) // validated when the monitor was added
+    }
+    if pattern == nil {
+        return value
+    }
+    match := pattern.FindStringSubmatch(value)
+    switch {
+    case match == nil:
+        return value
+    case len(match) > 1:
+        return strings.ToUpper(match[1])
+    }
+    return match[0]
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+    "net/http"
+    "testing"
+)
+
+func TestEdgePOP(t *testing.T) {
+    tests := []struct {
+        monitor Monitor
+        header  string
+        value   string
+        want    string
+    }{
+        {Monitor{}, "CF-Ray", "8a1b2c3d4e5f6789-FRA", ""},
+        {Monitor{POPHeader: "CF-Ray"}, "CF-Ray", "8a1b2c3d4e5f6789-fra", "FRA"},
+        {Monitor{POPHeader: "X-Served-By"}, "X-Served-By", "cache-iad2120-IAD, cache-fra19123-FRA", "FRA"},
+        {Monitor{POPHeader: "X-Amz-Cf-Pop"}, "X-Amz-Cf-Pop", "FRA56-P5", "FRA56-P5"},
+        {Monitor{POPHeader: "X-Amz-Cf-Pop", POPPattern: `^([A-Z]{3})\d+`}, "X-Amz-Cf-Pop", "FRA56-P5", "FRA"},
+        {Monitor{POPHeader: "X-Edge", POPPattern: `[a-z]+\d`}, "X-Edge", "edge ams1", "ams1"},
+        {Monitor{POPHeader: "CF-Ray"}, "CF-Ray", "8a1b2c3d4e5f6789", "8a1b2c3d4e5f6789"},
+        {Monitor{POPHeader: "CF-Ray"}, "Server", "cloudflare", ""},
+    }
+    for _, test := range tests {
+        header := http.Header{}
+        header.Set(test.header, test.value)
+        if got := edgePOP(test.monitor, header); got != test.want {
+            t.Errorf("edgePOP(%s %q, %s: %s) = %q, want %q", test.monitor.POPHeader, test.monitor.POPPattern, test.header, test.value, got, test.want)
+        }
+    }
+}
+
+func TestValidatePOP(t *testing.T) {
+    for _, monitor := range []Monitor{
+        {POPPattern: `-(\w+)$`},
+        {POPHeader: "CF-Ray", POPPattern: `(`},
+        {POPHeader: "CF-Ray", POPPattern: `(\w+)-(\w+)`},
+    } {
+        if err := validatePOP(monitor); err == nil {
+            t.Errorf("validatePOP(%s %q) succeeded, want an error", monitor.POPHeader, monitor.POPPattern)
+        }
+    }
+    if err := validatePOP(Monitor{POPHeader: "CF-Ray", POPPattern: `-(\w+)$`}); err != nil {
+        t.Error(err)
+    }
+}
+
+func TestStatsPOPs(t *testing.T) {
+    um := NewUptimeMonitor()
+    const url = "https://example.com/"
+    um.mu.Lock()
+    for _, pop := range []string{"FRA", "AMS", "FRA", ""} {
+        um.appendLogLocked(LogEntry{URL: url, Success: true, POP: pop})
+    }
+    um.mu.Unlock()
+
+    if pops := um.GetStats(url).POPs; len(pops) != 2 || pops["FRA"] != 2 || pops["AMS"] != 1 {
+        t.Errorf("POPs = %v, want FRA twice and AMS once", pops)
+    }
+}
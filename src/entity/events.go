@@ -0,0 +1,102 @@
+package entity
+
+import (
+    "log/slog"
+    "time"
+)
+
+// Event types delivered to alerters
+const (
+    EventDown            = "down"           // a new downtime started
+    EventUp              = "up"             // the downtime ended; Duration holds its length
+    EventRemoved         = "removed"        // the monitor was removed automatically after sustained failure
+    EventCacheHitRateLow = "cache_hit_rate" // the recent cache hit rate fell below the monitor's threshold
+)
+
+// defaultNotifyLogContext is the number of recent log entries attached to notifications
+const defaultNotifyLogContext = 5
+
+// DowntimeEvent describes a notable change for a monitored URL
+type DowntimeEvent struct {
+    Type       string    `json:"type"`
+    URL        string    `json:"url"`
+    Timestamp  time.Time `json:"timestamp"`
+    StatusCode int       `json:"statusCode,omitempty"`
+    Error      string    `json:"error,omitempty"`
+    Duration   string    `json:"duration,omitempty"`
+    Message    string    `json:"message,omitempty"` // rendered from the monitor's MessageTemplate
+
+    // UpstreamDown lists dependencies of the URL that were down when the event occurred
+    UpstreamDown []string `json:"upstreamDown,omitempty"`
+
+    // Events lists the events summarized by a digest, oldest first
+    Events []DowntimeEvent `json:"events,omitempty"`
+
+    // RecentLogs holds the latest checks of the URL, oldest first, so responders see the failure pattern
+    RecentLogs []LogEntry `json:"recentLogs,omitempty"`
+}
+
+// Alerter delivers downtime events to an external system
+type Alerter interface {
+    Notify(event DowntimeEvent) error
+}
+
+// AddAlerter registers an alerter that receives every subsequent event
+func (um *UptimeMonitor) AddAlerter(alerter Alerter) {
+    um.mu.Lock()
+    defer um.mu.Unlock()
+
+    um.alerters = append(um.alerters, alerter)
+}
+
+// recentLogsLocked returns up to n of the latest log entries for url, oldest first; callers must hold um.mu
+func (um *UptimeMonitor) recentLogsLocked(url string, n int) []LogEntry {
+    if n <= 0 {
+        return nil
+    }
+
+    logs := um.logs[url]
+    if len(logs) == 0 {
+        return nil
+    }
+    return append([]LogEntry(nil), logs[max(len(logs)-n, 0):]...)
+}
+
+// notifyLocked hands the event to every alerter without blocking the caller,
+// so a slow alerter never delays checks. Alerts about a URL whose dependencies are
+// down are suppressed, as the upstream outage is already being handled; lifecycle
+// events such as removal are still delivered. Down and up alerts come in pairs: a down
+// alert is not repeated while the URL's previous down alert, possibly sent before a
+// restart, has not been followed by an up alert, and an up alert is only sent after a
+// down alert. It reports whether the event was sent. Callers must hold um.mu.
+func (um *UptimeMonitor) notifyLocked(event DowntimeEvent) bool {
+    event.UpstreamDown = um.downDependenciesLocked(event.URL)
+    if len(event.UpstreamDown) > 0 && event.Type != EventRemoved {
+        slog.Info("Suppressed alert", "type", event.Type, "url", event.URL, "upstreamDown", event.UpstreamDown)
+        return false
+    }
+    if event.Type == EventDown && um.notified[event.URL] == EventDown {
+        slog.Info("Suppressed duplicate alert", "url", event.URL, "type", event.Type)
+        return false
+    }
+    if event.Type == EventUp && um.notified[event.URL] != EventDown {
+        slog.Info("Suppressed up alert without a down alert", "url", event.URL, "type", event.Type)
+        return false
+    }
+    if event.Type == EventDown || event.Type == EventUp {
+        um.notified[event.URL] = event.Type
+    }
+
+    if template := um.monitors[event.URL].MessageTemplate; template != "" {
+        event.Message = renderMessage(template, event)
+    }
+    event.RecentLogs = um.recentLogsLocked(event.URL, um.logContext)
+    if um.digest != nil {
+        um.digest.events = append(um.digest.events, event)
+        return true
+    }
+    for _, alerter := range um.alerters {
+        go um.deliver(alerter, event)
+    }
+    return true
+}
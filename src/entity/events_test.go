@@ -0,0 +1,140 @@
+package entity
+
+import (
+    "testing"
+    "time"
+)
+
+// recordingAlerter passes every event it is notified of to a channel
+type recordingAlerter chan DowntimeEvent
+
+func newRecordingAlerter() recordingAlerter {
+    return make(recordingAlerter, 16)
+}
+
+func (a recordingAlerter) Notify(event DowntimeEvent) error {
+    a <- event
+    return nil
+}
+
+// expect waits for the next event and checks its type
+func (a recordingAlerter) expect(t *testing.T, eventType string) DowntimeEvent {
+    t.Helper()
+    select {
+    case event := <-a:
+        if event.Type != eventType {
+            t.Fatalf("got a %s event for %s, want %s", event.Type, event.URL, eventType)
+        }
+        return event
+    case <-time.After(2 * time.Second):
+        t.Fatalf("no %s event was delivered", eventType)
+    }
+    return DowntimeEvent{}
+}
+
+// expectNone checks that no event arrives shortly
+func (a recordingAlerter) expectNone(t *testing.T) {
+    t.Helper()
+    select {
+    case event := <-a:
+        t.Fatalf("unexpected %s event for %s", event.Type, event.URL)
+    case <-time.After(100 * time.Millisecond):
+    }
+}
+
+func TestNotifySuppressesRepeatedDown(t *testing.T) {
+    um := NewUptimeMonitor()
+    alerter := newRecordingAlerter()
+    um.AddAlerter(alerter)
+
+    const url = "http://example.com/"
+    now := time.Now()
+    um.mu.Lock()
+    um.notifyLocked(DowntimeEvent{Type: EventDown, URL: url, Timestamp: now})
+    um.notifyLocked(DowntimeEvent{Type: EventDown, URL: url, Timestamp: now})
+    um.mu.Unlock()
+    alerter.expect(t, EventDown)
+    alerter.expectNone(t)
+
+    // Once the URL recovered, the next failure is a new incident
+    um.mu.Lock()
+    um.notifyLocked(DowntimeEvent{Type: EventUp, URL: url, Timestamp: now})
+    um.mu.Unlock()
+    alerter.expect(t, EventUp)
+    um.mu.Lock()
+    um.notifyLocked(DowntimeEvent{Type: EventDown, URL: url, Timestamp: now})
+    um.mu.Unlock()
+    alerter.expect(t, EventDown)
+}
+
+func TestNotifyUpRequiresDeliveredDown(t *testing.T) {
+    um := NewUptimeMonitor()
+    alerter := newRecordingAlerter()
+    um.AddAlerter(alerter)
+
+    const url, upstream = "http://example.com/", "http://upstream.example.com/"
+    now := time.Now()
+    um.mu.Lock()
+    um.monitors[url] = Monitor{URL: url, DependsOn: []string{upstream}}
+    um.downtimes[upstream] = []DowntimeEntry{{URL: upstream, StartTime: now}}
+    if um.notifyLocked(DowntimeEvent{Type: EventDown, URL: url, Timestamp: now}) {
+        t.Error("down alert was sent while a dependency is down")
+    }
+
+    // The dependency recovered before the URL did; its down alert was never sent
+    um.downtimes[upstream][0].EndTime = now
+    if um.notifyLocked(DowntimeEvent{Type: EventUp, URL: url, Timestamp: now}) {
+        t.Error("up alert was sent without a down alert")
+    }
+    um.mu.Unlock()
+    alerter.expectNone(t)
+}
+
+// recordingIncidents counts the incidents opened and resolved
+type recordingIncidents struct {
+    opened, resolved chan DowntimeEntry
+}
+
+func (m recordingIncidents) Open(downtime DowntimeEntry) (string, error) {
+    m.opened <- downtime
+    return "INC-1", nil
+}
+
+func (m recordingIncidents) Resolve(id string, downtime DowntimeEntry) error {
+    m.resolved <- downtime
+    return nil
+}
+
+func TestIncidentsFollowDownAlerts(t *testing.T) {
+    incidents := recordingIncidents{opened: make(chan DowntimeEntry, 4), resolved: make(chan DowntimeEntry, 4)}
+    um := NewUptimeMonitor(WithIncidentManager(incidents))
+
+    const url, upstream = "http://example.com/", "http://upstream.example.com/"
+    now := time.Now()
+    um.mu.Lock()
+    um.monitors[url] = Monitor{URL: url, DependsOn: []string{upstream}}
+    um.downtimes[upstream] = []DowntimeEntry{{URL: upstream, StartTime: now}}
+    um.mu.Unlock()
+
+    // Suppressed by the down dependency: no incident for the downtime, nothing to resolve
+    um.handleFailure(LogEntry{URL: url, Timestamp: now, StatusCode: 503})
+    um.mu.Lock()
+    um.downtimes[upstream][0].EndTime = now
+    um.mu.Unlock()
+    um.handleSuccess(LogEntry{URL: url, Timestamp: now.Add(time.Minute), Success: true})
+    select {
+    case downtime := <-incidents.opened:
+        t.Fatalf("incident opened for a suppressed downtime starting %v", downtime.StartTime)
+    case downtime := <-incidents.resolved:
+        t.Fatalf("incident resolved for a suppressed downtime starting %v", downtime.StartTime)
+    case <-time.After(100 * time.Millisecond):
+    }
+
+    // With the dependency up, the next downtime is alerted and gets its incident
+    um.handleFailure(LogEntry{URL: url, Timestamp: now.Add(2 * time.Minute), StatusCode: 503})
+    select {
+    case <-incidents.opened:
+    case <-time.After(2 * time.Second):
+        t.Fatal("no incident opened for an alerted downtime")
+    }
+}
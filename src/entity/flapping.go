@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// Flap detection defaults: five state changes within half an hour flag a monitor as flapping
+const (
+    defaultFlapWindow    = 30 * time.Minute
+    defaultFlapThreshold = 5
+)
+
+// WithFlapDetection flags a monitor as flapping once its state changed at least threshold times
+// within the last window. A zero threshold never flags a monitor; transitions are still counted.
+func WithFlapDetection(window time.Duration, threshold int) Option {
+    return func(um *UptimeMonitor) {
+        um.flapWindow = window
+        um.flapLimit = threshold
+    }
+}
+
+// flapTransitionsLocked counts up/down changes of the URL since the start of the flap window,
+// as GetTransitions reports them. A change into the window counts, the first check ever
+// recorded does not. Callers must hold um.mu.
+func (um *UptimeMonitor) flapTransitionsLocked(url string, now time.Time) int {
+    state, ok := um.states[url]
+    if !ok {
+        return 0
+    }
+    since := now.Add(-um.flapWindow)
+    transitions := 0
+    for _, transition := range state.transitions {
+        if !transition.Timestamp.Before(since) {
+            transitions++
+        }
+    }
+    return transitions
+}
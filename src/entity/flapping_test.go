@@ -0,0 +1,106 @@
+package entity
+
+import (
+    "testing"
+    "time"
+)
+
+func TestFlapDetection(t *testing.T) {
+    const url = "https://example.com/"
+    now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+    ago := func(minutes int) time.Time { return now.Add(-time.Duration(minutes) * time.Minute) }
+
+    tests := []struct {
+        name            string
+        logs            []LogEntry
+        wantTransitions int
+        wantFlapping    bool
+    }{
+        {name: "no checks"},
+        {
+            name:            "first check is not a transition",
+            logs:            []LogEntry{{Timestamp: ago(5)}},
+            wantTransitions: 0,
+        },
+        {
+            name: "changes before the window are ignored",
+            logs: []LogEntry{
+                {Timestamp: ago(50), Success: true}, {Timestamp: ago(45)}, {Timestamp: ago(40), Success: true},
+                {Timestamp: ago(10), Success: true},
+            },
+            wantTransitions: 0,
+        },
+        {
+            name: "a change into the window counts",
+            logs: []LogEntry{
+                {Timestamp: ago(40), Success: true}, {Timestamp: ago(20)}, {Timestamp: ago(10)},
+            },
+            wantTransitions: 1,
+        },
+        {
+            name: "retried attempts are skipped",
+            logs: []LogEntry{
+                {Timestamp: ago(25), Success: true}, {Timestamp: ago(20), Retried: true}, {Timestamp: ago(19), Success: true},
+                {Timestamp: ago(15), Retried: true}, {Timestamp: ago(14), Success: true},
+            },
+            wantTransitions: 0,
+        },
+        {
+            name: "threshold reached",
+            logs: []LogEntry{
+                {Timestamp: ago(40)}, {Timestamp: ago(25), Success: true}, {Timestamp: ago(20)},
+                {Timestamp: ago(15), Success: true}, {Timestamp: ago(10)},
+            },
+            wantTransitions: 4,
+            wantFlapping:    false,
+        },
+        {
+            name: "threshold exceeded",
+            logs: []LogEntry{
+                {Timestamp: ago(25), Success: true}, {Timestamp: ago(20)}, {Timestamp: ago(15), Success: true},
+                {Timestamp: ago(10)}, {Timestamp: ago(5), Success: true}, {Timestamp: ago(1)},
+            },
+            wantTransitions: 5,
+            wantFlapping:    true,
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            um := NewUptimeMonitor(WithClock(func() time.Time { return now }), WithFlapDetection(30*time.Minute, 5))
+            um.mu.Lock()
+            um.monitors[url] = Monitor{URL: url}
+            for _, log := range tt.logs {
+                log.URL = url
+                if log.Retried {
+                    um.retainLogLocked(log)
+                } else {
+                    um.appendLogLocked(log)
+                }
+            }
+            um.mu.Unlock()
+
+            summary := um.GetSummary()[0]
+            if summary.Transitions != tt.wantTransitions || summary.Flapping != tt.wantFlapping {
+                t.Errorf("transitions = %d, flapping = %v, want %d and %v",
+                    summary.Transitions, summary.Flapping, tt.wantTransitions, tt.wantFlapping)
+            }
+        })
+    }
+}
+
+func TestFlapDetectionDisabled(t *testing.T) {
+    const url = "https://example.com/"
+    now := time.Now()
+    um := NewUptimeMonitor(WithClock(func() time.Time { return now }), WithFlapDetection(time.Hour, 0))
+    um.mu.Lock()
+    um.monitors[url] = Monitor{URL: url}
+    for i := 0; i < 10; i++ {
+        um.appendLogLocked(LogEntry{URL: url, Timestamp: now.Add(-time.Duration(10-i) * time.Minute), Success: i%2 == 0})
+    }
+    um.mu.Unlock()
+
+    summary := um.GetSummary()[0]
+    if summary.Transitions != 9 || summary.Flapping {
+        t.Errorf("transitions = %d, flapping = %v, want 9 counted without flagging", summary.Transitions, summary.Flapping)
+    }
+}
@@ -0,0 +1,108 @@
+package entity
+
+import (
+    "context"
+    "crypto/tls"
+    "fmt"
+    "net"
+    "net/textproto"
+    "net/url"
+    "time"
+)
+
+// checkFTP connects to an FTP or FTPS (implicit TLS) server, optionally logs in and
+// verifies the control-channel handshake. ResponseTime covers the whole handshake
+// and TTFB the arrival of the server greeting.
+func (um *UptimeMonitor) checkFTP(ctx context.Context, monitor Monitor) LogEntry {
+    start := time.Now()
+    entry := LogEntry{URL: monitor.URL}
+
+    code, greeting, err := um.ftpHandshake(ctx, monitor, start)
+    entry.Timestamp = um.now()
+    entry.ResponseTime = time.Since(start).Milliseconds()
+    entry.TTFB = greeting.Milliseconds()
+    entry.StatusCode = code
+
+    if err != nil {
+        entry.Error = err.Error()
+        entry.ErrorClass = classifyError(err)
+        return entry
+    }
+    entry.Success = true
+    return entry
+}
+
+// ftpHandshake returns the last FTP reply code and the time until the greeting arrived
+func (um *UptimeMonitor) ftpHandshake(ctx context.Context, monitor Monitor, start time.Time) (int, time.Duration, error) {
+    target, err := url.Parse(monitor.URL)
+    if err != nil {
+        return 0, 0, err
+    }
+
+    timeout := monitor.timeout()
+    if monitor.CheckBudget > 0 {
+        timeout = monitor.CheckBudget
+    }
+    ctx, cancel := context.WithTimeout(ctx, timeout)
+    defer cancel()
+
+    host := target.Host
+    var conn net.Conn
+    switch target.Scheme {
+    case "ftp":
+        if target.Port() == "" {
+            host = net.JoinHostPort(target.Hostname(), "21")
+        }
+        conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", host)
+    case "ftps":
+        if target.Port() == "" {
+            host = net.JoinHostPort(target.Hostname(), "990")
+        }
+        dialer := &tls.Dialer{Config: checkTLSConfig(monitor, target.Hostname())}
+        conn, err = dialer.DialContext(ctx, "tcp", host)
+    default:
+        return 0, 0, fmt.Errorf("FTP monitor requires an ftp:// or ftps:// URL")
+    }
+    if err != nil {
+        return 0, 0, err
+    }
+    defer conn.Close()
+    deadline, _ := ctx.Deadline()
+    conn.SetDeadline(deadline)
+    // Cancelling the check, e.g. when its budget runs out, interrupts the exchange
+    stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+    defer stop()
+
+    text := textproto.NewConn(conn)
+    code, _, err := text.ReadResponse(2)
+    greeting := time.Since(start)
+    if err != nil {
+        return code, greeting, fmt.Errorf("unexpected FTP greeting: %w", err)
+    }
+
+    if monitor.FTPUser != "" {
+        if code, err = ftpCommand(text, 0, "USER %s", monitor.FTPUser); err != nil {
+            return code, greeting, err
+        }
+        if code == 331 {
+            if code, err = ftpCommand(text, 2, "PASS %s", monitor.FTPPassword); err != nil {
+                return code, greeting, fmt.Errorf("FTP login failed: %w", err)
+            }
+        } else if code/100 != 2 {
+            return code, greeting, fmt.Errorf("FTP login failed with reply %d", code)
+        }
+    }
+
+    ftpCommand(text, 0, "QUIT")
+    return code, greeting, nil
+}
+
+// ftpCommand sends a command and reads its reply, which must be in the expectClass
+// (e.g. 2 for 2xx) unless expectClass is zero
+func ftpCommand(text *textproto.Conn, expectClass int, format string, args ...interface{}) (int, error) {
+    if err := text.PrintfLine(format, args...); err != nil {
+        return 0, err
+    }
+    code, _, err := text.ReadResponse(expectClass)
+    return code, err
+}
@@ -0,0 +1,109 @@
+package entity
+
+import (
+    "context"
+    "net"
+    "net/textproto"
+    "strings"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+func TestFTPCheckStopsWhenCancelled(t *testing.T) {
+    // The server accepts connections but never sends a greeting
+    listener, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer listener.Close()
+    go func() {
+        for {
+            conn, err := listener.Accept()
+            if err != nil {
+                return
+            }
+            defer conn.Close()
+        }
+    }()
+
+    ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+    defer cancel()
+    um := NewUptimeMonitor()
+    start := time.Now()
+    entry := um.checkFTP(ctx, Monitor{URL: "ftp://" + listener.Addr().String(), Type: MonitorTypeFTP, Timeout: time.Minute})
+    if entry.Success {
+        t.Fatal("check succeeded without a greeting")
+    }
+    if elapsed := time.Since(start); elapsed > 5*time.Second {
+        t.Errorf("check took %v after its context was cancelled", elapsed)
+    }
+}
+
+// ftpLoginServer accepts any user and answers each login's PASS command with the next of
+// replies, repeating the last one. It returns the server's URL and the number of logins.
+func ftpLoginServer(t *testing.T, replies ...string) (string, *int32) {
+    listener, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { listener.Close() })
+
+    var logins int32
+    go func() {
+        for {
+            conn, err := listener.Accept()
+            if err != nil {
+                return
+            }
+            go func() {
+                defer conn.Close()
+                text := textproto.NewConn(conn)
+                text.PrintfLine("220 ready")
+                for {
+                    line, err := text.ReadLine()
+                    if err != nil {
+                        return
+                    }
+                    switch {
+                    case strings.HasPrefix(line, "USER "):
+                        text.PrintfLine("331 password required")
+                    case strings.HasPrefix(line, "PASS "):
+                        n := int(atomic.AddInt32(&logins, 1))
+                        text.PrintfLine(replies[min(n, len(replies))-1])
+                    default:
+                        text.PrintfLine("221 bye")
+                        return
+                    }
+                }
+            }()
+        }
+    }()
+    return "ftp://" + listener.Addr().String(), &logins
+}
+
+func TestFTPCheckRetriesTransientReplies(t *testing.T) {
+    url, logins := ftpLoginServer(t, "421 too many connections", "230 logged in")
+    um := NewUptimeMonitor(WithRetryJitter(0))
+
+    entry := um.checkURL(Monitor{URL: url, Type: MonitorTypeFTP, FTPUser: "user", FTPPassword: "pass", Retries: 2})
+    if !entry.Success || entry.Attempt != 2 {
+        t.Fatalf("entry = %+v, want the login to succeed on the second attempt", entry)
+    }
+    if n := atomic.LoadInt32(logins); n != 2 {
+        t.Errorf("server saw %d logins, want 2", n)
+    }
+}
+
+func TestFTPCheckDoesNotRetryPermanentReplies(t *testing.T) {
+    url, logins := ftpLoginServer(t, "530 login incorrect")
+    um := NewUptimeMonitor(WithRetryJitter(0))
+
+    entry := um.checkURL(Monitor{URL: url, Type: MonitorTypeFTP, FTPUser: "user", FTPPassword: "wrong", Retries: 2})
+    if entry.Success || entry.StatusCode != 530 {
+        t.Fatalf("entry = %+v, want a failed login with reply 530", entry)
+    }
+    if n := atomic.LoadInt32(logins); n != 1 {
+        t.Errorf("server saw %d logins, want a 530 reply not to be retried", n)
+    }
+}
@@ -0,0 +1,92 @@
+package entity
+
+import (
+    "fmt"
+    "math"
+    "slices"
+    "strconv"
+    "strings"
+)
+
+// defaultLatencyBuckets are the histogram upper bounds in milliseconds
+var defaultLatencyBuckets = []float64{50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// ParseLatencyBuckets parses comma-separated histogram bounds in milliseconds, e.g. "100,500,1000",
+// and returns them validated and sorted
+func ParseLatencyBuckets(text string) ([]float64, error) {
+    var bounds []float64
+    for _, field := range strings.Split(text, ",") {
+        bound, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+        if err != nil {
+            return nil, fmt.Errorf("invalid latency bucket %q", field)
+        }
+        bounds = append(bounds, bound)
+    }
+    return sortedLatencyBuckets(bounds)
+}
+
+// sortedLatencyBuckets returns a sorted copy of the bounds, which must be finite, positive and distinct
+func sortedLatencyBuckets(bounds []float64) ([]float64, error) {
+    if len(bounds) == 0 {
+        return nil, fmt.Errorf("at least one latency bucket is required")
+    }
+    sorted := slices.Clone(bounds)
+    slices.Sort(sorted)
+    for i, bound := range sorted {
+        if math.IsNaN(bound) || math.IsInf(bound, 0) || bound <= 0 {
+            return nil, fmt.Errorf("latency bucket %v must be a positive number of milliseconds", bound)
+        }
+        if i > 0 && bound == sorted[i-1] {
+            return nil, fmt.Errorf("duplicate latency bucket %v", bound)
+        }
+    }
+    return sorted, nil
+}
+
+// latencyHistogram accumulates response times into fixed buckets
+type latencyHistogram struct {
+    counts []uint64 // per bucket, non-cumulative; the last slot counts values above every bound
+    sum    int64
+    count  uint64
+}
+
+func newLatencyHistogram(bounds []float64) *latencyHistogram {
+    return &latencyHistogram{counts: make([]uint64, len(bounds)+1)}
+}
+
+func (h *latencyHistogram) observe(bounds []float64, ms int64) {
+    i := 0
+    for i < len(bounds) && float64(ms) > bounds[i] {
+        i++
+    }
+    h.counts[i]++
+    h.sum += ms
+    h.count++
+}
+
+// HistogramBucket is a cumulative bucket: Count observations were at most UpperBound
+type HistogramBucket struct {
+    UpperBound float64 `json:"le"`
+    Count      uint64  `json:"count"`
+}
+
+// LatencyHistogram is the exported form of a URL's response time histogram
+type LatencyHistogram struct {
+    Buckets []HistogramBucket `json:"buckets"` // excludes the implicit +Inf bucket, which equals Count
+    Sum     int64             `json:"sum"`     // in milliseconds
+    Count   uint64            `json:"count"`
+}
+
+func (h *latencyHistogram) export(bounds []float64) LatencyHistogram {
+    out := LatencyHistogram{
+        Buckets: make([]HistogramBucket, len(bounds)),
+        Sum:     h.sum,
+        Count:   h.count,
+    }
+    var cumulative uint64
+    for i, bound := range bounds {
+        cumulative += h.counts[i]
+        out.Buckets[i] = HistogramBucket{UpperBound: bound, Count: cumulative}
+    }
+    return out
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+    "slices"
+    "testing"
+)
+
+func TestLatencyHistogram(t *testing.T) {
+    bounds := []float64{100, 500, 1000}
+    h := newLatencyHistogram(bounds)
+    for _, ms := range []int64{20, 100, 101, 499, 1000, 4000} {
+        h.observe(bounds, ms)
+    }
+
+    exported := h.export(bounds)
+    if exported.Count != 6 || exported.Sum != 5720 {
+        t.Errorf("count %d, sum %d, want 6 and 5720", exported.Count, exported.Sum)
+    }
+    // Buckets are cumulative and include their upper bound
+    var counts []uint64
+    for i, bucket := range exported.Buckets {
+        if bucket.UpperBound != bounds[i] {
+            t.Errorf("bucket %d has bound %v, want %v", i, bucket.UpperBound, bounds[i])
+        }
+        counts = append(counts, bucket.Count)
+    }
+    if !slices.Equal(counts, []uint64{2, 4, 5}) {
+        t.Errorf("cumulative counts = %v, want [2 4 5]", counts)
+    }
+}
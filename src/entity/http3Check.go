@@ -0,0 +1,108 @@
+package entity
+
+import (
+    "context"
+    "crypto/tls"
+    "fmt"
+    "io"
+    "net/http"
+    "time"
+
+    "github.com/quic-go/quic-go"
+    "github.com/quic-go/quic-go/http3"
+)
+
+// checkHTTP3 fetches the URL over HTTP/3. Every check dials a fresh QUIC connection so
+// HandshakeTime reflects the current connect and TLS handshake cost; endpoints that do
+// not answer over QUIC fail instead of falling back to HTTP/1.1 or HTTP/2.
+func (um *UptimeMonitor) checkHTTP3(ctx context.Context, monitor Monitor) LogEntry {
+    start := time.Now()
+    entry := LogEntry{URL: monitor.URL, Protocol: "HTTP/3.0"}
+
+    timeout := monitor.timeout()
+    if monitor.CheckBudget > 0 {
+        timeout = monitor.CheckBudget
+    }
+    ctx, cancel := context.WithTimeout(ctx, timeout)
+    defer cancel()
+
+    var handshake time.Duration
+    transport := &http3.Transport{
+        TLSClientConfig: checkTLSConfig(monitor, ""),
+        QUICConfig:      &quic.Config{HandshakeIdleTimeout: timeout},
+        Dial: func(ctx context.Context, addr string, tlsCfg *tls.Config, cfg *quic.Config) (quic.EarlyConnection, error) {
+            conn, err := quic.DialAddrEarly(ctx, addr, tlsCfg, cfg)
+            if err != nil {
+                return nil, err
+            }
+            select {
+            case <-conn.HandshakeComplete():
+            case <-ctx.Done():
+                conn.CloseWithError(0, "")
+                return nil, ctx.Err()
+            }
+            handshake = time.Since(start)
+            entry.RemoteAddr = conn.RemoteAddr().String()
+            return conn, nil
+        },
+    }
+    defer transport.Close()
+
+    resp, body, firstByte, err := um.fetchHTTP3(ctx, monitor, transport, start)
+    entry.Timestamp = um.now()
+    entry.ResponseTime = time.Since(start).Milliseconds()
+    entry.TTFB = firstByte.Milliseconds()
+    entry.HandshakeTime = handshake.Milliseconds()
+
+    if err != nil {
+        if handshake == 0 {
+            err = fmt.Errorf("endpoint does not support HTTP/3: %w", err)
+        }
+        entry.Error = err.Error()
+        entry.ErrorClass = classifyError(err)
+        return entry
+    }
+
+    entry.StatusCode = resp.StatusCode
+    entry.BytesSent = requestSize(resp.Request)
+    entry.BytesReceived = responseSize(resp, int64(len(body)))
+    entry.Success = monitor.statusOK(resp.StatusCode)
+    if finalURL := resp.Request.URL.String(); finalURL != monitor.URL {
+        entry.FinalURL = finalURL
+    }
+    recordTLS(&entry, resp.TLS)
+    if entry.Success {
+        if err := verifyResponse(monitor, resp, body, &entry); err != nil {
+            entry.Success = false
+            entry.Error = err.Error()
+        }
+    }
+    return entry
+}
+
+// fetchHTTP3 sends the monitor's request through the QUIC transport and reads the capped body.
+// It also returns when, relative to start, the response headers arrived.
+func (um *UptimeMonitor) fetchHTTP3(ctx context.Context, monitor Monitor, transport http.RoundTripper, start time.Time) (*http.Response, []byte, time.Duration, error) {
+    req, err := um.newCheckRequest(ctx, monitor)
+    if err != nil {
+        return nil, nil, 0, err
+    }
+
+    // Keep the monitor's redirect policy while swapping in the QUIC transport
+    client := *um.clientFor(monitor)
+    client.Transport = transport
+    client.Timeout = 0 // bounded by ctx
+
+    resp, err := client.Do(req)
+    if err != nil {
+        return nil, nil, 0, err
+    }
+    defer resp.Body.Close()
+    firstByte := time.Since(start)
+
+    body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+    if err != nil {
+        return nil, nil, firstByte, fmt.Errorf("reading response body: %w", err)
+    }
+    return resp, body, firstByte, nil
+}
@@ -0,0 +1,68 @@
+package entity
+
+import (
+    "context"
+    "crypto/tls"
+    "net"
+    "net/http"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/quic-go/quic-go/http3"
+)
+
+// startHTTP3Server serves handler over HTTP/3 with a self-signed certificate and returns its URL
+func startHTTP3Server(t *testing.T, handler http.Handler) string {
+    t.Helper()
+    conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    server := &http3.Server{
+        Handler:   handler,
+        TLSConfig: http3.ConfigureTLSConfig(&tls.Config{Certificates: []tls.Certificate{selfSignedCert(t)}}),
+    }
+    go server.Serve(conn)
+    t.Cleanup(func() { server.Close() })
+    return "https://" + conn.LocalAddr().String() + "/"
+}
+
+func TestCheckHTTP3(t *testing.T) {
+    url := startHTTP3Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.ProtoMajor != 3 {
+            http.Error(w, "not HTTP/3", http.StatusBadRequest)
+            return
+        }
+        w.Write([]byte(`{"status": "ok"}`))
+    }))
+    um := NewUptimeMonitor()
+
+    entry := um.attemptCheck(context.Background(), Monitor{URL: url, Type: MonitorTypeHTTP3, InsecureSkipVerify: true, ExpectedBody: `"ok"`})
+    if !entry.Success || entry.StatusCode != http.StatusOK || entry.TLSVersion != "TLS 1.3" || entry.RemoteAddr == "" {
+        t.Fatalf("HTTP/3 check = %+v, want success over TLS 1.3", entry)
+    }
+    if entry.HandshakeTime > entry.ResponseTime || entry.BytesReceived == 0 {
+        t.Errorf("handshake %dms of %dms, %d bytes received", entry.HandshakeTime, entry.ResponseTime, entry.BytesReceived)
+    }
+
+    entry = um.attemptCheck(context.Background(), Monitor{URL: url, Type: MonitorTypeHTTP3, InsecureSkipVerify: true, ExpectedBody: "degraded"})
+    if entry.Success || !strings.Contains(entry.Error, "degraded") {
+        t.Errorf("check with a missing body substring = %+v, want a failure", entry)
+    }
+}
+
+func TestCheckHTTP3Unsupported(t *testing.T) {
+    // Nothing answers QUIC on this port, as if the endpoint only spoke TCP
+    conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer conn.Close()
+
+    monitor := Monitor{URL: "https://" + conn.LocalAddr().String() + "/", Type: MonitorTypeHTTP3, InsecureSkipVerify: true, Timeout: 200 * time.Millisecond}
+    entry := NewUptimeMonitor().attemptCheck(context.Background(), monitor)
+    if entry.Success || !strings.Contains(entry.Error, "does not support HTTP/3") || entry.HandshakeTime != 0 {
+        t.Errorf("check = %+v, want a failure reporting no HTTP/3 support", entry)
+    }
+}
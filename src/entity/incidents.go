@@ -0,0 +1,134 @@
+package entity
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "log/slog"
+    "net/http"
+    "net/url"
+    "strings"
+    "time"
+)
+
+// IncidentManager mirrors downtimes into an external incident system such as PagerDuty or Opsgenie
+type IncidentManager interface {
+    // Open creates an incident for a downtime that just started and returns its ID
+    Open(downtime DowntimeEntry) (string, error)
+    // Resolve closes the incident of a downtime that ended
+    Resolve(id string, downtime DowntimeEntry) error
+}
+
+// WithIncidentManager opens an external incident for every downtime and resolves it on recovery
+func WithIncidentManager(manager IncidentManager) Option {
+    return func(um *UptimeMonitor) {
+        um.incidents = manager
+    }
+}
+
+// HTTPIncidentAPI is a generic IncidentManager for incident APIs that accept a JSON downtime.
+// Creation must answer with a JSON object carrying the incident "id".
+type HTTPIncidentAPI struct {
+    CreateURL  string
+    ResolveURL string            // "{id}" is replaced with the incident ID
+    Headers    map[string]string // e.g. Authorization
+    Client     *http.Client
+}
+
+func (api *HTTPIncidentAPI) Open(downtime DowntimeEntry) (string, error) {
+    resp, err := api.post(api.CreateURL, downtime)
+    if err != nil {
+        return "", err
+    }
+    defer resp.Body.Close()
+
+    var created struct {
+        ID string `json:"id"`
+    }
+    if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+        return "", fmt.Errorf("decoding incident: %w", err)
+    }
+    if created.ID == "" {
+        return "", fmt.Errorf("incident API returned no id")
+    }
+    return created.ID, nil
+}
+
+func (api *HTTPIncidentAPI) Resolve(id string, downtime DowntimeEntry) error {
+    resp, err := api.post(strings.ReplaceAll(api.ResolveURL, "{id}", url.PathEscape(id)), downtime)
+    if err != nil {
+        return err
+    }
+    resp.Body.Close()
+    return nil
+}
+
+func (api *HTTPIncidentAPI) post(target string, downtime DowntimeEntry) (*http.Response, error) {
+    payload, err := json.Marshal(downtime)
+    if err != nil {
+        return nil, err
+    }
+    req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(payload))
+    if err != nil {
+        return nil, err
+    }
+    req.Header.Set("Content-Type", "application/json")
+    for name, value := range api.Headers {
+        req.Header.Set(name, value)
+    }
+
+    client := api.Client
+    if client == nil {
+        client = &http.Client{Timeout: 10 * time.Second}
+    }
+    resp, err := client.Do(req)
+    if err != nil {
+        return nil, err
+    }
+    if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+        resp.Body.Close()
+        return nil, fmt.Errorf("incident API %s answered %s", target, resp.Status)
+    }
+    return resp, nil
+}
+
+// findDowntimeLocked returns the downtime of url that started at start; callers must hold um.mu
+func (um *UptimeMonitor) findDowntimeLocked(url string, start time.Time) *DowntimeEntry {
+    downtimes := um.downtimes[url]
+    for i := len(downtimes) - 1; i >= 0; i-- {
+        if downtimes[i].StartTime.Equal(start) {
+            return &downtimes[i]
+        }
+    }
+    return nil
+}
+
+// openIncident creates the external incident and stores its ID on the downtime. If the
+// downtime already ended while the incident was being created, it is resolved right away.
+func (um *UptimeMonitor) openIncident(downtime DowntimeEntry) {
+    id, err := um.incidents.Open(downtime)
+    if err != nil {
+        slog.Error("Failed to open incident", "url", downtime.URL, "error", err)
+        return
+    }
+
+    um.mu.Lock()
+    stored := um.findDowntimeLocked(downtime.URL, downtime.StartTime)
+    if stored == nil {
+        um.mu.Unlock()
+        return
+    }
+    stored.IncidentID = id
+    current := *stored
+    um.mu.Unlock()
+
+    if !current.EndTime.IsZero() {
+        um.resolveIncident(current)
+    }
+}
+
+func (um *UptimeMonitor) resolveIncident(downtime DowntimeEntry) {
+    if err := um.incidents.Resolve(downtime.IncidentID, downtime); err != nil {
+        slog.Error("Failed to resolve incident", "url", downtime.URL, "incidentId", downtime.IncidentID, "error", err)
+    }
+}
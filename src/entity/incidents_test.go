@@ -0,0 +1,94 @@
+package entity
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+)
+
+func TestHTTPIncidentAPI(t *testing.T) {
+    requests := make(chan *http.Request, 2)
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        var downtime DowntimeEntry
+        if err := json.NewDecoder(r.Body).Decode(&downtime); err != nil || downtime.URL != "https://example.com/" {
+            http.Error(w, "bad downtime", http.StatusBadRequest)
+            return
+        }
+        requests <- r
+        if r.URL.Path == "/incidents" {
+            w.Write([]byte(`{"id": "INC 42"}`))
+        }
+    }))
+    defer server.Close()
+
+    api := &HTTPIncidentAPI{
+        CreateURL:  server.URL + "/incidents",
+        ResolveURL: server.URL + "/incidents/{id}/resolve",
+        Headers:    map[string]string{"Authorization": "Token secret"},
+    }
+    downtime := DowntimeEntry{URL: "https://example.com/", StartTime: time.Now()}
+    id, err := api.Open(downtime)
+    if err != nil || id != "INC 42" {
+        t.Fatalf("Open = %q, %v", id, err)
+    }
+    if r := <-requests; r.Header.Get("Authorization") != "Token secret" || r.Header.Get("Content-Type") != "application/json" {
+        t.Errorf("create request headers = %v", r.Header)
+    }
+
+    if err := api.Resolve(id, downtime); err != nil {
+        t.Fatal(err)
+    }
+    if r := <-requests; r.URL.EscapedPath() != "/incidents/INC%2042/resolve" {
+        t.Errorf("resolved %s, want the escaped incident ID in the path", r.URL.EscapedPath())
+    }
+
+    if _, err := (&HTTPIncidentAPI{CreateURL: server.URL + "/unknown"}).Open(downtime); err == nil {
+        t.Error("expected an error for a response without an id")
+    }
+    if err := api.Resolve(id, DowntimeEntry{URL: "https://other.example.com/"}); err == nil {
+        t.Error("expected an error for a rejected request")
+    }
+}
+
+// gatedIncidents opens incidents only once release is closed
+type gatedIncidents struct {
+    release  chan struct{}
+    resolved chan string
+}
+
+func (m gatedIncidents) Open(downtime DowntimeEntry) (string, error) {
+    <-m.release
+    return "INC-7", nil
+}
+
+func (m gatedIncidents) Resolve(id string, downtime DowntimeEntry) error {
+    m.resolved <- id
+    return nil
+}
+
+func TestIncidentOpenedAfterRecovery(t *testing.T) {
+    incidents := gatedIncidents{release: make(chan struct{}), resolved: make(chan string, 1)}
+    um := NewUptimeMonitor(WithIncidentManager(incidents))
+
+    const url = "https://example.com/"
+    um.monitors[url] = Monitor{URL: url}
+    now := time.Now()
+    um.recordCheck(LogEntry{URL: url, Success: false, StatusCode: 503, Timestamp: now})
+    um.recordCheck(LogEntry{URL: url, Success: true, StatusCode: 200, Timestamp: now.Add(time.Second)})
+
+    // The incident API answers only after the URL recovered, so the incident is resolved at once
+    close(incidents.release)
+    select {
+    case id := <-incidents.resolved:
+        if id != "INC-7" {
+            t.Errorf("resolved incident %q", id)
+        }
+    case <-time.After(2 * time.Second):
+        t.Fatal("the late incident was not resolved")
+    }
+    if downtimes := um.GetDowntimes(url); downtimes[0].IncidentID != "INC-7" {
+        t.Errorf("downtime incident = %q, want INC-7", downtimes[0].IncidentID)
+    }
+}
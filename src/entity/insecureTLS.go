@@ -0,0 +1,41 @@
+package entity
+
+import (
+    "crypto/tls"
+    "net/http"
+)
+
+// insecureWarning is sent with the response when a monitor skipping certificate verification is added
+const insecureWarning = `299 - "TLS certificate verification is disabled for this monitor"`
+
+// insecureTransportFor returns the transport shared by monitors with InsecureSkipVerify. It is
+// built once from the client's transport, so these monitors still pool their connections.
+func (um *UptimeMonitor) insecureTransportFor() *http.Transport {
+    um.insecureOnce.Do(func() {
+        base, ok := um.client.Transport.(*http.Transport)
+        if !ok || base == nil {
+            base = http.DefaultTransport.(*http.Transport)
+        }
+        config := &tls.Config{}
+        if base.TLSClientConfig != nil {
+            config = base.TLSClientConfig.Clone()
+        }
+        config.InsecureSkipVerify = true
+        um.insecure = base.Clone()
+        um.insecure.TLSClientConfig = config
+    })
+    return um.insecure
+}
+
+// checkTLSConfig returns the TLS configuration of a connection dialed for one check, as
+// HTTP/3 and FTPS checks do, honoring InsecureSkipVerify and the monitor's TLS profile
+func checkTLSConfig(monitor Monitor, serverName string) *tls.Config {
+    config := &tls.Config{ServerName: serverName, InsecureSkipVerify: monitor.InsecureSkipVerify}
+    if monitor.TLSProfile != nil {
+        // The profile was validated when the monitor was added
+        if profiled, err := monitor.TLSProfile.config(config); err == nil {
+            config = profiled
+        }
+    }
+    return config
+}
@@ -0,0 +1,92 @@
+package entity
+
+import (
+    "context"
+    "crypto/tls"
+    "net"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/quic-go/quic-go/http3"
+)
+
+// selfSignedCert returns the certificate httptest serves, which no system pool trusts
+func selfSignedCert(t *testing.T) tls.Certificate {
+    server := httptest.NewTLSServer(http.NotFoundHandler())
+    defer server.Close()
+    return server.TLS.Certificates[0]
+}
+
+func TestInsecureSkipVerifyHTTPS(t *testing.T) {
+    server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+    defer server.Close()
+    um := NewUptimeMonitor()
+
+    if entry := um.attemptCheck(context.Background(), Monitor{URL: server.URL + "/"}); entry.Success || entry.ErrorClass != ErrorClassTLS {
+        t.Errorf("verified check = %+v, want a TLS failure", entry)
+    }
+    if entry := um.attemptCheck(context.Background(), Monitor{URL: server.URL + "/", InsecureSkipVerify: true}); !entry.Success {
+        t.Errorf("unverified check failed: %s", entry.Error)
+    }
+}
+
+func TestInsecureSkipVerifyHTTP3(t *testing.T) {
+    conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    server := &http3.Server{
+        Handler:   http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+        TLSConfig: http3.ConfigureTLSConfig(&tls.Config{Certificates: []tls.Certificate{selfSignedCert(t)}}),
+    }
+    go server.Serve(conn)
+    defer server.Close()
+
+    um := NewUptimeMonitor()
+    url := "https://" + conn.LocalAddr().String() + "/"
+    if entry := um.checkHTTP3(context.Background(), Monitor{URL: url, Type: MonitorTypeHTTP3}); entry.Success {
+        t.Errorf("verified HTTP/3 check of a self-signed endpoint succeeded")
+    }
+    entry := um.checkHTTP3(context.Background(), Monitor{URL: url, Type: MonitorTypeHTTP3, InsecureSkipVerify: true})
+    if !entry.Success || entry.Protocol != "HTTP/3.0" {
+        t.Errorf("unverified HTTP/3 check = %+v, want success", entry)
+    }
+}
+
+func TestInsecureSkipVerifyFTPS(t *testing.T) {
+    listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{selfSignedCert(t)}})
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer listener.Close()
+    go func() {
+        for {
+            conn, err := listener.Accept()
+            if err != nil {
+                return
+            }
+            conn.Write([]byte("220 ready\r\n"))
+            buf := make([]byte, 64)
+            conn.Read(buf) // QUIT
+            conn.Close()
+        }
+    }()
+
+    um := NewUptimeMonitor()
+    url := "ftps://" + listener.Addr().String()
+    if entry := um.checkFTP(context.Background(), Monitor{URL: url, Type: MonitorTypeFTP}); entry.Success {
+        t.Errorf("verified FTPS check of a self-signed endpoint succeeded")
+    }
+    if entry := um.checkFTP(context.Background(), Monitor{URL: url, Type: MonitorTypeFTP, InsecureSkipVerify: true}); !entry.Success {
+        t.Errorf("unverified FTPS check failed: %s", entry.Error)
+    }
+}
+
+func TestCheckTLSConfigAppliesProfile(t *testing.T) {
+    monitor := Monitor{InsecureSkipVerify: true, TLSProfile: &TLSProfile{ServerName: "override.example.com", MinVersion: "1.3"}}
+    config := checkTLSConfig(monitor, "example.com")
+    if !config.InsecureSkipVerify || config.ServerName != "override.example.com" || config.MinVersion != tls.VersionTLS13 {
+        t.Errorf("config = %+v, want the flag and the profile applied", config)
+    }
+}
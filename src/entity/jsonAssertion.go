@@ -0,0 +1,138 @@
+package entity
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+// Operators are matched longest first so "<=" is not read as "<"
+var comparisonOperators = []string{"<=", ">=", "==", "!=", "<", ">"}
+
+// jsonAssertion is a numeric comparison on a JSONPath value, e.g. "$.queueDepth < 1000"
+type jsonAssertion struct {
+    expr  string
+    path  []jsonPathStep
+    op    string
+    value float64
+}
+
+func parseJSONAssertion(expr string) (jsonAssertion, error) {
+    for _, op := range comparisonOperators {
+        idx := strings.Index(expr, op)
+        if idx < 0 {
+            continue
+        }
+        path, err := parseJSONPath(expr[:idx])
+        if err != nil {
+            return jsonAssertion{}, err
+        }
+        value, err := strconv.ParseFloat(strings.TrimSpace(expr[idx+len(op):]), 64)
+        if err != nil {
+            return jsonAssertion{}, fmt.Errorf("assertion %q must compare against a number", expr)
+        }
+        return jsonAssertion{expr: expr, path: path, op: op, value: value}, nil
+    }
+    return jsonAssertion{}, fmt.Errorf("assertion %q has no comparison operator (one of %s)", expr, strings.Join(comparisonOperators, " "))
+}
+
+func compareNumbers(actual float64, op string, expected float64) bool {
+    switch op {
+    case "<":
+        return actual < expected
+    case "<=":
+        return actual <= expected
+    case ">":
+        return actual > expected
+    case ">=":
+        return actual >= expected
+    case "==":
+        return actual == expected
+    case "!=":
+        return actual != expected
+    }
+    return false
+}
+
+// checkJSONAssertions returns an error describing the first assertion the body violates
+func checkJSONAssertions(exprs []string, body []byte) error {
+    doc, err := decodeJSONBody(body)
+    if err != nil {
+        return err
+    }
+
+    for _, expr := range exprs {
+        assertion, err := parseJSONAssertion(expr)
+        if err != nil {
+            return err
+        }
+        value, err := lookupJSONPath(doc, assertion.path)
+        if err != nil {
+            return fmt.Errorf("assertion %q: %v", expr, err)
+        }
+        number, ok := value.(float64)
+        if !ok {
+            return fmt.Errorf("assertion %q: value %v is not a number", expr, value)
+        }
+        if !compareNumbers(number, assertion.op, assertion.value) {
+            return fmt.Errorf("assertion %q failed: actual value %v", expr, number)
+        }
+    }
+    return nil
+}
+
+// ArrayLengthAssertion bounds the length of a JSONPath-selected array, e.g. a list of active nodes.
+// Unset bounds are not checked.
+type ArrayLengthAssertion struct {
+    Path  string `json:"path"`
+    Min   *int   `json:"min,omitempty"`
+    Max   *int   `json:"max,omitempty"`
+    Exact *int   `json:"exact,omitempty"`
+}
+
+func (a ArrayLengthAssertion) validate() error {
+    if _, err := parseJSONPath(a.Path); err != nil {
+        return err
+    }
+    if a.Min == nil && a.Max == nil && a.Exact == nil {
+        return fmt.Errorf("array length assertion on %s needs min, max or exact", a.Path)
+    }
+    if a.Min != nil && a.Max != nil && *a.Min > *a.Max {
+        return fmt.Errorf("array length assertion on %s has min greater than max", a.Path)
+    }
+    return nil
+}
+
+// checkArrayLengths returns an error describing the first array whose length is out of bounds
+func checkArrayLengths(assertions []ArrayLengthAssertion, body []byte) error {
+    doc, err := decodeJSONBody(body)
+    if err != nil {
+        return err
+    }
+
+    for _, assertion := range assertions {
+        path, err := parseJSONPath(assertion.Path)
+        if err != nil {
+            return err
+        }
+        value, err := lookupJSONPath(doc, path)
+        if err != nil {
+            return fmt.Errorf("array length of %s: %v", assertion.Path, err)
+        }
+        array, ok := value.([]interface{})
+        if !ok {
+            return fmt.Errorf("array length of %s: value %v is not an array", assertion.Path, value)
+        }
+
+        length := len(array)
+        switch {
+        case assertion.Exact != nil && length != *assertion.Exact:
+            return fmt.Errorf("%s has %d elements, expected exactly %d", assertion.Path, length, *assertion.Exact)
+        case assertion.Min != nil && length < *assertion.Min:
+            return fmt.Errorf("%s has %d elements, expected at least %d", assertion.Path, length, *assertion.Min)
+        case assertion.Max != nil && length > *assertion.Max:
+            return fmt.Errorf("%s has %d elements, expected at most %d", assertion.Path, length, *assertion.Max)
+        }
+    }
+    return nil
+}
@@ -0,0 +1,77 @@
+package entity
+
+import "testing"
+
+func TestCheckJSONAssertions(t *testing.T) {
+    body := []byte(`{"queue": {"depth": 250, "workers": [1, 2]}, "status": "ok", "ratio": 0.5}`)
+    tests := []struct {
+        exprs   []string
+        wantErr bool
+    }{
+        {exprs: []string{"$.queue.depth < 1000", "$.ratio >= 0.5", "$.queue.workers[1] == 2"}},
+        {exprs: []string{"$.queue.depth <= 250", "$.queue.depth != 0"}},
+        {exprs: []string{"$.queue.depth > 250"}, wantErr: true},
+        {exprs: []string{"$.queue.depth < 1000", "$.ratio == 1"}, wantErr: true},
+        {exprs: []string{"$.status == 1"}, wantErr: true},
+        {exprs: []string{"$.missing < 1"}, wantErr: true},
+        {exprs: []string{"$.queue.depth"}, wantErr: true},
+        {exprs: []string{"$.queue.depth < many"}, wantErr: true},
+    }
+    for _, test := range tests {
+        err := checkJSONAssertions(test.exprs, body)
+        if (err != nil) != test.wantErr {
+            t.Errorf("checkJSONAssertions(%q) = %v, want error %v", test.exprs, err, test.wantErr)
+        }
+    }
+
+    if err := checkJSONAssertions([]string{"$.a < 1"}, []byte("<html>")); err == nil {
+        t.Error("expected an error for a body that is not JSON")
+    }
+}
+
+func TestParseJSONAssertionOperators(t *testing.T) {
+    for expr, want := range map[string]string{
+        "$.a<=1":   "<=",
+        "$.a >= 1": ">=",
+        "$.a < -1": "<",
+        "$.a != 1": "!=",
+    } {
+        assertion, err := parseJSONAssertion(expr)
+        if err != nil || assertion.op != want {
+            t.Errorf("parseJSONAssertion(%q) = %q, %v, want operator %q", expr, assertion.op, err, want)
+        }
+    }
+}
+
+func TestCheckArrayLengths(t *testing.T) {
+    body := []byte(`{"nodes": [{"id": 1}, {"id": 2}, {"id": 3}], "name": "cluster"}`)
+    two, three, four := 2, 3, 4
+    tests := []struct {
+        assertion ArrayLengthAssertion
+        wantErr   bool
+    }{
+        {assertion: ArrayLengthAssertion{Path: "$.nodes", Exact: &three}},
+        {assertion: ArrayLengthAssertion{Path: "$.nodes", Min: &two, Max: &four}},
+        {assertion: ArrayLengthAssertion{Path: "$.nodes", Exact: &two}, wantErr: true},
+        {assertion: ArrayLengthAssertion{Path: "$.nodes", Min: &four}, wantErr: true},
+        {assertion: ArrayLengthAssertion{Path: "$.nodes", Max: &two}, wantErr: true},
+        {assertion: ArrayLengthAssertion{Path: "$.name", Min: &two}, wantErr: true},
+        {assertion: ArrayLengthAssertion{Path: "$.missing", Min: &two}, wantErr: true},
+    }
+    for _, test := range tests {
+        err := checkArrayLengths([]ArrayLengthAssertion{test.assertion}, body)
+        if (err != nil) != test.wantErr {
+            t.Errorf("checkArrayLengths(%s) = %v, want error %v", test.assertion.Path, err, test.wantErr)
+        }
+    }
+
+    for _, assertion := range []ArrayLengthAssertion{
+        {Path: "$.nodes"},
+        {Path: "nodes", Min: &two},
+        {Path: "$.nodes", Min: &four, Max: &two},
+    } {
+        if err := assertion.validate(); err == nil {
+            t.Errorf("validate(%+v) succeeded, want an error", assertion)
+        }
+    }
+}
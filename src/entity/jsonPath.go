@@ -0,0 +1,92 @@
+package entity
+
+import (
+    "encoding/json"
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+// jsonPathStep is one segment of a JSONPath: an object key or an array index
+type jsonPathStep struct {
+    key     string
+    index   int
+    isIndex bool
+}
+
+// parseJSONPath parses the dotted subset of JSONPath, e.g. $.data.items[0].name or $['odd key']
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+    path = strings.TrimSpace(path)
+    if !strings.HasPrefix(path, "$") {
+        return nil, fmt.Errorf("JSONPath %q must start with $", path)
+    }
+
+    var steps []jsonPathStep
+    rest := path[1:]
+    for rest != "" {
+        switch {
+        case rest[0] == '.':
+            end := strings.IndexAny(rest[1:], ".[")
+            if end < 0 {
+                end = len(rest) - 1
+            }
+            key := rest[1 : end+1]
+            if key == "" {
+                return nil, fmt.Errorf("JSONPath %q has an empty key", path)
+            }
+            steps = append(steps, jsonPathStep{key: key})
+            rest = rest[end+1:]
+        case rest[0] == '[':
+            end := strings.IndexByte(rest, ']')
+            if end < 0 {
+                return nil, fmt.Errorf("JSONPath %q has an unterminated [", path)
+            }
+            inner := rest[1:end]
+            if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+                steps = append(steps, jsonPathStep{key: inner[1 : len(inner)-1]})
+            } else {
+                index, err := strconv.Atoi(inner)
+                if err != nil || index < 0 {
+                    return nil, fmt.Errorf("JSONPath %q has an invalid index [%s]", path, inner)
+                }
+                steps = append(steps, jsonPathStep{index: index, isIndex: true})
+            }
+            rest = rest[end+1:]
+        default:
+            return nil, fmt.Errorf("JSONPath %q is malformed near %q", path, rest)
+        }
+    }
+    return steps, nil
+}
+
+// lookupJSONPath resolves parsed steps against a decoded JSON document
+func lookupJSONPath(doc interface{}, steps []jsonPathStep) (interface{}, error) {
+    current := doc
+    for _, step := range steps {
+        if step.isIndex {
+            arr, ok := current.([]interface{})
+            if !ok || step.index >= len(arr) {
+                return nil, fmt.Errorf("index [%d] not found", step.index)
+            }
+            current = arr[step.index]
+            continue
+        }
+        obj, ok := current.(map[string]interface{})
+        if !ok {
+            return nil, fmt.Errorf("key %q not found", step.key)
+        }
+        if current, ok = obj[step.key]; !ok {
+            return nil, fmt.Errorf("key %q not found", step.key)
+        }
+    }
+    return current, nil
+}
+
+// decodeJSONBody decodes a response body for JSONPath evaluation
+func decodeJSONBody(body []byte) (interface{}, error) {
+    var doc interface{}
+    if err := json.Unmarshal(body, &doc); err != nil {
+        return nil, fmt.Errorf("response is not valid JSON: %w", err)
+    }
+    return doc, nil
+}
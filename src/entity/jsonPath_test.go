@@ -0,0 +1,55 @@
+package entity
+
+import "testing"
+
+func TestParseJSONPath(t *testing.T) {
+    tests := []struct {
+        path string
+        want []jsonPathStep
+    }{
+        {"$", nil},
+        {"$.data.items[0].name", []jsonPathStep{{key: "data"}, {key: "items"}, {index: 0, isIndex: true}, {key: "name"}}},
+        {"$['odd key'][\"x.y\"]", []jsonPathStep{{key: "odd key"}, {key: "x.y"}}},
+        {" $[2][10] ", []jsonPathStep{{index: 2, isIndex: true}, {index: 10, isIndex: true}}},
+    }
+    for _, test := range tests {
+        steps, err := parseJSONPath(test.path)
+        if err != nil {
+            t.Errorf("parseJSONPath(%q) = %v", test.path, err)
+            continue
+        }
+        if len(steps) != len(test.want) {
+            t.Errorf("parseJSONPath(%q) = %+v, want %+v", test.path, steps, test.want)
+            continue
+        }
+        for i := range steps {
+            if steps[i] != test.want[i] {
+                t.Errorf("parseJSONPath(%q) step %d = %+v, want %+v", test.path, i, steps[i], test.want[i])
+            }
+        }
+    }
+
+    for _, path := range []string{"data.items", "$.", "$..a", "$[0", "$[-1]", "$[x]", "$a"} {
+        if _, err := parseJSONPath(path); err == nil {
+            t.Errorf("parseJSONPath(%q) succeeded, want an error", path)
+        }
+    }
+}
+
+func TestLookupJSONPath(t *testing.T) {
+    doc, err := decodeJSONBody([]byte(`{"data": {"items": [{"name": "first"}, {"name": "second"}]}}`))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    steps, _ := parseJSONPath("$.data.items[1].name")
+    if value, err := lookupJSONPath(doc, steps); err != nil || value != "second" {
+        t.Errorf("lookup = %v, %v, want second", value, err)
+    }
+    for _, path := range []string{"$.data.items[2]", "$.data.missing", "$.data.items.name", "$.data[0]"} {
+        steps, _ := parseJSONPath(path)
+        if value, err := lookupJSONPath(doc, steps); err == nil {
+            t.Errorf("lookup of %s = %v, want an error", path, value)
+        }
+    }
+}
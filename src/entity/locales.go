@@ -0,0 +1,100 @@
+package entity
+
+import (
+    "context"
+    "fmt"
+    "strings"
+    "sync"
+)
+
+// LocaleVariant is one locale of a monitor checked with its own negotiation headers
+type LocaleVariant struct {
+    Name           string            `json:"name"`                     // e.g. "de"
+    AcceptLanguage string            `json:"acceptLanguage,omitempty"` // e.g. "de-DE,de;q=0.9"
+    Headers        map[string]string `json:"headers,omitempty"`        // other negotiation headers, e.g. Accept
+}
+
+// LocaleResult is the outcome of checking one locale variant
+type LocaleResult struct {
+    Locale          string `json:"locale"`
+    Success         bool   `json:"success"`
+    StatusCode      int    `json:"statusCode,omitempty"`
+    ResponseTime    int64  `json:"responseTime"` // in milliseconds
+    ContentLanguage string `json:"contentLanguage,omitempty"`
+    Error           string `json:"error,omitempty"`
+}
+
+func validateLocales(monitor Monitor) error {
+    if len(monitor.Locales) == 0 {
+        return nil
+    }
+    if monitor.Type != MonitorTypeHTTP {
+        return fmt.Errorf("locales are only supported by http monitors")
+    }
+    if len(monitor.Ports) > 0 {
+        return fmt.Errorf("locales cannot be combined with ports")
+    }
+    seen := make(map[string]bool)
+    for _, variant := range monitor.Locales {
+        if variant.Name == "" {
+            return fmt.Errorf("every locale variant needs a name")
+        }
+        if seen[variant.Name] {
+            return fmt.Errorf("locale %q is listed twice", variant.Name)
+        }
+        seen[variant.Name] = true
+    }
+    return nil
+}
+
+// checkLocales checks every locale variant in parallel, each in its own check slot, and
+// aggregates the results into one entry, which succeeds only when every variant does
+func (um *UptimeMonitor) checkLocales(ctx context.Context, monitor Monitor) LogEntry {
+    results := make([]LogEntry, len(monitor.Locales))
+    var wg sync.WaitGroup
+    for i, variant := range monitor.Locales {
+        wg.Add(1)
+        go func(i int, variant LocaleVariant) {
+            defer wg.Done()
+            single := monitor
+            single.Locales = nil
+            single.AcceptLanguage = variant.AcceptLanguage
+            single.NegotiationHeaders = variant.Headers
+            results[i] = um.withCheckSlot(ctx, single, um.checkHTTP)
+        }(i, variant)
+    }
+    wg.Wait()
+
+    entry := LogEntry{Timestamp: um.now(), URL: monitor.URL, Success: true}
+    var failed []string
+    for i, result := range results {
+        entry.Locales = append(entry.Locales, LocaleResult{
+            Locale:          monitor.Locales[i].Name,
+            Success:         result.Success,
+            StatusCode:      result.StatusCode,
+            ResponseTime:    result.ResponseTime,
+            ContentLanguage: result.ContentLanguage,
+            Error:           result.Error,
+        })
+        entry.BytesSent += result.BytesSent
+        entry.BytesReceived += result.BytesReceived
+        if result.ResponseTime > entry.ResponseTime {
+            entry.ResponseTime = result.ResponseTime
+        }
+        if !result.Success {
+            if entry.Success {
+                // The first failing locale represents the check
+                entry.StatusCode = result.StatusCode
+                entry.ErrorClass = result.ErrorClass
+            }
+            entry.Success = false
+            failed = append(failed, monitor.Locales[i].Name)
+        }
+    }
+    if len(failed) > 0 {
+        entry.Error = fmt.Sprintf("%d of %d locales failed: %s", len(failed), len(monitor.Locales), strings.Join(failed, ", "))
+    } else {
+        entry.StatusCode = results[0].StatusCode
+    }
+    return entry
+}
@@ -0,0 +1,67 @@
+package entity
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestCheckLocales(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        language := strings.SplitN(r.Header.Get("Accept-Language"), "-", 2)[0]
+        if language == "fr" || r.Header.Get("Accept") != "text/html" {
+            http.NotFound(w, r)
+            return
+        }
+        w.Header().Set("Content-Language", language)
+    }))
+    defer server.Close()
+    um := NewUptimeMonitor()
+
+    html := map[string]string{"Accept": "text/html"}
+    monitor := Monitor{URL: server.URL + "/", Type: MonitorTypeHTTP, Locales: []LocaleVariant{
+        {Name: "en", AcceptLanguage: "en-US,en;q=0.9", Headers: html},
+        {Name: "de", AcceptLanguage: "de-DE", Headers: html},
+    }}
+    entry := um.attemptCheck(context.Background(), monitor)
+    if !entry.Success || entry.StatusCode != http.StatusOK || len(entry.Locales) != 2 {
+        t.Fatalf("check = %+v, want both locales to pass", entry)
+    }
+    if entry.Locales[0].ContentLanguage != "en" || entry.Locales[1].ContentLanguage != "de" {
+        t.Errorf("content languages = %+v", entry.Locales)
+    }
+
+    monitor.Locales = append(monitor.Locales,
+        LocaleVariant{Name: "fr", AcceptLanguage: "fr-FR", Headers: html},
+        LocaleVariant{Name: "json", AcceptLanguage: "en-US"})
+    entry = um.attemptCheck(context.Background(), monitor)
+    if entry.Success || entry.StatusCode != http.StatusNotFound {
+        t.Fatalf("check = %+v, want a failure with the first failing locale's status", entry)
+    }
+    if entry.Error != "2 of 4 locales failed: fr, json" {
+        t.Errorf("error = %q", entry.Error)
+    }
+    if !entry.Locales[0].Success || entry.Locales[2].Success || entry.Locales[2].StatusCode != http.StatusNotFound {
+        t.Errorf("locale results = %+v", entry.Locales)
+    }
+}
+
+func TestValidateLocales(t *testing.T) {
+    um := NewUptimeMonitor()
+    defer um.Close()
+
+    for _, monitor := range []Monitor{
+        {URL: "https://example.com", Interval: time.Hour, Locales: []LocaleVariant{{AcceptLanguage: "en"}}},
+        {URL: "https://example.com", Interval: time.Hour, Locales: []LocaleVariant{{Name: "en"}, {Name: "en"}}},
+        {URL: "https://example.com", Interval: time.Hour, Ports: []int{8443}, Locales: []LocaleVariant{{Name: "en"}}},
+        {URL: "tcp://example.com:443", Interval: time.Hour, Type: MonitorTypeTCP, Locales: []LocaleVariant{{Name: "en"}}},
+    } {
+        if err := um.AddMonitorConfig(monitor); err == nil {
+            t.Errorf("added a monitor with locales %+v, want an error", monitor.Locales)
+            um.RemoveMonitor(monitor.URL)
+        }
+    }
+}
@@ -4,10 +4,66 @@ import "time"
 
 // LogEntry represents a single monitoring log entry
 type LogEntry struct {
-    Timestamp    time.Time `json:"timestamp"`
-    URL          string    `json:"url"`
-    StatusCode   int       `json:"statusCode"`
-    ResponseTime int64     `json:"responseTime"` // in milliseconds
-    Success      bool      `json:"success"`
-    Error        string    `json:"error,omitempty"`
+    Timestamp      time.Time `json:"timestamp"`
+    URL            string    `json:"url"`
+    StatusCode     int       `json:"statusCode"`
+    ResponseTime   int64     `json:"responseTime"`         // in milliseconds
+    TTFB           int64     `json:"ttfb"`                 // time to first byte, in milliseconds
+    RemoteAddr     string    `json:"remoteAddr,omitempty"` // resolved IP:port the check connected to
+    Success        bool      `json:"success"`
+    Attempt        int       `json:"attempt,omitempty"` // 1-based, for monitors with retries
+    Retried        bool      `json:"retried,omitempty"` // a failed attempt superseded by a retry
+    Error          string    `json:"error,omitempty"`
+    ErrorClass     string    `json:"errorClass,omitempty"`
+    TLSVersion     string    `json:"tlsVersion,omitempty"`
+    CipherSuite    string    `json:"cipherSuite,omitempty"`
+    KeyType        string    `json:"keyType,omitempty"` // leaf certificate public key
+    KeyBits        int       `json:"keyBits,omitempty"`
+    CertExpiryDays int       `json:"certExpiryDays,omitempty"` // days until the earliest certificate in the chain expires
+    FinalURL       string    `json:"finalUrl,omitempty"`       // set when redirects led elsewhere
+    Redirects      int       `json:"redirects,omitempty"`
+
+    CacheStatus     string `json:"cacheStatus,omitempty"`     // hit or miss, for monitors with a cache header
+    POP             string `json:"pop,omitempty"`             // CDN edge location, for monitors with a POP header
+    ContentEncoding string `json:"contentEncoding,omitempty"` // declared encoding, for monitors verifying it
+    ContentLanguage string `json:"contentLanguage,omitempty"` // served language, for monitors sending Accept-Language
+
+    // SHA-256 of the response body, for monitors expecting a checksum
+    BodySHA256 string `json:"bodySha256,omitempty"`
+
+    // SPKI SHA-256 of the leaf certificate as sha256/<base64>, compared against a pinned fingerprint
+    CertFingerprint string `json:"certFingerprint,omitempty"`
+
+    // Approximate traffic of the check, headers included
+    BytesSent     int64 `json:"bytesSent,omitempty"`
+    BytesReceived int64 `json:"bytesReceived,omitempty"`
+
+    // Upload monitors record the streamed body size and its throughput in bytes per second
+    UploadBytes      int64   `json:"uploadBytes,omitempty"`
+    UploadThroughput float64 `json:"uploadThroughput,omitempty"`
+
+    // HTTP/3 monitors record the negotiated protocol and the QUIC connect plus TLS handshake time
+    Protocol      string `json:"protocol,omitempty"`
+    HandshakeTime int64  `json:"handshakeTime,omitempty"` // in milliseconds
+
+    // Results for each variant of a monitor with locales
+    Locales []LocaleResult `json:"locales,omitempty"`
+
+    // Results on each port of a multi-port monitor
+    Ports []PortResult `json:"ports,omitempty"`
+
+    // Results from remote agents asked to confirm a failure
+    Locations []AgentResult `json:"locations,omitempty"`
+
+    // Findings of assertions configured to warn instead of failing the check
+    Warnings       []string `json:"warnings,omitempty"`
+    MissingHeaders []string `json:"missingHeaders,omitempty"` // required response headers that were absent
+
+    // DNS monitors record the resolved records and whether they changed since the previous check
+    DNSRecords []DNSRecord `json:"dnsRecords,omitempty"`
+    DNSChanged bool        `json:"dnsChanged,omitempty"`
+
+    // Set when the check failed after the response had started, e.g. a timeout mid-body
+    Partial bool              `json:"partial,omitempty"`
+    Headers map[string]string `json:"headers,omitempty"` // response headers received before the failure
 }
\ No newline at end of file
@@ -0,0 +1,48 @@
+package entity
+
+import (
+    "fmt"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+)
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+// templatePlaceholders lists the values a monitor's MessageTemplate may refer to
+var templatePlaceholders = map[string]func(DowntimeEvent) string{
+    "url":   func(e DowntimeEvent) string { return e.URL },
+    "event": func(e DowntimeEvent) string { return e.Type },
+    "status": func(e DowntimeEvent) string {
+        if e.StatusCode == 0 {
+            return ""
+        }
+        return strconv.Itoa(e.StatusCode)
+    },
+    "duration":  func(e DowntimeEvent) string { return e.Duration },
+    "error":     func(e DowntimeEvent) string { return e.Error },
+    "timestamp": func(e DowntimeEvent) string { return e.Timestamp.Format(time.RFC3339) },
+}
+
+// validateMessageTemplate rejects unknown placeholders and stray braces
+func validateMessageTemplate(template string) error {
+    for _, match := range placeholderPattern.FindAllStringSubmatch(template, -1) {
+        if _, ok := templatePlaceholders[match[1]]; !ok {
+            return fmt.Errorf("unknown placeholder {{%s}} in message template", match[1])
+        }
+    }
+    rest := placeholderPattern.ReplaceAllString(template, "")
+    if strings.Contains(rest, "{{") || strings.Contains(rest, "}}") {
+        return fmt.Errorf("malformed placeholder in message template %q", template)
+    }
+    return nil
+}
+
+// renderMessage substitutes the event's values into a validated template
+func renderMessage(template string, event DowntimeEvent) string {
+    return placeholderPattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+        name := placeholderPattern.FindStringSubmatch(placeholder)[1]
+        return templatePlaceholders[name](event)
+    })
+}
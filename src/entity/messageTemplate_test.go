@@ -0,0 +1,57 @@
+package entity
+
+import (
+    "testing"
+    "time"
+)
+
+func TestRenderMessage(t *testing.T) {
+    event := DowntimeEvent{
+        Type:       EventDown,
+        URL:        "https://example.com/",
+        StatusCode: 503,
+        Error:      "service unavailable",
+        Timestamp:  time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+    }
+    tests := []struct {
+        template, want string
+    }{
+        {"{{url}} is {{ event }} ({{status}})", "https://example.com/ is down (503)"},
+        {"{{error}} at {{timestamp}}, see https://runbooks/{{url}}", "service unavailable at 2024-01-01T12:00:00Z, see https://runbooks/https://example.com/"},
+        {"down for {{duration}}", "down for "},
+        {"no placeholders", "no placeholders"},
+    }
+    for _, test := range tests {
+        if err := validateMessageTemplate(test.template); err != nil {
+            t.Errorf("validateMessageTemplate(%q) = %v", test.template, err)
+        }
+        if got := renderMessage(test.template, event); got != test.want {
+            t.Errorf("renderMessage(%q) = %q, want %q", test.template, got, test.want)
+        }
+    }
+
+    if got := renderMessage("status {{status}}", DowntimeEvent{}); got != "status " {
+        t.Errorf("status of an event without one = %q, want it empty", got)
+    }
+}
+
+func TestValidateMessageTemplate(t *testing.T) {
+    for _, template := range []string{"{{host}} is down", "{{url} is down", "{{ url }} is {{", "}}"} {
+        if err := validateMessageTemplate(template); err == nil {
+            t.Errorf("validateMessageTemplate(%q) succeeded, want an error", template)
+        }
+    }
+}
+
+func TestNotificationMessage(t *testing.T) {
+    um := NewUptimeMonitor()
+    alerter := newRecordingAlerter()
+    um.AddAlerter(alerter)
+
+    const url = "https://example.com/"
+    um.monitors[url] = Monitor{URL: url, MessageTemplate: "{{url}} returned {{status}}"}
+    um.recordCheck(LogEntry{URL: url, Success: false, StatusCode: 502, Timestamp: time.Now()})
+    if event := alerter.expect(t, EventDown); event.Message != "https://example.com/ returned 502" {
+        t.Errorf("message = %q", event.Message)
+    }
+}
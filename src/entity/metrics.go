@@ -0,0 +1,68 @@
+package entity
+
+import (
+    "encoding/json"
+    "net/http"
+    "sort"
+)
+
+// URLMetrics holds the metric values exported for a single monitored URL
+type URLMetrics struct {
+    URL              string `json:"url"`
+    Up               *int   `json:"up,omitempty"`     // 1 when the latest check succeeded, 0 when it failed; unset before the first check
+    LastResponseTime int64  `json:"lastResponseTime"` // in milliseconds
+    ChecksTotal      int64  `json:"checksTotal"`
+    FailuresTotal    int64  `json:"failuresTotal"`
+    IncidentsTotal   int64  `json:"incidentsTotal"` // downtimes opened
+    BytesSent        int64  `json:"bytesSent"`
+    BytesReceived    int64  `json:"bytesReceived"`
+
+    Latency LatencyHistogram `json:"latency"`
+}
+
+// CollectMetrics snapshots the metric values for every active monitor, sorted by URL.
+// It is the single source for all metric exports so that they always agree.
+func (um *UptimeMonitor) CollectMetrics() []URLMetrics {
+    um.mu.RLock()
+    defer um.mu.RUnlock()
+
+    metrics := make([]URLMetrics, 0, len(um.monitors))
+    for url := range um.monitors {
+        m := URLMetrics{URL: url}
+        state, ok := um.states[url]
+        if !ok {
+            state = &urlState{latency: newLatencyHistogram(um.buckets)}
+        }
+        m.Latency = state.latency.export(um.buckets)
+        if ok {
+            if state.checks > 0 {
+                up := 0
+                if state.lastSuccess {
+                    up = 1
+                }
+                m.Up = &up
+            }
+            m.LastResponseTime = state.lastResponseTime
+            m.ChecksTotal = state.checks
+            m.FailuresTotal = state.failures
+            m.IncidentsTotal = state.incidents
+            m.BytesSent = state.bytesSent
+            m.BytesReceived = state.bytesReceived
+        }
+        metrics = append(metrics, m)
+    }
+    sort.Slice(metrics, func(i, j int) bool {
+        return metrics[i].URL < metrics[j].URL
+    })
+    return metrics
+}
+
+func (um *UptimeMonitor) HandleMetricsJSON(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(um.CollectMetrics())
+}
@@ -0,0 +1,110 @@
+package entity
+
+import (
+    "bytes"
+    "encoding/json"
+    "math"
+    "net/http"
+    "net/http/httptest"
+    "slices"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestParseLatencyBuckets(t *testing.T) {
+    tests := []struct {
+        text    string
+        want    []float64
+        wantErr bool
+    }{
+        {text: "100,500,1000", want: []float64{100, 500, 1000}},
+        {text: "1000, 50 ,250", want: []float64{50, 250, 1000}},
+        {text: "0.5", want: []float64{0.5}},
+        {text: "100,100", wantErr: true},
+        {text: "100,0", wantErr: true},
+        {text: "-5", wantErr: true},
+        {text: "NaN", wantErr: true},
+        {text: "+Inf", wantErr: true},
+        {text: "100,fast", wantErr: true},
+        {text: "", wantErr: true},
+    }
+    for _, tt := range tests {
+        got, err := ParseLatencyBuckets(tt.text)
+        if tt.wantErr {
+            if err == nil {
+                t.Errorf("ParseLatencyBuckets(%q) = %v, want an error", tt.text, got)
+            }
+            continue
+        }
+        if err != nil || !slices.Equal(got, tt.want) {
+            t.Errorf("ParseLatencyBuckets(%q) = %v, %v, want %v", tt.text, got, err, tt.want)
+        }
+    }
+}
+
+func TestWithLatencyBuckets(t *testing.T) {
+    bounds := []float64{500, 100}
+    um := NewUptimeMonitor(WithLatencyBuckets(bounds))
+    if !slices.Equal(um.buckets, []float64{100, 500}) {
+        t.Errorf("buckets = %v, want them sorted", um.buckets)
+    }
+    if bounds[0] != 500 {
+        t.Error("the caller's slice was modified")
+    }
+
+    for _, invalid := range [][]float64{nil, {100, 100}, {math.NaN()}} {
+        um := NewUptimeMonitor(WithLatencyBuckets(invalid))
+        if !slices.Equal(um.buckets, defaultLatencyBuckets) {
+            t.Errorf("buckets for %v = %v, want the defaults", invalid, um.buckets)
+        }
+    }
+}
+
+func TestPrometheusUpOmittedBeforeFirstCheck(t *testing.T) {
+    down, up := 0, 1
+    var out bytes.Buffer
+    writePrometheusMetrics(&out, []URLMetrics{
+        {URL: "https://new.example.com/"},
+        {URL: "https://down.example.com/", Up: &down, ChecksTotal: 3},
+        {URL: "https://up.example.com/", Up: &up, ChecksTotal: 3},
+    })
+
+    text := out.String()
+    if strings.Contains(text, `urlmonitor_up{url="https://new.example.com/"}`) {
+        t.Error("up sample written for a URL that was never checked")
+    }
+    for _, want := range []string{
+        `urlmonitor_up{url="https://down.example.com/"} 0`,
+        `urlmonitor_up{url="https://up.example.com/"} 1`,
+        `urlmonitor_checks_total{url="https://new.example.com/"} 0`,
+    } {
+        if !strings.Contains(text, want) {
+            t.Errorf("metrics do not contain %s", want)
+        }
+    }
+}
+
+func TestMetricsJSONUpOmittedBeforeFirstCheck(t *testing.T) {
+    um := NewUptimeMonitor()
+    const checked, unchecked = "https://checked.example.com/", "https://new.example.com/"
+    um.monitors[checked] = Monitor{URL: checked}
+    um.monitors[unchecked] = Monitor{URL: unchecked}
+    um.recordCheck(LogEntry{URL: checked, Success: false, Timestamp: time.Now()})
+
+    rec := httptest.NewRecorder()
+    um.HandleMetricsJSON(rec, httptest.NewRequest(http.MethodGet, "/metrics.json", nil))
+    var metrics []map[string]any
+    if err := json.NewDecoder(rec.Body).Decode(&metrics); err != nil {
+        t.Fatal(err)
+    }
+    if len(metrics) != 2 {
+        t.Fatalf("metrics = %v, want both URLs", metrics)
+    }
+    if up, ok := metrics[0]["up"]; !ok || up != 0.0 {
+        t.Errorf("up of the failed URL = %v, want 0", up)
+    }
+    if up, ok := metrics[1]["up"]; ok {
+        t.Errorf("up of the unchecked URL = %v, want it omitted as in /metrics", up)
+    }
+}
@@ -1,9 +1,281 @@
 package entity
 
-import "time"
+import (
+    "encoding/json"
+    "time"
+)
+
+// minInterval keeps a misconfigured monitor from hammering its URL
+const minInterval = time.Second
+
+// Monitor types
+const (
+    MonitorTypeHTTP  = "http"
+    MonitorTypeFTP   = "ftp"   // control-channel handshake against ftp:// or ftps:// (implicit TLS) URLs
+    MonitorTypeDNS   = "dns"   // resolves the host of a dns://hostname URL and tracks record changes
+    MonitorTypeHTTP3 = "http3" // fetches an https:// URL over QUIC only, recording the handshake time
+    MonitorTypeTCP   = "tcp"   // connects to the host and port of a tcp://host:port URL, e.g. a database
+)
+
+// Echo modes comparing the response body against the request body
+const (
+    EchoExact    = "exact"
+    EchoContains = "contains"
+)
 
 // Monitor represents a URL to be monitored
 type Monitor struct {
     URL      string        `json:"url"`
     Interval time.Duration `json:"interval"`
+    Type     string        `json:"type,omitempty"`    // MonitorTypeHTTP (default), MonitorTypeFTP, MonitorTypeDNS, MonitorTypeHTTP3 or MonitorTypeTCP
+    Retries  int           `json:"retries,omitempty"` // attempts after a transient failure, a short delay apart, before the check fails
+
+    // RetryConnReset set to false counts a connection reset as a real failure, e.g. for an
+    // endpoint whose resets are persistent, instead of retrying it. Unset means true.
+    RetryConnReset *bool `json:"retryConnReset,omitempty"`
+
+    // Paused suspends checks, e.g. during planned maintenance, while keeping logs and downtimes
+    Paused bool `json:"paused,omitempty"`
+
+    // Timeout bounds each request attempt, including reading the body; zero means 10 seconds
+    Timeout time.Duration `json:"timeout,omitempty"`
+
+    // CheckBudget caps the total time of one check, including retries and the pauses between them
+    CheckBudget time.Duration `json:"checkBudget,omitempty"`
+
+    // HostHeader overrides the Host header, e.g. to reach a virtual host by IP address
+    HostHeader string `json:"hostHeader,omitempty"`
+
+    // Content negotiation headers sent with every request, e.g. Accept-Language for a localised site.
+    // Locales instead checks several variants together, each with its own headers.
+    AcceptLanguage     string            `json:"acceptLanguage,omitempty"`
+    NegotiationHeaders map[string]string `json:"negotiationHeaders,omitempty"` // e.g. Accept, Accept-Charset
+    Locales            []LocaleVariant   `json:"locales,omitempty"`
+
+    // Method defaults to GET, or POST when a Body is set. Headers are added to every request.
+    Method  string            `json:"method,omitempty"`
+    Headers map[string]string `json:"headers,omitempty"` // e.g. Authorization, Content-Type
+
+    // Credentials sent in the Authorization header, either basic auth or a bearer token. A 401
+    // still fails the check. They are redacted from exports but kept in plaintext in the state file.
+    BasicAuthUser string `json:"basicAuthUser,omitempty"`
+    BasicAuthPass string `json:"basicAuthPass,omitempty"`
+    BearerToken   string `json:"bearerToken,omitempty"`
+
+    // Body is sent with the request when set. EchoMode additionally requires the
+    // response body to echo it back, either exactly or as a substring.
+    Body     string `json:"body,omitempty"`
+    EchoMode string `json:"echoMode,omitempty"`
+
+    // UploadBytes streams a generated body of this size with a POST request and records the
+    // upload throughput, for endpoints whose health shows in accepting large uploads
+    UploadBytes int64 `json:"uploadBytes,omitempty"`
+
+    // Optional FTP login; without it only the server greeting is verified
+    FTPUser     string `json:"ftpUser,omitempty"`
+    FTPPassword string `json:"ftpPassword,omitempty"`
+
+    // FailOnDNSChange fails DNS checks whose records differ from the previous check instead of warning
+    FailOnDNSChange bool `json:"failOnDnsChange,omitempty"`
+
+    // AutoRemoveAfter removes the monitor once a downtime has stayed open this long; zero disables
+    AutoRemoveAfter time.Duration `json:"autoRemoveAfter,omitempty"`
+
+    // ConfirmWithAgents opens a downtime only when a majority of locations, this one and
+    // the configured remote agents, see the URL down
+    ConfirmWithAgents bool `json:"confirmWithAgents,omitempty"`
+
+    // DependsOn lists the URLs of monitors this one relies on, e.g. its database. While one of
+    // them is down, alerts for this monitor are suppressed and its downtimes are annotated.
+    DependsOn []string `json:"dependsOn,omitempty"`
+
+    // A downtime closes only after RecoverySuccesses consecutive successful checks (default 1)
+    // spanning at least RecoveryCooldown, so a single lucky check does not signal recovery
+    RecoverySuccesses int           `json:"recoverySuccesses,omitempty"`
+    RecoveryCooldown  time.Duration `json:"recoveryCooldown,omitempty"`
+
+    // SkipSuccessLogs keeps only failed checks in the log; uptime counters still include successes
+    SkipSuccessLogs bool `json:"skipSuccessLogs,omitempty"`
+
+    // TLS policy, only applied to HTTPS checks
+    MinTLSVersion     string `json:"minTLSVersion,omitempty"` // e.g. "1.2"
+    RejectWeakCiphers bool   `json:"rejectWeakCiphers,omitempty"`
+    MinRSAKeyBits     int    `json:"minRsaKeyBits,omitempty"`   // e.g. 2048
+    MinECDSAKeyBits   int    `json:"minEcdsaKeyBits,omitempty"` // e.g. 256
+    WarnOnWeakKey     bool   `json:"warnOnWeakKey,omitempty"`   // record a warning instead of failing
+
+    // CertExpiryThreshold flags HTTPS checks whose certificate chain expires within this many days
+    CertExpiryThreshold int  `json:"certExpiryThreshold,omitempty"`
+    WarnOnCertExpiry    bool `json:"warnOnCertExpiry,omitempty"` // record a warning instead of failing
+
+    // CertFingerprint pins the SHA-256 of the leaf certificate's public key (SPKI), as
+    // sha256/<base64> or hex; any other key fails the check, e.g. on MITM or unplanned rotation
+    CertFingerprint string `json:"certFingerprint,omitempty"`
+
+    // Ports checks the URL once per port, e.g. on every node of a cluster; the monitor is up
+    // only while every port answers, and each check records the per-port results
+    Ports []int `json:"ports,omitempty"`
+
+    // InsecureSkipVerify accepts any certificate, e.g. of an internal service with a self-signed
+    // one. INSECURE: the connection is open to interception. Other TLS checks still apply.
+    InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+    // TLSProfile customises the TLS client hello sent by this monitor's checks
+    TLSProfile *TLSProfile `json:"tlsProfile,omitempty"`
+
+    // MessageTemplate renders the message of this monitor's notifications, e.g. with a runbook link.
+    // Placeholders: {{url}}, {{event}}, {{status}}, {{duration}}, {{error}} and {{timestamp}}.
+    MessageTemplate string `json:"messageTemplate,omitempty"`
+
+    // ExpectedBody must occur in the response body, e.g. text only shown when the backend works.
+    // Only the first 1 MiB of the body is searched.
+    ExpectedBody string `json:"expectedBody,omitempty"`
+
+    // BodySHA256 is the expected hex SHA-256 of the response body, e.g. of a published static asset.
+    // Bodies are hashed up to the 1 MiB read limit.
+    BodySHA256 string `json:"bodySha256,omitempty"`
+
+    // RequireHTTPS fails the check unless redirects eventually land on an https:// URL
+    RequireHTTPS bool `json:"requireHttps,omitempty"`
+
+    // ExpectedStatus, when set, is the only status code counted as success instead of any 2xx.
+    // A 3xx expectation is checked against the redirect itself, which is then not followed.
+    ExpectedStatus int `json:"expectedStatus,omitempty"`
+
+    // AcceptStatus lists the status codes counted as success instead of any 2xx, as codes and
+    // ranges, e.g. "200-299,304". Accepted 3xx codes are checked against the redirect itself.
+    AcceptStatus string `json:"acceptStatus,omitempty"`
+
+    // FailOnRedirect treats any 3xx response as a failure instead of following it
+    FailOnRedirect bool `json:"failOnRedirect,omitempty"`
+
+    // FollowRedirects set to false judges a 3xx response by the success criteria as-is, e.g. to
+    // assert an endpoint answers 200 directly rather than through a login redirect. Unset means true.
+    FollowRedirects *bool `json:"followRedirects,omitempty"`
+
+    // MaxRedirects fails the check when following it takes more redirects, whatever the final status
+    MaxRedirects    int  `json:"maxRedirects,omitempty"`
+    WarnOnRedirects bool `json:"warnOnRedirects,omitempty"` // record a warning instead of failing
+
+    // Response headers that must be present, e.g. Strict-Transport-Security
+    RequiredHeaders      []string `json:"requiredHeaders,omitempty"`
+    WarnOnMissingHeaders bool     `json:"warnOnMissingHeaders,omitempty"` // record a warning instead of failing
+
+    // CacheHeader enables cache detection from a header such as X-Cache or CF-Cache-Status. A value
+    // containing one of CacheHitValues (default "HIT") counts as a hit. ExpectCache ("hit" or "miss")
+    // fails checks with the other status; MinCacheHitRate alerts when the recent hit rate drops below it.
+    CacheHeader     string   `json:"cacheHeader,omitempty"`
+    CacheHitValues  []string `json:"cacheHitValues,omitempty"`
+    ExpectCache     string   `json:"expectCache,omitempty"`
+    MinCacheHitRate float64  `json:"minCacheHitRate,omitempty"` // percent
+
+    // POPHeader names the header revealing the CDN edge location, e.g. CF-Ray or X-Served-By.
+    // POPPattern optionally extracts the location with a regular expression's capture group.
+    POPHeader  string `json:"popHeader,omitempty"`
+    POPPattern string `json:"popPattern,omitempty"`
+
+    // VerifyEncoding requests gzip and brotli and fails the check when the body does not
+    // decode according to its Content-Encoding; body assertions then see the decoded body
+    VerifyEncoding bool `json:"verifyEncoding,omitempty"`
+
+    // Numeric assertions on the JSON response, e.g. "$.queueDepth < 1000"
+    JSONAssertions []string `json:"jsonAssertions,omitempty"`
+
+    // Length bounds on JSON arrays, e.g. at least 3 entries in $.nodes
+    ArrayLengths []ArrayLengthAssertion `json:"arrayLengths,omitempty"`
+
+    // SigV4 signs every request with AWS credentials, e.g. for API Gateway endpoints
+    SigV4 *SigV4Config `json:"sigv4,omitempty"`
+}
+
+// MonitorConfig is the external representation of a Monitor used by the add endpoint
+// and config sources. It carries every Monitor field, except that durations are ConfigDurations.
+type MonitorConfig struct {
+    Monitor
+    Interval         ConfigDuration `json:"interval,omitempty"`
+    Timeout          ConfigDuration `json:"timeout,omitempty"`
+    CheckBudget      ConfigDuration `json:"checkBudget,omitempty"`
+    AutoRemoveAfter  ConfigDuration `json:"autoRemoveAfter,omitempty"`
+    RecoveryCooldown ConfigDuration `json:"recoveryCooldown,omitempty"`
+    // Archived marks archived monitors in the monitor list; it is ignored when adding
+    Archived bool `json:"archived,omitempty"`
+}
+
+// ConfigDuration is a duration given either as a number of seconds, e.g. 300, or as a
+// duration string, e.g. "5m" or "500ms". Whole seconds are written back as a number.
+type ConfigDuration time.Duration
+
+func (d ConfigDuration) MarshalJSON() ([]byte, error) {
+    if time.Duration(d)%time.Second == 0 {
+        return json.Marshal(int64(time.Duration(d) / time.Second))
+    }
+    return json.Marshal(time.Duration(d).String())
+}
+
+func (d *ConfigDuration) UnmarshalJSON(data []byte) error {
+    var text string
+    if err := json.Unmarshal(data, &text); err == nil {
+        parsed, err := time.ParseDuration(text)
+        if err != nil {
+            return err
+        }
+        *d = ConfigDuration(parsed)
+        return nil
+    }
+
+    var seconds float64
+    if err := json.Unmarshal(data, &seconds); err != nil {
+        return err
+    }
+    *d = ConfigDuration(seconds * float64(time.Second))
+    return nil
+}
+
+// Config converts the monitor into its external representation, the inverse of ToMonitor
+func (m Monitor) Config() MonitorConfig {
+    return MonitorConfig{
+        Monitor:          m,
+        Interval:         ConfigDuration(m.Interval),
+        Timeout:          ConfigDuration(m.Timeout),
+        CheckBudget:      ConfigDuration(m.CheckBudget),
+        AutoRemoveAfter:  ConfigDuration(m.AutoRemoveAfter),
+        RecoveryCooldown: ConfigDuration(m.RecoveryCooldown),
+    }
+}
+
+// timeout returns the per-attempt request timeout, applying the default
+func (m Monitor) timeout() time.Duration {
+    if m.Timeout > 0 {
+        return m.Timeout
+    }
+    return defaultTimeout
+}
+
+// statusOK reports whether a response status counts as success for the monitor
+func (m Monitor) statusOK(code int) bool {
+    if m.ExpectedStatus != 0 {
+        return code == m.ExpectedStatus
+    }
+    if m.AcceptStatus != "" {
+        return acceptsStatus(m.AcceptStatus, code)
+    }
+    return code >= 200 && code < 300
+}
+
+// stopsAtRedirect reports whether checks use the first 3xx response instead of following it
+func (m Monitor) stopsAtRedirect() bool {
+    if m.FollowRedirects != nil && !*m.FollowRedirects {
+        return true
+    }
+    return m.FailOnRedirect || m.ExpectedStatus >= 300 && m.ExpectedStatus < 400 || acceptsRedirect(m.AcceptStatus)
+}
+
+func (c MonitorConfig) ToMonitor() Monitor {
+    monitor := c.Monitor
+    monitor.Interval = time.Duration(c.Interval)
+    monitor.Timeout = time.Duration(c.Timeout)
+    monitor.CheckBudget = time.Duration(c.CheckBudget)
+    monitor.AutoRemoveAfter = time.Duration(c.AutoRemoveAfter)
+    monitor.RecoveryCooldown = time.Duration(c.RecoveryCooldown)
+    return monitor
 }
\ No newline at end of file
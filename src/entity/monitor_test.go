@@ -0,0 +1,68 @@
+package entity
+
+import (
+    "encoding/json"
+    "testing"
+    "time"
+)
+
+func TestConfigDurationUnmarshal(t *testing.T) {
+    tests := []struct {
+        json string
+        want time.Duration
+    }{
+        {`300`, 5 * time.Minute},
+        {`1.5`, 1500 * time.Millisecond},
+        {`"5m"`, 5 * time.Minute},
+        {`"500ms"`, 500 * time.Millisecond},
+        {`"1h30m"`, 90 * time.Minute},
+    }
+    for _, test := range tests {
+        var d ConfigDuration
+        if err := json.Unmarshal([]byte(test.json), &d); err != nil || time.Duration(d) != test.want {
+            t.Errorf("unmarshal %s = %v, %v, want %v", test.json, time.Duration(d), err, test.want)
+        }
+    }
+    for _, invalid := range []string{`"5 minutes"`, `true`, `"300"`} {
+        var d ConfigDuration
+        if err := json.Unmarshal([]byte(invalid), &d); err == nil {
+            t.Errorf("unmarshal %s = %v, want an error", invalid, time.Duration(d))
+        }
+    }
+}
+
+func TestConfigDurationMarshal(t *testing.T) {
+    for d, want := range map[time.Duration]string{
+        5 * time.Minute:         `300`,
+        0:                       `0`,
+        1500 * time.Millisecond: `"1.5s"`,
+    } {
+        if data, err := json.Marshal(ConfigDuration(d)); err != nil || string(data) != want {
+            t.Errorf("marshal %v = %s, %v, want %s", d, data, err, want)
+        }
+    }
+}
+
+func TestMonitorConfigRoundTrip(t *testing.T) {
+    var config MonitorConfig
+    data := `{"url": "https://example.com/", "interval": "2m", "timeout": 1.5, "recoveryCooldown": 600}`
+    if err := json.Unmarshal([]byte(data), &config); err != nil {
+        t.Fatal(err)
+    }
+    monitor := config.ToMonitor()
+    if monitor.Interval != 2*time.Minute || monitor.Timeout != 1500*time.Millisecond || monitor.RecoveryCooldown != 10*time.Minute {
+        t.Errorf("monitor = interval %v, timeout %v, cooldown %v", monitor.Interval, monitor.Timeout, monitor.RecoveryCooldown)
+    }
+
+    encoded, err := json.Marshal(monitor.Config())
+    if err != nil {
+        t.Fatal(err)
+    }
+    var again MonitorConfig
+    if err := json.Unmarshal(encoded, &again); err != nil {
+        t.Fatal(err)
+    }
+    if again.ToMonitor().Interval != monitor.Interval || again.ToMonitor().Timeout != monitor.Timeout {
+        t.Errorf("round trip through %s changed the durations", encoded)
+    }
+}
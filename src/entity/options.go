@@ -0,0 +1,106 @@
+package entity
+
+import (
+    "log/slog"
+    "math/rand"
+    "net/http"
+    "time"
+)
+
+// Option configures an UptimeMonitor at construction time
+type Option func(*UptimeMonitor)
+
+// WithDebugToken protects the debug endpoints with a bearer token
+func WithDebugToken(token string) Option {
+    return func(um *UptimeMonitor) {
+        um.debugToken = token
+    }
+}
+
+// WithDowntimeMergeGap reopens a just-closed downtime instead of starting a new one
+// when a URL fails again within the given gap after recovering
+func WithDowntimeMergeGap(gap time.Duration) Option {
+    return func(um *UptimeMonitor) {
+        um.mergeGap = gap
+    }
+}
+
+// WithProxyResolver routes outbound checks through the proxy chosen by a PAC script
+func WithProxyResolver(resolver *PACResolver) Option {
+    return func(um *UptimeMonitor) {
+        transport := http.DefaultTransport.(*http.Transport).Clone()
+        transport.Proxy = resolver.Proxy
+        um.client.Transport = transport
+    }
+}
+
+// WithStaleAfter reports a URL's current state as stale once its latest check is older than d
+func WithStaleAfter(d time.Duration) Option {
+    return func(um *UptimeMonitor) {
+        um.staleAfter = d
+    }
+}
+
+// WithClock replaces the wall clock used to timestamp checks and downtimes
+func WithClock(now func() time.Time) Option {
+    return func(um *UptimeMonitor) {
+        um.now = now
+    }
+}
+
+// WithRetryJitter sets the upper bound of the random delay added to each retry; zero disables jitter
+func WithRetryJitter(jitter time.Duration) Option {
+    return func(um *UptimeMonitor) {
+        um.retryJitter = jitter
+    }
+}
+
+// WithRandSource replaces the random source used for jitter, e.g. with a seeded one in tests
+func WithRandSource(src rand.Source) Option {
+    return func(um *UptimeMonitor) {
+        um.rand = rand.New(src)
+    }
+}
+
+// WithNotificationLogContext sets how many recent log entries are attached to notifications
+func WithNotificationLogContext(n int) Option {
+    return func(um *UptimeMonitor) {
+        um.logContext = n
+    }
+}
+
+// WithLatencyBuckets sets the response time histogram bucket bounds in milliseconds, in any order.
+// Invalid bounds, such as duplicates or non-positive values, are logged and the defaults kept.
+func WithLatencyBuckets(bounds []float64) Option {
+    return func(um *UptimeMonitor) {
+        sorted, err := sortedLatencyBuckets(bounds)
+        if err != nil {
+            slog.Warn("Invalid latency buckets, using the defaults", "error", err)
+            return
+        }
+        um.buckets = sorted
+    }
+}
+
+// WithDNSResolver replaces the resolver used by DNS monitors, e.g. with one that reports TTLs
+func WithDNSResolver(resolver DNSResolver) Option {
+    return func(um *UptimeMonitor) {
+        um.dnsResolver = resolver
+    }
+}
+
+// WithMaxLogs caps the number of log entries retained across all URLs, evicting the oldest
+// first; zero keeps every entry. Stats totals and downtimes are unaffected by eviction.
+func WithMaxLogs(n int) Option {
+    return func(um *UptimeMonitor) {
+        um.maxLogs = n
+    }
+}
+
+// WithoutSuccessLogs stops retaining log entries of successful checks for every monitor.
+// Counters, stats totals and downtime tracking still include them.
+func WithoutSuccessLogs() Option {
+    return func(um *UptimeMonitor) {
+        um.skipSuccess = true
+    }
+}
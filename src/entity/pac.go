@@ -0,0 +1,899 @@
+package entity
+
+import (
+    "context"
+    "fmt"
+    "net"
+    "net/http"
+    "net/url"
+    "os"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// PACResolver chooses an outbound proxy per target URL by evaluating a
+// Proxy Auto-Config script. Only the subset of JavaScript that PAC files
+// commonly use is supported:
+//
+//   - top-level function declarations; FindProxyForURL(url, host) is required
+//   - var declarations, assignment, if/else, return and expression statements
+//   - string, number, true/false and null/undefined literals
+//   - the operators !, &&, ||, ==, !=, ===, !==, <, >, <=, >= and +
+//   - the helpers isPlainHostName, dnsDomainIs, localHostOrDomainIs,
+//     dnsDomainLevels, shExpMatch, isResolvable, dnsResolve, isInNet,
+//     myIpAddress and alert (a no-op)
+//
+// Statements outside functions, loops, objects, regular expressions and the
+// date/time helpers are rejected; a backslash in a string literal simply
+// quotes the next character. Scripts may come from untrusted sources, so
+// their size, nesting, call depth and number of calls per evaluation are
+// bounded.
+type PACResolver struct {
+    functions map[string]*pacFunction
+
+    // Credentials are attached to every proxy chosen by the script, for
+    // proxies that require authentication
+    Credentials *url.Userinfo
+}
+
+// Limits on untrusted PAC scripts
+const (
+    maxPACScriptBytes = 1 << 20
+    maxPACNesting     = 100             // nested statements and expressions
+    maxPACCallDepth   = 64              // nested function calls
+    maxPACCalls       = 10000           // function calls per evaluation, bounding recursive scripts
+    pacDNSTimeout     = 2 * time.Second // per lookup of isResolvable, dnsResolve and isInNet
+)
+
+// LoadPACFile reads and parses a PAC script from disk
+func LoadPACFile(path string) (*PACResolver, error) {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+    return ParsePAC(string(data))
+}
+
+// ParsePAC parses a PAC script, which must define FindProxyForURL(url, host)
+func ParsePAC(script string) (*PACResolver, error) {
+    if len(script) > maxPACScriptBytes {
+        return nil, fmt.Errorf("PAC script exceeds %d bytes", maxPACScriptBytes)
+    }
+    tokens, err := tokenizePAC(script)
+    if err != nil {
+        return nil, err
+    }
+    p := &pacParser{tokens: tokens}
+    functions, err := p.parseProgram()
+    if err != nil {
+        return nil, err
+    }
+    if fn, ok := functions["FindProxyForURL"]; !ok || len(fn.params) != 2 {
+        return nil, fmt.Errorf("PAC script does not define FindProxyForURL(url, host)")
+    }
+    return &PACResolver{functions: functions}, nil
+}
+
+// FindProxy returns the raw PAC result for the target, e.g. "PROXY proxy:8080; DIRECT"
+func (pr *PACResolver) FindProxy(target *url.URL) (string, error) {
+    run := &pacRun{resolver: pr}
+    result, err := run.call("FindProxyForURL", []pacValue{target.String(), target.Hostname()}, 0)
+    if err != nil {
+        return "", fmt.Errorf("PAC evaluation failed: %w", err)
+    }
+    s, ok := result.(string)
+    if !ok {
+        return "", fmt.Errorf("PAC evaluation failed: FindProxyForURL returned %v, expected a string", result)
+    }
+    return s, nil
+}
+
+// Proxy implements http.Transport's Proxy hook. Evaluation errors are returned
+// so that they surface as check errors.
+func (pr *PACResolver) Proxy(req *http.Request) (*url.URL, error) {
+    result, err := pr.FindProxy(req.URL)
+    if err != nil {
+        return nil, err
+    }
+    return parsePACResult(result, pr.Credentials)
+}
+
+// parsePACResult picks the first usable entry of a PAC result. A nil URL means DIRECT.
+func parsePACResult(result string, credentials *url.Userinfo) (*url.URL, error) {
+    for _, entry := range strings.Split(result, ";") {
+        fields := strings.Fields(entry)
+        if len(fields) == 0 {
+            continue
+        }
+
+        var scheme string
+        switch strings.ToUpper(fields[0]) {
+        case "DIRECT":
+            return nil, nil
+        case "PROXY", "HTTP":
+            scheme = "http"
+        case "HTTPS":
+            scheme = "https"
+        case "SOCKS", "SOCKS5":
+            scheme = "socks5"
+        default:
+            continue
+        }
+        if len(fields) < 2 {
+            return nil, fmt.Errorf("PAC result %q is missing a proxy address", entry)
+        }
+        return &url.URL{Scheme: scheme, Host: fields[1], User: credentials}, nil
+    }
+    return nil, fmt.Errorf("PAC result %q contains no supported proxy", result)
+}
+
+// Script evaluation
+
+type pacValue interface{} // string, bool or float64
+
+type pacFunction struct {
+    params []string
+    body   []pacStmt
+}
+
+// pacRun is one evaluation of a script; resolvers are shared by concurrent requests
+type pacRun struct {
+    resolver *PACResolver
+    calls    int
+}
+
+type pacEnv struct {
+    run   *pacRun
+    vars  map[string]pacValue
+    depth int
+}
+
+func (run *pacRun) call(name string, args []pacValue, depth int) (pacValue, error) {
+    if depth > maxPACCallDepth {
+        return nil, fmt.Errorf("maximum call depth exceeded")
+    }
+    if run.calls++; run.calls > maxPACCalls {
+        return nil, fmt.Errorf("more than %d function calls", maxPACCalls)
+    }
+
+    fn, ok := run.resolver.functions[name]
+    if !ok {
+        return callPACBuiltin(name, args)
+    }
+
+    env := &pacEnv{run: run, vars: make(map[string]pacValue), depth: depth}
+    for i, param := range fn.params {
+        if i < len(args) {
+            env.vars[param] = args[i]
+        } else {
+            env.vars[param] = ""
+        }
+    }
+    for _, stmt := range fn.body {
+        value, returned, err := stmt.exec(env)
+        if err != nil || returned {
+            return value, err
+        }
+    }
+    return "", nil
+}
+
+func pacTruthy(v pacValue) bool {
+    switch v := v.(type) {
+    case bool:
+        return v
+    case string:
+        return v != ""
+    case float64:
+        return v != 0
+    }
+    return false
+}
+
+func pacString(v pacValue) string {
+    switch v := v.(type) {
+    case string:
+        return v
+    case bool:
+        return strconv.FormatBool(v)
+    case float64:
+        return strconv.FormatFloat(v, 'f', -1, 64)
+    }
+    return ""
+}
+
+func pacNumber(v pacValue) float64 {
+    switch v := v.(type) {
+    case float64:
+        return v
+    case bool:
+        if v {
+            return 1
+        }
+        return 0
+    case string:
+        f, _ := strconv.ParseFloat(strings.TrimSpace(v), 64)
+        return f
+    }
+    return 0
+}
+
+// Statements
+
+type pacStmt interface {
+    exec(env *pacEnv) (value pacValue, returned bool, err error)
+}
+
+type pacBlock []pacStmt
+
+func (b pacBlock) exec(env *pacEnv) (pacValue, bool, error) {
+    for _, stmt := range b {
+        value, returned, err := stmt.exec(env)
+        if err != nil || returned {
+            return value, returned, err
+        }
+    }
+    return nil, false, nil
+}
+
+type pacIf struct {
+    cond      pacExpr
+    then, els pacStmt
+}
+
+func (s *pacIf) exec(env *pacEnv) (pacValue, bool, error) {
+    cond, err := s.cond.eval(env)
+    if err != nil {
+        return nil, false, err
+    }
+    if pacTruthy(cond) {
+        return s.then.exec(env)
+    }
+    if s.els != nil {
+        return s.els.exec(env)
+    }
+    return nil, false, nil
+}
+
+type pacReturn struct {
+    value pacExpr
+}
+
+func (s *pacReturn) exec(env *pacEnv) (pacValue, bool, error) {
+    if s.value == nil {
+        return "", true, nil
+    }
+    value, err := s.value.eval(env)
+    return value, true, err
+}
+
+type pacAssign struct {
+    name  string
+    value pacExpr
+}
+
+func (s *pacAssign) exec(env *pacEnv) (pacValue, bool, error) {
+    value, err := s.value.eval(env)
+    if err != nil {
+        return nil, false, err
+    }
+    env.vars[s.name] = value
+    return nil, false, nil
+}
+
+type pacExprStmt struct {
+    expr pacExpr
+}
+
+func (s *pacExprStmt) exec(env *pacEnv) (pacValue, bool, error) {
+    _, err := s.expr.eval(env)
+    return nil, false, err
+}
+
+// Expressions
+
+type pacExpr interface {
+    eval(env *pacEnv) (pacValue, error)
+}
+
+type pacLiteral struct {
+    value pacValue
+}
+
+func (e *pacLiteral) eval(env *pacEnv) (pacValue, error) {
+    return e.value, nil
+}
+
+type pacIdent struct {
+    name string
+}
+
+func (e *pacIdent) eval(env *pacEnv) (pacValue, error) {
+    value, ok := env.vars[e.name]
+    if !ok {
+        return nil, fmt.Errorf("%s is not defined", e.name)
+    }
+    return value, nil
+}
+
+type pacNot struct {
+    operand pacExpr
+}
+
+func (e *pacNot) eval(env *pacEnv) (pacValue, error) {
+    value, err := e.operand.eval(env)
+    if err != nil {
+        return nil, err
+    }
+    return !pacTruthy(value), nil
+}
+
+type pacBinary struct {
+    op          string
+    left, right pacExpr
+}
+
+func (e *pacBinary) eval(env *pacEnv) (pacValue, error) {
+    left, err := e.left.eval(env)
+    if err != nil {
+        return nil, err
+    }
+
+    // Short-circuit operators return the deciding operand, as in JavaScript
+    switch e.op {
+    case "&&":
+        if !pacTruthy(left) {
+            return left, nil
+        }
+        return e.right.eval(env)
+    case "||":
+        if pacTruthy(left) {
+            return left, nil
+        }
+        return e.right.eval(env)
+    }
+
+    right, err := e.right.eval(env)
+    if err != nil {
+        return nil, err
+    }
+
+    switch e.op {
+    case "+":
+        _, ls := left.(string)
+        _, rs := right.(string)
+        if ls || rs {
+            return pacString(left) + pacString(right), nil
+        }
+        return pacNumber(left) + pacNumber(right), nil
+    case "==", "===":
+        return pacEqual(left, right), nil
+    case "!=", "!==":
+        return !pacEqual(left, right), nil
+    case "<":
+        return pacNumber(left) < pacNumber(right), nil
+    case ">":
+        return pacNumber(left) > pacNumber(right), nil
+    case "<=":
+        return pacNumber(left) <= pacNumber(right), nil
+    case ">=":
+        return pacNumber(left) >= pacNumber(right), nil
+    }
+    return nil, fmt.Errorf("unsupported operator %s", e.op)
+}
+
+func pacEqual(left, right pacValue) bool {
+    ls, lok := left.(string)
+    rs, rok := right.(string)
+    if lok && rok {
+        return ls == rs
+    }
+    return pacNumber(left) == pacNumber(right)
+}
+
+type pacCall struct {
+    name string
+    args []pacExpr
+}
+
+func (e *pacCall) eval(env *pacEnv) (pacValue, error) {
+    args := make([]pacValue, len(e.args))
+    for i, arg := range e.args {
+        value, err := arg.eval(env)
+        if err != nil {
+            return nil, err
+        }
+        args[i] = value
+    }
+    return env.run.call(e.name, args, env.depth+1)
+}
+
+// Standard PAC helper functions
+
+func callPACBuiltin(name string, args []pacValue) (pacValue, error) {
+    arg := func(i int) string {
+        if i < len(args) {
+            return pacString(args[i])
+        }
+        return ""
+    }
+
+    switch name {
+    case "isPlainHostName":
+        return !strings.Contains(arg(0), "."), nil
+    case "dnsDomainIs":
+        return strings.HasSuffix(strings.ToLower(arg(0)), strings.ToLower(arg(1))), nil
+    case "localHostOrDomainIs":
+        host, hostdom := strings.ToLower(arg(0)), strings.ToLower(arg(1))
+        return host == hostdom || (!strings.Contains(host, ".") && strings.HasPrefix(hostdom, host+".")), nil
+    case "dnsDomainLevels":
+        return float64(strings.Count(arg(0), ".")), nil
+    case "shExpMatch":
+        return shExpMatch(arg(0), arg(1)), nil
+    case "isResolvable":
+        return pacResolve(arg(0)) != nil, nil
+    case "dnsResolve":
+        if ip := pacResolve(arg(0)); ip != nil {
+            return ip.String(), nil
+        }
+        return "", nil
+    case "isInNet":
+        ip := pacResolve(arg(0))
+        pattern := net.ParseIP(arg(1)).To4()
+        mask := net.ParseIP(arg(2)).To4()
+        if ip == nil || pattern == nil || mask == nil {
+            return false, nil
+        }
+        return ip.Mask(net.IPMask(mask)).Equal(pattern.Mask(net.IPMask(mask))), nil
+    case "myIpAddress":
+        return myIPAddress(), nil
+    case "alert":
+        return "", nil
+    }
+    return nil, fmt.Errorf("function %s is not supported", name)
+}
+
+// shExpMatch matches a shell expression where * matches any run of characters and ? a single one
+func shExpMatch(s, pattern string) bool {
+    expr := regexp.QuoteMeta(pattern)
+    expr = strings.ReplaceAll(expr, `\*`, ".*")
+    expr = strings.ReplaceAll(expr, `\?`, ".")
+    matched, err := regexp.MatchString("^"+expr+"$", s)
+    return err == nil && matched
+}
+
+// pacResolve returns the host's first IPv4 address, or nil
+func pacResolve(host string) net.IP {
+    if ip := net.ParseIP(host); ip != nil {
+        return ip.To4()
+    }
+    ctx, cancel := context.WithTimeout(context.Background(), pacDNSTimeout)
+    defer cancel()
+    addrs, err := net.DefaultResolver.LookupIP(ctx, "ip4", host)
+    if err != nil || len(addrs) == 0 {
+        return nil
+    }
+    return addrs[0].To4()
+}
+
+func myIPAddress() string {
+    // No packets are sent, dialing UDP only selects the outbound interface
+    conn, err := net.Dial("udp", "198.51.100.1:80")
+    if err != nil {
+        return "127.0.0.1"
+    }
+    defer conn.Close()
+    return conn.LocalAddr().(*net.UDPAddr).IP.String()
+}
+
+// Tokenizer
+
+type pacToken struct {
+    kind int
+    text string
+}
+
+const (
+    pacTokIdent = iota
+    pacTokString
+    pacTokNumber
+    pacTokPunct
+    pacTokEOF
+)
+
+// pacReservedWords are JavaScript keywords outside the supported subset, rejected wherever an
+// identifier or expression is expected rather than mistaken for calls or variables
+var pacReservedWords = map[string]bool{
+    "break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+    "delete": true, "do": true, "for": true, "function": true, "in": true, "instanceof": true,
+    "let": true, "new": true, "switch": true, "this": true, "throw": true, "try": true,
+    "typeof": true, "void": true, "while": true, "with": true,
+}
+
+var pacPunctuation = []string{"===", "!==", "==", "!=", "<=", ">=", "&&", "||", "(", ")", "{", "}", ";", ",", "!", "<", ">", "+", "="}
+
+func tokenizePAC(src string) ([]pacToken, error) {
+    var tokens []pacToken
+    for i := 0; i < len(src); {
+        c := src[i]
+        switch {
+        case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+            i++
+        case strings.HasPrefix(src[i:], "//"):
+            for i < len(src) && src[i] != '\n' {
+                i++
+            }
+        case strings.HasPrefix(src[i:], "/*"):
+            end := strings.Index(src[i+2:], "*/")
+            if end < 0 {
+                return nil, fmt.Errorf("PAC script has an unterminated comment")
+            }
+            i += end + 4
+        case c == '"' || c == '\'':
+            var sb strings.Builder
+            j := i + 1
+            for ; j < len(src) && src[j] != c; j++ {
+                if src[j] == '\\' && j+1 < len(src) {
+                    j++
+                }
+                sb.WriteByte(src[j])
+            }
+            if j >= len(src) {
+                return nil, fmt.Errorf("PAC script has an unterminated string")
+            }
+            tokens = append(tokens, pacToken{kind: pacTokString, text: sb.String()})
+            i = j + 1
+        case c >= '0' && c <= '9':
+            j := i
+            for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.') {
+                j++
+            }
+            tokens = append(tokens, pacToken{kind: pacTokNumber, text: src[i:j]})
+            i = j
+        case c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+            j := i
+            for j < len(src) && (src[j] == '_' || src[j] == '$' || src[j] >= 'a' && src[j] <= 'z' ||
+                src[j] >= 'A' && src[j] <= 'Z' || src[j] >= '0' && src[j] <= '9') {
+                j++
+            }
+            tokens = append(tokens, pacToken{kind: pacTokIdent, text: src[i:j]})
+            i = j
+        default:
+            matched := false
+            for _, punct := range pacPunctuation {
+                if strings.HasPrefix(src[i:], punct) {
+                    tokens = append(tokens, pacToken{kind: pacTokPunct, text: punct})
+                    i += len(punct)
+                    matched = true
+                    break
+                }
+            }
+            if !matched {
+                return nil, fmt.Errorf("PAC script has unsupported character %q", c)
+            }
+        }
+    }
+    return append(tokens, pacToken{kind: pacTokEOF}), nil
+}
+
+// Parser
+
+type pacParser struct {
+    tokens []pacToken
+    pos    int
+    depth  int // current nesting, bounded by maxPACNesting
+}
+
+// enter descends one nesting level; every successful call must be paired with leave
+func (p *pacParser) enter() error {
+    if p.depth >= maxPACNesting {
+        return fmt.Errorf("PAC parse error: nested more than %d levels deep", maxPACNesting)
+    }
+    p.depth++
+    return nil
+}
+
+func (p *pacParser) leave() {
+    p.depth--
+}
+
+func (p *pacParser) peek() pacToken {
+    return p.tokens[p.pos]
+}
+
+func (p *pacParser) next() pacToken {
+    tok := p.tokens[p.pos]
+    if tok.kind != pacTokEOF {
+        p.pos++
+    }
+    return tok
+}
+
+// accept consumes the next token if it is the given punctuation or keyword
+func (p *pacParser) accept(text string) bool {
+    tok := p.peek()
+    if (tok.kind == pacTokPunct || tok.kind == pacTokIdent) && tok.text == text {
+        p.pos++
+        return true
+    }
+    return false
+}
+
+func (p *pacParser) expect(text string) error {
+    if !p.accept(text) {
+        return fmt.Errorf("PAC parse error: expected %q, found %q", text, p.peek().text)
+    }
+    return nil
+}
+
+func (p *pacParser) ident() (string, error) {
+    tok := p.next()
+    if tok.kind != pacTokIdent || pacReservedWords[tok.text] {
+        return "", fmt.Errorf("PAC parse error: expected identifier, found %q", tok.text)
+    }
+    return tok.text, nil
+}
+
+func (p *pacParser) parseProgram() (map[string]*pacFunction, error) {
+    functions := make(map[string]*pacFunction)
+    for p.peek().kind != pacTokEOF {
+        if p.accept(";") {
+            continue
+        }
+        if err := p.expect("function"); err != nil {
+            return nil, err
+        }
+        name, err := p.ident()
+        if err != nil {
+            return nil, err
+        }
+
+        fn := &pacFunction{}
+        if err := p.expect("("); err != nil {
+            return nil, err
+        }
+        for !p.accept(")") {
+            param, err := p.ident()
+            if err != nil {
+                return nil, err
+            }
+            fn.params = append(fn.params, param)
+            if !p.accept(",") && p.peek().text != ")" {
+                return nil, fmt.Errorf("PAC parse error: malformed parameters of %s", name)
+            }
+        }
+
+        body, err := p.parseBlock()
+        if err != nil {
+            return nil, err
+        }
+        fn.body = body
+        functions[name] = fn
+    }
+    return functions, nil
+}
+
+func (p *pacParser) parseBlock() (pacBlock, error) {
+    if err := p.expect("{"); err != nil {
+        return nil, err
+    }
+    var block pacBlock
+    for !p.accept("}") {
+        if p.peek().kind == pacTokEOF {
+            return nil, fmt.Errorf("PAC parse error: unexpected end of script")
+        }
+        stmt, err := p.parseStmt()
+        if err != nil {
+            return nil, err
+        }
+        if stmt != nil {
+            block = append(block, stmt)
+        }
+    }
+    return block, nil
+}
+
+func (p *pacParser) parseStmt() (pacStmt, error) {
+    if err := p.enter(); err != nil {
+        return nil, err
+    }
+    defer p.leave()
+
+    switch {
+    case p.accept(";"):
+        return nil, nil
+    case p.peek().kind == pacTokPunct && p.peek().text == "{":
+        return p.parseBlock()
+    case p.accept("if"):
+        if err := p.expect("("); err != nil {
+            return nil, err
+        }
+        cond, err := p.parseExpr()
+        if err != nil {
+            return nil, err
+        }
+        if err := p.expect(")"); err != nil {
+            return nil, err
+        }
+        stmt := &pacIf{cond: cond}
+        if stmt.then, err = p.parseBranch(); err != nil {
+            return nil, err
+        }
+        if p.accept("else") {
+            if stmt.els, err = p.parseBranch(); err != nil {
+                return nil, err
+            }
+        }
+        return stmt, nil
+    case p.accept("return"):
+        if p.accept(";") || p.peek().text == "}" {
+            return &pacReturn{}, nil
+        }
+        value, err := p.parseExpr()
+        if err != nil {
+            return nil, err
+        }
+        p.accept(";")
+        return &pacReturn{value: value}, nil
+    case p.accept("var"):
+        name, err := p.ident()
+        if err != nil {
+            return nil, err
+        }
+        var value pacExpr = &pacLiteral{value: ""}
+        if p.accept("=") {
+            if value, err = p.parseExpr(); err != nil {
+                return nil, err
+            }
+        }
+        p.accept(";")
+        return &pacAssign{name: name, value: value}, nil
+    }
+
+    if tok := p.peek(); tok.kind == pacTokIdent && !pacReservedWords[tok.text] && p.tokens[p.pos+1].text == "=" {
+        p.pos += 2
+        value, err := p.parseExpr()
+        if err != nil {
+            return nil, err
+        }
+        p.accept(";")
+        return &pacAssign{name: tok.text, value: value}, nil
+    }
+
+    expr, err := p.parseExpr()
+    if err != nil {
+        return nil, err
+    }
+    p.accept(";")
+    return &pacExprStmt{expr: expr}, nil
+}
+
+// parseBranch parses the body of an if/else, which may be a single statement
+func (p *pacParser) parseBranch() (pacStmt, error) {
+    stmt, err := p.parseStmt()
+    if err != nil {
+        return nil, err
+    }
+    if stmt == nil {
+        return pacBlock(nil), nil
+    }
+    return stmt, nil
+}
+
+// Operator precedence, lowest first
+var pacBinaryLevels = [][]string{
+    {"||"},
+    {"&&"},
+    {"===", "!==", "==", "!="},
+    {"<=", ">=", "<", ">"},
+    {"+"},
+}
+
+func (p *pacParser) parseExpr() (pacExpr, error) {
+    if err := p.enter(); err != nil {
+        return nil, err
+    }
+    defer p.leave()
+
+    return p.parseBinary(0)
+}
+
+func (p *pacParser) parseBinary(level int) (pacExpr, error) {
+    if level == len(pacBinaryLevels) {
+        return p.parseUnary()
+    }
+
+    left, err := p.parseBinary(level + 1)
+    if err != nil {
+        return nil, err
+    }
+    for {
+        op := ""
+        for _, candidate := range pacBinaryLevels[level] {
+            if tok := p.peek(); tok.kind == pacTokPunct && tok.text == candidate {
+                op = candidate
+                break
+            }
+        }
+        if op == "" {
+            return left, nil
+        }
+        p.next()
+        right, err := p.parseBinary(level + 1)
+        if err != nil {
+            return nil, err
+        }
+        left = &pacBinary{op: op, left: left, right: right}
+    }
+}
+
+func (p *pacParser) parseUnary() (pacExpr, error) {
+    if p.accept("!") {
+        if err := p.enter(); err != nil {
+            return nil, err
+        }
+        defer p.leave()
+
+        operand, err := p.parseUnary()
+        if err != nil {
+            return nil, err
+        }
+        return &pacNot{operand: operand}, nil
+    }
+    return p.parsePrimary()
+}
+
+func (p *pacParser) parsePrimary() (pacExpr, error) {
+    tok := p.next()
+    switch tok.kind {
+    case pacTokString:
+        return &pacLiteral{value: tok.text}, nil
+    case pacTokNumber:
+        f, err := strconv.ParseFloat(tok.text, 64)
+        if err != nil {
+            return nil, fmt.Errorf("PAC parse error: invalid number %q", tok.text)
+        }
+        return &pacLiteral{value: f}, nil
+    case pacTokIdent:
+        switch tok.text {
+        case "true":
+            return &pacLiteral{value: true}, nil
+        case "false":
+            return &pacLiteral{value: false}, nil
+        case "null", "undefined":
+            return &pacLiteral{value: ""}, nil
+        }
+        if pacReservedWords[tok.text] {
+            return nil, fmt.Errorf("PAC parse error: %q is not supported", tok.text)
+        }
+        if !p.accept("(") {
+            return &pacIdent{name: tok.text}, nil
+        }
+        call := &pacCall{name: tok.text}
+        for !p.accept(")") {
+            arg, err := p.parseExpr()
+            if err != nil {
+                return nil, err
+            }
+            call.args = append(call.args, arg)
+            if !p.accept(",") && p.peek().text != ")" {
+                return nil, fmt.Errorf("PAC parse error: malformed arguments to %s", tok.text)
+            }
+        }
+        return call, nil
+    case pacTokPunct:
+        if tok.text == "(" {
+            expr, err := p.parseExpr()
+            if err != nil {
+                return nil, err
+            }
+            if err := p.expect(")"); err != nil {
+                return nil, err
+            }
+            return expr, nil
+        }
+    }
+    return nil, fmt.Errorf("PAC parse error: unexpected %q", tok.text)
+}
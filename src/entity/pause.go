@@ -0,0 +1,51 @@
+package entity
+
+import (
+    "fmt"
+    "net/http"
+)
+
+// PauseMonitor stops checking url, e.g. during planned maintenance. Unlike ArchiveMonitor the
+// monitor stays listed, reported as paused. Pausing an already paused monitor is a no-op.
+func (um *UptimeMonitor) PauseMonitor(url string) error {
+    url = monitorKey(url)
+
+    um.mu.Lock()
+    defer um.mu.Unlock()
+
+    monitor, exists := um.monitors[url]
+    if !exists {
+        return fmt.Errorf("URL %s is not being monitored", url)
+    }
+    um.stopLocked(url)
+    monitor.Paused = true
+    um.monitors[url] = monitor
+    return nil
+}
+
+// ResumeMonitor restarts checks of a paused monitor with its previous configuration
+func (um *UptimeMonitor) ResumeMonitor(url string) error {
+    url = monitorKey(url)
+
+    um.mu.Lock()
+    defer um.mu.Unlock()
+
+    monitor, exists := um.monitors[url]
+    if !exists {
+        return fmt.Errorf("URL %s is not being monitored", url)
+    }
+    if !monitor.Paused {
+        return nil
+    }
+    monitor.Paused = false
+    um.startLocked(monitor)
+    return nil
+}
+
+func (um *UptimeMonitor) HandlePauseMonitor(w http.ResponseWriter, r *http.Request) {
+    um.handleArchiveAction(w, r, um.PauseMonitor)
+}
+
+func (um *UptimeMonitor) HandleResumeMonitor(w http.ResponseWriter, r *http.Request) {
+    um.handleArchiveAction(w, r, um.ResumeMonitor)
+}
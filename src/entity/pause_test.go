@@ -0,0 +1,87 @@
+package entity
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+// waitForLoops waits until n monitor goroutines are running
+func waitForLoops(t *testing.T, um *UptimeMonitor, n int64) {
+    t.Helper()
+    deadline := time.Now().Add(2 * time.Second)
+    for atomic.LoadInt64(&um.activeLoops) != n {
+        if time.Now().After(deadline) {
+            t.Fatalf("%d monitor goroutines running, want %d", atomic.LoadInt64(&um.activeLoops), n)
+        }
+        time.Sleep(5 * time.Millisecond)
+    }
+}
+
+func TestPauseAndResume(t *testing.T) {
+    um := NewUptimeMonitor()
+    defer um.Close()
+
+    const url = "https://example.com/"
+    if err := um.AddMonitorConfig(Monitor{URL: url, Interval: time.Hour, ExpectedStatus: 204}); err != nil {
+        t.Fatal(err)
+    }
+    waitForLoops(t, um, 1)
+    um.mu.Lock()
+    um.appendLogLocked(LogEntry{URL: url, Success: true, Timestamp: time.Now()})
+    um.mu.Unlock()
+
+    if err := um.PauseMonitor(url); err != nil {
+        t.Fatal(err)
+    }
+    if err := um.PauseMonitor(url); err != nil {
+        t.Errorf("pausing again = %v, want a no-op", err)
+    }
+    waitForLoops(t, um, 0)
+    if status := um.GetStatus(url); status.State != StatePaused {
+        t.Errorf("state = %s, want paused", status.State)
+    }
+    if monitors := um.ListMonitors(); len(monitors) != 1 || !monitors[0].Paused {
+        t.Errorf("monitors = %+v, want the paused monitor listed", monitors)
+    }
+
+    if err := um.ResumeMonitor(url); err != nil {
+        t.Fatal(err)
+    }
+    waitForLoops(t, um, 1)
+    if status := um.GetStatus(url); status.State != StateUp {
+        t.Errorf("state after resuming = %s, want up", status.State)
+    }
+    if monitor := um.ListMonitors()[0]; monitor.Paused || monitor.ExpectedStatus != 204 {
+        t.Errorf("resumed monitor = %+v, want its previous configuration", monitor)
+    }
+    if err := um.ResumeMonitor(url); err != nil {
+        t.Errorf("resuming a running monitor = %v, want a no-op", err)
+    }
+    waitForLoops(t, um, 1)
+
+    if err := um.PauseMonitor("https://unknown.example.com/"); err == nil {
+        t.Error("expected an error pausing an unknown monitor")
+    }
+}
+
+func TestHandlePauseMonitor(t *testing.T) {
+    um := NewUptimeMonitor()
+    defer um.Close()
+    if err := um.AddMonitor("https://example.com/", time.Hour); err != nil {
+        t.Fatal(err)
+    }
+
+    rec := httptest.NewRecorder()
+    um.HandlePauseMonitor(rec, httptest.NewRequest(http.MethodPost, "/monitor/pause?url=https://example.com/", nil))
+    if rec.Code != http.StatusOK || um.GetStatus("https://example.com/").State != StatePaused {
+        t.Errorf("pause = %d, state %s", rec.Code, um.GetStatus("https://example.com/").State)
+    }
+    rec = httptest.NewRecorder()
+    um.HandleResumeMonitor(rec, httptest.NewRequest(http.MethodPost, "/monitor/resume?url=https://example.com/", nil))
+    if rec.Code != http.StatusOK || um.GetStatus("https://example.com/").State == StatePaused {
+        t.Errorf("resume = %d, state %s", rec.Code, um.GetStatus("https://example.com/").State)
+    }
+}
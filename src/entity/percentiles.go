@@ -0,0 +1,43 @@
+package entity
+
+import (
+    "sort"
+    "time"
+)
+
+// LatencyPercentiles are response time percentiles, in milliseconds, over a recent window
+type LatencyPercentiles struct {
+    Window string `json:"window"`
+    P50    int64  `json:"p50"`
+    P95    int64  `json:"p95"`
+    P99    int64  `json:"p99"`
+}
+
+// GetLatencyPercentiles returns the nearest-rank 50th, 95th and 99th percentile response times
+// of the URL's successful checks within window before now. Only retained logs are considered;
+// all three are zero when no successful check falls in the window.
+func (um *UptimeMonitor) GetLatencyPercentiles(url string, window time.Duration) (p50, p95, p99 int64) {
+    url = monitorKey(url)
+
+    um.mu.RLock()
+    since := um.now().Add(-window)
+    var times []int64
+    for _, log := range um.logs[url] {
+        if log.Success && !log.Timestamp.Before(since) {
+            times = append(times, log.ResponseTime)
+        }
+    }
+    um.mu.RUnlock()
+
+    if len(times) == 0 {
+        return 0, 0, 0
+    }
+    sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+    return percentile(times, 50), percentile(times, 95), percentile(times, 99)
+}
+
+// percentile picks the nearest-rank percentile p of sorted values
+func percentile(sorted []int64, p int) int64 {
+    rank := (p*len(sorted) + 99) / 100 // ceil(p/100 * n)
+    return sorted[max(rank, 1)-1]
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+    "testing"
+    "time"
+)
+
+func TestPercentile(t *testing.T) {
+    hundred := make([]int64, 100)
+    for i := range hundred {
+        hundred[i] = int64(i + 1)
+    }
+    tests := []struct {
+        sorted []int64
+        p      int
+        want   int64
+    }{
+        {hundred, 50, 50},
+        {hundred, 95, 95},
+        {hundred, 99, 99},
+        {[]int64{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}, 50, 50},
+        {[]int64{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}, 95, 100},
+        {[]int64{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}, 99, 100},
+        {[]int64{10, 20, 30}, 50, 20},
+        {[]int64{7}, 50, 7},
+        {[]int64{7}, 99, 7},
+        {[]int64{7, 9}, 0, 7},
+    }
+    for _, tt := range tests {
+        if got := percentile(tt.sorted, tt.p); got != tt.want {
+            t.Errorf("percentile(%d values, %d) = %d, want %d", len(tt.sorted), tt.p, got, tt.want)
+        }
+    }
+}
+
+func TestGetLatencyPercentiles(t *testing.T) {
+    const url = "https://example.com/"
+    now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+    um := NewUptimeMonitor(WithClock(func() time.Time { return now }))
+
+    um.mu.Lock()
+    // Outside the window, and failures inside it, must not count
+    um.appendLogLocked(LogEntry{URL: url, Timestamp: now.Add(-2 * time.Hour), Success: true, ResponseTime: 5000})
+    um.appendLogLocked(LogEntry{URL: url, Timestamp: now.Add(-time.Minute), ResponseTime: 9000})
+    for i := 1; i <= 20; i++ {
+        um.appendLogLocked(LogEntry{URL: url, Timestamp: now.Add(-time.Duration(i) * time.Minute), Success: true, ResponseTime: int64(i * 10)})
+    }
+    um.mu.Unlock()
+
+    p50, p95, p99 := um.GetLatencyPercentiles(url, time.Hour)
+    if p50 != 100 || p95 != 190 || p99 != 200 {
+        t.Errorf("percentiles = %d/%d/%d, want 100/190/200", p50, p95, p99)
+    }
+
+    if p50, p95, p99 := um.GetLatencyPercentiles(url, time.Second); p50 != 0 || p95 != 0 || p99 != 0 {
+        t.Errorf("empty window percentiles = %d/%d/%d, want zeros", p50, p95, p99)
+    }
+    if p50, p95, p99 := um.GetLatencyPercentiles("https://unknown.example.com/", time.Hour); p50 != 0 || p95 != 0 || p99 != 0 {
+        t.Errorf("unknown URL percentiles = %d/%d/%d, want zeros", p50, p95, p99)
+    }
+}
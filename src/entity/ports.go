@@ -0,0 +1,104 @@
+package entity
+
+import (
+    "context"
+    "fmt"
+    "net"
+    "net/url"
+    "strconv"
+    "strings"
+    "sync"
+)
+
+// PortResult is the outcome of checking one port of a multi-port monitor
+type PortResult struct {
+    Port         int    `json:"port"`
+    Success      bool   `json:"success"`
+    StatusCode   int    `json:"statusCode,omitempty"`
+    ResponseTime int64  `json:"responseTime"` // in milliseconds
+    Error        string `json:"error,omitempty"`
+}
+
+func validatePorts(monitor Monitor) error {
+    if len(monitor.Ports) == 0 {
+        return nil
+    }
+    if monitor.Type != MonitorTypeHTTP {
+        return fmt.Errorf("ports are only supported by http monitors")
+    }
+    if _, err := url.Parse(monitor.URL); err != nil {
+        return err
+    }
+    seen := make(map[int]bool)
+    for _, port := range monitor.Ports {
+        if port < 1 || port > 65535 {
+            return fmt.Errorf("port %d is out of range", port)
+        }
+        if seen[port] {
+            return fmt.Errorf("port %d is listed twice", port)
+        }
+        seen[port] = true
+    }
+    return nil
+}
+
+// withPort returns rawURL with its port replaced
+func withPort(rawURL string, port int) string {
+    u, err := url.Parse(rawURL)
+    if err != nil {
+        return rawURL
+    }
+    u.Host = net.JoinHostPort(u.Hostname(), strconv.Itoa(port))
+    return u.String()
+}
+
+// checkPorts checks every port of the monitor in parallel, each in its own check slot, and
+// aggregates the results into one entry for the monitor's URL, which succeeds only when every
+// port does
+func (um *UptimeMonitor) checkPorts(ctx context.Context, monitor Monitor) LogEntry {
+    results := make([]LogEntry, len(monitor.Ports))
+    var wg sync.WaitGroup
+    for i, port := range monitor.Ports {
+        wg.Add(1)
+        go func(i, port int) {
+            defer wg.Done()
+            single := monitor
+            single.URL = withPort(monitor.URL, port)
+            single.Ports = nil
+            results[i] = um.withCheckSlot(ctx, single, um.checkHTTP)
+        }(i, port)
+    }
+    wg.Wait()
+
+    entry := LogEntry{Timestamp: um.now(), URL: monitor.URL, Success: true}
+    var down []string
+    for i, result := range results {
+        entry.Ports = append(entry.Ports, PortResult{
+            Port:         monitor.Ports[i],
+            Success:      result.Success,
+            StatusCode:   result.StatusCode,
+            ResponseTime: result.ResponseTime,
+            Error:        result.Error,
+        })
+        entry.BytesSent += result.BytesSent
+        entry.BytesReceived += result.BytesReceived
+        if result.ResponseTime > entry.ResponseTime {
+            entry.ResponseTime = result.ResponseTime
+        }
+        if !result.Success {
+            if entry.Success {
+                // The first failing port represents the check
+                entry.StatusCode = result.StatusCode
+                entry.ErrorClass = result.ErrorClass
+            }
+            entry.Success = false
+            down = append(down, strconv.Itoa(monitor.Ports[i]))
+        }
+    }
+    if len(down) > 0 {
+        entry.Error = fmt.Sprintf("%d of %d ports down: %s", len(down), len(monitor.Ports), strings.Join(down, ", "))
+    } else {
+        entry.StatusCode = results[0].StatusCode
+    }
+    return entry
+}
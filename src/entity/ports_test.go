@@ -0,0 +1,78 @@
+package entity
+
+import (
+    "context"
+    "fmt"
+    "net"
+    "net/http"
+    "net/http/httptest"
+    "strconv"
+    "testing"
+    "time"
+)
+
+func TestWithPort(t *testing.T) {
+    tests := map[string]string{
+        "https://example.com/health?x=1": "https://example.com:8443/health?x=1",
+        "http://example.com:8080/":       "http://example.com:8443/",
+        "http://[::1]:8080/":             "http://[::1]:8443/",
+    }
+    for raw, want := range tests {
+        if got := withPort(raw, 8443); got != want {
+            t.Errorf("withPort(%q) = %q, want %q", raw, got, want)
+        }
+    }
+}
+
+// serverPort returns the port an httptest server listens on
+func serverPort(t *testing.T, server *httptest.Server) int {
+    t.Helper()
+    _, port, err := net.SplitHostPort(server.Listener.Addr().String())
+    if err != nil {
+        t.Fatal(err)
+    }
+    n, _ := strconv.Atoi(port)
+    return n
+}
+
+func TestCheckPorts(t *testing.T) {
+    ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+    defer ok.Close()
+    failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(http.StatusServiceUnavailable)
+    }))
+    defer failing.Close()
+    um := NewUptimeMonitor()
+
+    okPort, failingPort := serverPort(t, ok), serverPort(t, failing)
+    entry := um.attemptCheck(context.Background(), Monitor{URL: "http://127.0.0.1/", Type: MonitorTypeHTTP, Ports: []int{okPort}})
+    if !entry.Success || entry.StatusCode != http.StatusOK || entry.URL != "http://127.0.0.1/" {
+        t.Errorf("single port check = %+v", entry)
+    }
+
+    entry = um.attemptCheck(context.Background(), Monitor{URL: "http://127.0.0.1/", Type: MonitorTypeHTTP, Ports: []int{okPort, failingPort}})
+    if entry.Success || entry.StatusCode != http.StatusServiceUnavailable {
+        t.Fatalf("check = %+v, want a failure with the failing port's status", entry)
+    }
+    if want := fmt.Sprintf("1 of 2 ports down: %d", failingPort); entry.Error != want {
+        t.Errorf("error = %q, want %q", entry.Error, want)
+    }
+    if len(entry.Ports) != 2 || entry.Ports[0].Port != okPort || !entry.Ports[0].Success || entry.Ports[1].Success {
+        t.Errorf("port results = %+v", entry.Ports)
+    }
+}
+
+func TestValidatePorts(t *testing.T) {
+    um := NewUptimeMonitor()
+    defer um.Close()
+
+    for _, ports := range [][]int{{0}, {65536}, {443, 443}} {
+        if err := um.AddMonitorConfig(Monitor{URL: "https://example.com", Interval: time.Hour, Ports: ports}); err == nil {
+            t.Errorf("added a monitor with ports %v, want an error", ports)
+            um.RemoveMonitor("https://example.com")
+        }
+    }
+    if err := um.AddMonitorConfig(Monitor{URL: "https://example.com", Interval: time.Hour, Ports: []int{443, 8443}}); err != nil {
+        t.Error(err)
+    }
+}
@@ -0,0 +1,69 @@
+package entity
+
+import (
+    "fmt"
+    "io"
+    "net/http"
+    "strconv"
+    "strings"
+)
+
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// HandlePrometheusMetrics serves the metrics in the Prometheus text exposition format
+func (um *UptimeMonitor) HandlePrometheusMetrics(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+    writePrometheusMetrics(w, um.CollectMetrics())
+}
+
+func writePrometheusMetrics(w io.Writer, metrics []URLMetrics) {
+    // A URL has no up sample until its first check, rather than reporting it as down
+    checked := make([]URLMetrics, 0, len(metrics))
+    for _, m := range metrics {
+        if m.Up != nil {
+            checked = append(checked, m)
+        }
+    }
+    writePrometheusFamily(w, "urlmonitor_up", "gauge", "Whether the latest check succeeded (1) or failed (0).", checked,
+        func(m URLMetrics) int64 { return int64(*m.Up) })
+    writePrometheusFamily(w, "urlmonitor_checks_total", "counter", "Checks performed.", metrics,
+        func(m URLMetrics) int64 { return m.ChecksTotal })
+    writePrometheusFamily(w, "urlmonitor_failures_total", "counter", "Checks that failed.", metrics,
+        func(m URLMetrics) int64 { return m.FailuresTotal })
+    writePrometheusFamily(w, "urlmonitor_incidents_total", "counter", "Downtimes opened.", metrics,
+        func(m URLMetrics) int64 { return m.IncidentsTotal })
+    writePrometheusFamily(w, "urlmonitor_last_response_time_ms", "gauge", "Response time of the latest check in milliseconds.", metrics,
+        func(m URLMetrics) int64 { return m.LastResponseTime })
+
+    fmt.Fprintln(w, "# HELP urlmonitor_response_time_ms Check response time in milliseconds.")
+    fmt.Fprintln(w, "# TYPE urlmonitor_response_time_ms histogram")
+    for _, m := range metrics {
+        label := prometheusLabelEscaper.Replace(m.URL)
+        for _, bucket := range m.Latency.Buckets {
+            le := strconv.FormatFloat(bucket.UpperBound, 'f', -1, 64)
+            fmt.Fprintf(w, "urlmonitor_response_time_ms_bucket{url=\"%s\",le=\"%s\"} %d\n", label, le, bucket.Count)
+        }
+        fmt.Fprintf(w, "urlmonitor_response_time_ms_bucket{url=\"%s\",le=\"+Inf\"} %d\n", label, m.Latency.Count)
+        fmt.Fprintf(w, "urlmonitor_response_time_ms_sum{url=\"%s\"} %d\n", label, m.Latency.Sum)
+        fmt.Fprintf(w, "urlmonitor_response_time_ms_count{url=\"%s\"} %d\n", label, m.Latency.Count)
+    }
+
+    writePrometheusFamily(w, "urlmonitor_bytes_sent_total", "counter", "Approximate bytes sent by checks.", metrics,
+        func(m URLMetrics) int64 { return m.BytesSent })
+    writePrometheusFamily(w, "urlmonitor_bytes_received_total", "counter", "Approximate bytes received by checks.", metrics,
+        func(m URLMetrics) int64 { return m.BytesReceived })
+}
+
+// writePrometheusFamily writes one sample per URL of a metric with a single value
+func writePrometheusFamily(w io.Writer, name, kind, help string, metrics []URLMetrics, value func(URLMetrics) int64) {
+    fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+    fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+    for _, m := range metrics {
+        fmt.Fprintf(w, "%s{url=\"%s\"} %d\n", name, prometheusLabelEscaper.Replace(m.URL), value(m))
+    }
+}
@@ -0,0 +1,52 @@
+package entity
+
+import (
+    "bytes"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestHandlePrometheusMetrics(t *testing.T) {
+    um := NewUptimeMonitor(WithLatencyBuckets([]float64{100, 1000}))
+    const url = "https://example.com/"
+    um.monitors[url] = Monitor{URL: url}
+    now := time.Now()
+    um.recordCheck(LogEntry{URL: url, Success: false, ResponseTime: 2000, BytesReceived: 10, Timestamp: now})
+    um.recordCheck(LogEntry{URL: url, Success: true, ResponseTime: 80, BytesReceived: 20, Timestamp: now.Add(time.Minute)})
+
+    rec := httptest.NewRecorder()
+    um.HandlePrometheusMetrics(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+    if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+        t.Errorf("Content-Type = %q", rec.Header().Get("Content-Type"))
+    }
+    text := rec.Body.String()
+    for _, want := range []string{
+        "# TYPE urlmonitor_up gauge",
+        `urlmonitor_up{url="https://example.com/"} 1`,
+        `urlmonitor_checks_total{url="https://example.com/"} 2`,
+        `urlmonitor_failures_total{url="https://example.com/"} 1`,
+        `urlmonitor_incidents_total{url="https://example.com/"} 1`,
+        `urlmonitor_last_response_time_ms{url="https://example.com/"} 80`,
+        "# TYPE urlmonitor_response_time_ms histogram",
+        `urlmonitor_response_time_ms_bucket{url="https://example.com/",le="100"} 1`,
+        `urlmonitor_response_time_ms_bucket{url="https://example.com/",le="1000"} 1`,
+        `urlmonitor_response_time_ms_bucket{url="https://example.com/",le="+Inf"} 2`,
+        `urlmonitor_response_time_ms_sum{url="https://example.com/"} 2080`,
+        `urlmonitor_bytes_received_total{url="https://example.com/"} 30`,
+    } {
+        if !strings.Contains(text, want+"\n") {
+            t.Errorf("metrics do not contain %s", want)
+        }
+    }
+}
+
+func TestPrometheusLabelEscaping(t *testing.T) {
+    var out bytes.Buffer
+    writePrometheusMetrics(&out, []URLMetrics{{URL: "https://example.com/?q=\"a\\b\"\n", ChecksTotal: 1}})
+    if want := `urlmonitor_checks_total{url="https://example.com/?q=\"a\\b\"\n"} 1`; !strings.Contains(out.String(), want) {
+        t.Errorf("metrics do not contain %s:\n%s", want, out.String())
+    }
+}
@@ -0,0 +1,127 @@
+package entity
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "log/slog"
+    "net/http"
+    "time"
+)
+
+const readinessPollInterval = 500 * time.Millisecond
+
+// ReadinessCheck reports whether a dependency, e.g. the result store, is available
+type ReadinessCheck func(ctx context.Context) error
+
+// WithReadinessGate holds back every check until ready succeeds. It is polled until it does
+// or timeout elapses; after a timeout monitoring starts anyway so outages are still detected.
+func WithReadinessGate(ready ReadinessCheck, timeout time.Duration) Option {
+    return func(um *UptimeMonitor) {
+        um.readyCheck = ready
+        um.readyTimeout = timeout
+    }
+}
+
+// HTTPReadiness treats a dependency as ready once url answers with a 2xx status
+func HTTPReadiness(url string) ReadinessCheck {
+    return func(ctx context.Context) error {
+        req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+        if err != nil {
+            return err
+        }
+        resp, err := http.DefaultClient.Do(req)
+        if err != nil {
+            return err
+        }
+        resp.Body.Close()
+        if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+            return fmt.Errorf("%s answered %s", url, resp.Status)
+        }
+        return nil
+    }
+}
+
+// awaitReady polls the readiness check and opens the gate once it passes or times out
+func (um *UptimeMonitor) awaitReady() {
+    defer close(um.ready)
+
+    ctx := context.Background()
+    if um.readyTimeout > 0 {
+        var cancel context.CancelFunc
+        ctx, cancel = context.WithTimeout(ctx, um.readyTimeout)
+        defer cancel()
+    }
+
+    ticker := time.NewTicker(readinessPollInterval)
+    defer ticker.Stop()
+    for {
+        err := um.readyCheck(ctx)
+        if err == nil {
+            return
+        }
+        select {
+        case <-ctx.Done():
+            slog.Warn("Dependencies not ready, starting checks anyway", "timeout", um.readyTimeout.String(), "error", err)
+            return
+        case <-ticker.C:
+        }
+    }
+}
+
+// IsReady reports whether checks have started
+func (um *UptimeMonitor) IsReady() bool {
+    select {
+    case <-um.ready:
+        return true
+    default:
+        return false
+    }
+}
+
+// Health is the body of the service's own health endpoint
+type Health struct {
+    Status         string `json:"status"`         // "ok", or "starting" while waiting for dependencies
+    ActiveMonitors int    `json:"activeMonitors"` // monitors being checked, excluding paused ones
+    UptimeSeconds  int64  `json:"uptimeSeconds"`  // since the monitor was created
+}
+
+// GetHealth reports the service's own health, taking only the read lock
+func (um *UptimeMonitor) GetHealth() Health {
+    health := Health{Status: "ok", UptimeSeconds: int64(time.Since(um.started) / time.Second)}
+    if !um.IsReady() {
+        health.Status = "starting"
+    }
+
+    um.mu.RLock()
+    health.ActiveMonitors = len(um.stopChannels)
+    um.mu.RUnlock()
+    return health
+}
+
+// HandleHealthz answers 200 while the process is alive, including while it waits for
+// dependencies; the body's status tells both apart. Use /readyz to gate traffic.
+func (um *UptimeMonitor) HandleHealthz(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(um.GetHealth())
+}
+
+// HandleReadyz answers 200 once checks have started and 503 while waiting for dependencies
+func (um *UptimeMonitor) HandleReadyz(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    health := um.GetHealth()
+    w.Header().Set("Content-Type", "application/json")
+    if health.Status != "ok" {
+        w.WriteHeader(http.StatusServiceUnavailable)
+    }
+    json.NewEncoder(w).Encode(health)
+}
@@ -0,0 +1,52 @@
+package entity
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+func TestHealthzAndReadyzWhileStarting(t *testing.T) {
+    var available atomic.Bool
+    um := NewUptimeMonitor(WithReadinessGate(func(ctx context.Context) error {
+        if !available.Load() {
+            return errors.New("result store unavailable")
+        }
+        return nil
+    }, time.Minute))
+
+    probe := func(handler http.HandlerFunc) (int, Health) {
+        rec := httptest.NewRecorder()
+        handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+        var health Health
+        if err := json.NewDecoder(rec.Body).Decode(&health); err != nil {
+            t.Fatal(err)
+        }
+        return rec.Code, health
+    }
+
+    if code, health := probe(um.HandleHealthz); code != http.StatusOK || health.Status != "starting" {
+        t.Errorf("healthz while starting = %d %q, want 200 starting", code, health.Status)
+    }
+    if code, _ := probe(um.HandleReadyz); code != http.StatusServiceUnavailable {
+        t.Errorf("readyz while starting = %d, want 503", code)
+    }
+
+    available.Store(true)
+    select {
+    case <-um.ready:
+    case <-time.After(5 * time.Second):
+        t.Fatal("checks did not start once dependencies were ready")
+    }
+    if code, health := probe(um.HandleHealthz); code != http.StatusOK || health.Status != "ok" {
+        t.Errorf("healthz when ready = %d %q, want 200 ok", code, health.Status)
+    }
+    if code, _ := probe(um.HandleReadyz); code != http.StatusOK {
+        t.Errorf("readyz when ready = %d, want 200", code)
+    }
+}
@@ -0,0 +1,121 @@
+package entity
+
+import (
+    "crypto/tls"
+    "encoding/json"
+    "net/http"
+)
+
+// ReplayCriteria is a candidate set of success rules evaluated against stored logs.
+// Only properties recorded on log entries can be replayed; response bodies are not kept.
+type ReplayCriteria struct {
+    StatusCodes     []int  `json:"statusCodes,omitempty"`     // accepted codes; any 2xx when empty
+    MaxResponseTime int64  `json:"maxResponseTime,omitempty"` // in milliseconds; zero disables
+    MinTLSVersion   string `json:"minTLSVersion,omitempty"`   // e.g. "1.2"; only applied to TLS checks
+}
+
+// ReplayResult reports how the stored checks of a URL would have fared under new criteria
+type ReplayResult struct {
+    URL          string `json:"url"`
+    Evaluated    int    `json:"evaluated"`
+    Passed       int    `json:"passed"`
+    Failed       int    `json:"failed"`
+    NewlyFailing int    `json:"newlyFailing"` // passed originally, fail under the criteria
+    NewlyPassing int    `json:"newlyPassing"` // failed originally, pass under the criteria
+}
+
+// ReplayLogs re-evaluates the retained logs of url against criteria. Failures the criteria
+// cannot judge, such as connection errors or body assertions, stay failures.
+func (um *UptimeMonitor) ReplayLogs(url string, criteria ReplayCriteria) (ReplayResult, error) {
+    url = monitorKey(url)
+    var minTLS uint16
+    if criteria.MinTLSVersion != "" {
+        var err error
+        if minTLS, err = parseTLSVersion(criteria.MinTLSVersion); err != nil {
+            return ReplayResult{}, err
+        }
+    }
+
+    result := ReplayResult{URL: url}
+    for _, entry := range um.GetLogs(url) {
+        passed := criteria.passes(entry, minTLS)
+        result.Evaluated++
+        if passed {
+            result.Passed++
+            if !entry.Success {
+                result.NewlyPassing++
+            }
+        } else {
+            result.Failed++
+            if entry.Success {
+                result.NewlyFailing++
+            }
+        }
+    }
+    return result, nil
+}
+
+func (c ReplayCriteria) passes(entry LogEntry, minTLS uint16) bool {
+    statusOK := entry.StatusCode >= 200 && entry.StatusCode < 300
+    if !entry.Success && (entry.StatusCode == 0 || statusOK) {
+        // No response at all, or a 2xx that failed a check the criteria do not cover
+        return false
+    }
+
+    if len(c.StatusCodes) > 0 {
+        statusOK = false
+        for _, code := range c.StatusCodes {
+            if entry.StatusCode == code {
+                statusOK = true
+                break
+            }
+        }
+    }
+    if !statusOK {
+        return false
+    }
+    if c.MaxResponseTime > 0 && entry.ResponseTime > c.MaxResponseTime {
+        return false
+    }
+    if minTLS != 0 && entry.TLSVersion != "" && recordedTLSVersion(entry.TLSVersion) < minTLS {
+        return false
+    }
+    return true
+}
+
+// recordedTLSVersion maps a version name stored by recordTLS back to its tls constant
+func recordedTLSVersion(name string) uint16 {
+    for _, version := range tlsVersions {
+        if tls.VersionName(version) == name {
+            return version
+        }
+    }
+    return 0
+}
+
+// HandleReplayLogs evaluates the criteria in the request body against the URL's stored logs
+func (um *UptimeMonitor) HandleReplayLogs(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodPost {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    url := r.URL.Query().Get("url")
+    if url == "" {
+        writeError(w, "URL parameter is required", http.StatusBadRequest)
+        return
+    }
+
+    var criteria ReplayCriteria
+    if err := json.NewDecoder(r.Body).Decode(&criteria); err != nil {
+        writeError(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+
+    result, err := um.ReplayLogs(url, criteria)
+    if err != nil {
+        writeError(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,67 @@
+package entity
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestReplayLogs(t *testing.T) {
+    um := NewUptimeMonitor()
+    const url = "https://example.com/"
+    um.mu.Lock()
+    for _, entry := range []LogEntry{
+        {StatusCode: 200, ResponseTime: 100, Success: true, TLSVersion: "TLS 1.3"},
+        {StatusCode: 200, ResponseTime: 900, Success: true, TLSVersion: "TLS 1.2"},
+        {StatusCode: 204, ResponseTime: 50, Success: true},
+        {StatusCode: 404, ResponseTime: 30, Success: false},
+        {StatusCode: 0, Success: false, Error: "connection refused"},
+        {StatusCode: 200, ResponseTime: 80, Success: false, Error: "expected body substring not found"},
+    } {
+        entry.URL = url
+        entry.Timestamp = time.Now()
+        um.appendLogLocked(entry)
+    }
+    um.mu.Unlock()
+
+    tests := []struct {
+        criteria ReplayCriteria
+        want     ReplayResult
+    }{
+        {ReplayCriteria{}, ReplayResult{URL: url, Evaluated: 6, Passed: 3, Failed: 3}},
+        {ReplayCriteria{MaxResponseTime: 500}, ReplayResult{URL: url, Evaluated: 6, Passed: 2, Failed: 4, NewlyFailing: 1}},
+        {ReplayCriteria{StatusCodes: []int{200, 404}}, ReplayResult{URL: url, Evaluated: 6, Passed: 3, Failed: 3, NewlyFailing: 1, NewlyPassing: 1}},
+        {ReplayCriteria{MinTLSVersion: "1.3"}, ReplayResult{URL: url, Evaluated: 6, Passed: 2, Failed: 4, NewlyFailing: 1}},
+    }
+    for _, test := range tests {
+        result, err := um.ReplayLogs(url, test.criteria)
+        if err != nil || result != test.want {
+            t.Errorf("ReplayLogs(%+v) = %+v, %v, want %+v", test.criteria, result, err, test.want)
+        }
+    }
+
+    if _, err := um.ReplayLogs(url, ReplayCriteria{MinTLSVersion: "2.0"}); err == nil {
+        t.Error("expected an error for an unknown TLS version")
+    }
+}
+
+func TestHandleReplayLogs(t *testing.T) {
+    um := NewUptimeMonitor()
+    for _, test := range []struct {
+        target, body string
+        status       int
+    }{
+        {"/monitor/replay?url=https://example.com/", `{"statusCodes": [200]}`, http.StatusOK},
+        {"/monitor/replay", `{}`, http.StatusBadRequest},
+        {"/monitor/replay?url=https://example.com/", `{"statusCodes": "200"}`, http.StatusBadRequest},
+        {"/monitor/replay?url=https://example.com/", `{"minTLSVersion": "0.9"}`, http.StatusBadRequest},
+    } {
+        rec := httptest.NewRecorder()
+        um.HandleReplayLogs(rec, httptest.NewRequest(http.MethodPost, test.target, strings.NewReader(test.body)))
+        if rec.Code != test.status {
+            t.Errorf("POST %s %s = %d, want %d", test.target, test.body, rec.Code, test.status)
+        }
+    }
+}
@@ -0,0 +1,208 @@
+package entity
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net"
+    "net/http"
+    "net/url"
+    "sort"
+    "strings"
+    "sync"
+    "time"
+)
+
+// NameserverResolver queries a specific DNS server, e.g. "8.8.8.8:53", instead of the system resolver
+type NameserverResolver struct {
+    Address string
+}
+
+func (r NameserverResolver) LookupRecords(ctx context.Context, host string) ([]DNSRecord, error) {
+    resolver := &net.Resolver{
+        PreferGo: true,
+        Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+            var dialer net.Dialer
+            return dialer.DialContext(ctx, network, r.Address)
+        },
+    }
+
+    ips, err := resolver.LookupIP(ctx, "ip", host)
+    if err != nil {
+        return nil, err
+    }
+    records := make([]DNSRecord, 0, len(ips))
+    for _, ip := range ips {
+        recordType := "AAAA"
+        if ip.To4() != nil {
+            recordType = "A"
+        }
+        records = append(records, DNSRecord{Type: recordType, Value: ip.String()})
+    }
+    return records, nil
+}
+
+// WithComparisonResolvers names the resolvers a URL is checked through by CompareResolvers
+func WithComparisonResolvers(resolvers map[string]DNSResolver) Option {
+    return func(um *UptimeMonitor) {
+        um.compareVia = resolvers
+    }
+}
+
+// ResolverResult is the outcome of checking a URL through one resolver
+type ResolverResult struct {
+    Resolver     string   `json:"resolver"`
+    IPs          []string `json:"ips"`
+    StatusCode   int      `json:"statusCode"`
+    ResponseTime int64    `json:"responseTime"` // in milliseconds
+    Success      bool     `json:"success"`
+    Error        string   `json:"error,omitempty"`
+    Differs      bool     `json:"differs"` // resolved IPs or status differ from the most common outcome
+}
+
+// ResolverComparison reports how a URL resolves and responds through several resolvers
+type ResolverComparison struct {
+    URL        string           `json:"url"`
+    Consistent bool             `json:"consistent"` // every resolver returned the same IPs and status
+    Results    []ResolverResult `json:"results"`
+}
+
+// CompareResolvers checks rawURL through every configured resolver at once, connecting to
+// the first address each one returns. These one-off checks are not recorded in the logs.
+func (um *UptimeMonitor) CompareResolvers(rawURL string) (ResolverComparison, error) {
+    target, err := url.Parse(rawURL)
+    if err != nil {
+        return ResolverComparison{}, err
+    }
+    if len(um.compareVia) == 0 {
+        return ResolverComparison{}, fmt.Errorf("no comparison resolvers configured")
+    }
+
+    names := make([]string, 0, len(um.compareVia))
+    for name := range um.compareVia {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+
+    comparison := ResolverComparison{URL: rawURL, Consistent: true, Results: make([]ResolverResult, len(names))}
+    var wg sync.WaitGroup
+    for i, name := range names {
+        wg.Add(1)
+        go func(i int, name string) {
+            defer wg.Done()
+            comparison.Results[i] = um.checkViaResolver(target, name, um.compareVia[name])
+        }(i, name)
+    }
+    wg.Wait()
+
+    // The most common combination of addresses and status is the baseline
+    outcomes := make(map[string]int)
+    for _, result := range comparison.Results {
+        outcomes[resolverOutcome(result)]++
+    }
+    baseline, best := "", -1
+    for outcome, count := range outcomes {
+        if count > best || count == best && outcome < baseline {
+            baseline, best = outcome, count
+        }
+    }
+    for i := range comparison.Results {
+        if resolverOutcome(comparison.Results[i]) != baseline {
+            comparison.Results[i].Differs = true
+            comparison.Consistent = false
+        }
+    }
+    return comparison, nil
+}
+
+func resolverOutcome(result ResolverResult) string {
+    return fmt.Sprintf("%s|%d", strings.Join(result.IPs, ","), result.StatusCode)
+}
+
+func (um *UptimeMonitor) checkViaResolver(target *url.URL, name string, resolver DNSResolver) ResolverResult {
+    result := ResolverResult{Resolver: name}
+
+    ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+    defer cancel()
+
+    start := time.Now()
+    records, err := resolver.LookupRecords(ctx, target.Hostname())
+    if err != nil {
+        result.Error = err.Error()
+        return result
+    }
+    for _, record := range records {
+        if record.Type == "A" || record.Type == "AAAA" {
+            result.IPs = append(result.IPs, record.Value)
+        }
+    }
+    sort.Strings(result.IPs)
+    if len(result.IPs) == 0 {
+        result.Error = fmt.Sprintf("%s returned no addresses", name)
+        return result
+    }
+
+    port := target.Port()
+    if port == "" {
+        port = "80"
+        if target.Scheme == "https" {
+            port = "443"
+        }
+    }
+    addr := net.JoinHostPort(result.IPs[0], port)
+
+    // Pin the connection to the resolved address; the URL keeps its hostname for Host and SNI
+    transport := http.DefaultTransport.(*http.Transport).Clone()
+    transport.Proxy = nil
+    transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+        var dialer net.Dialer
+        return dialer.DialContext(ctx, network, addr)
+    }
+    defer transport.CloseIdleConnections()
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+    if err != nil {
+        result.Error = err.Error()
+        return result
+    }
+    resp, err := (&http.Client{Transport: transport}).Do(req)
+    if err != nil {
+        result.ResponseTime = time.Since(start).Milliseconds()
+        result.Error = err.Error()
+        return result
+    }
+    io.Copy(io.Discard, io.LimitReader(resp.Body, maxBodyBytes))
+    resp.Body.Close()
+
+    result.ResponseTime = time.Since(start).Milliseconds()
+    result.StatusCode = resp.StatusCode
+    result.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+    return result
+}
+
+func (um *UptimeMonitor) HandleCompareResolvers(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodPost {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    var req struct {
+        URL string `json:"url"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeError(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    if req.URL == "" {
+        writeError(w, "url is required", http.StatusBadRequest)
+        return
+    }
+
+    comparison, err := um.CompareResolvers(req.URL)
+    if err != nil {
+        writeError(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    json.NewEncoder(w).Encode(comparison)
+}
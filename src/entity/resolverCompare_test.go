@@ -0,0 +1,58 @@
+package entity
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+func TestCompareResolvers(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if !strings.HasPrefix(r.Host, "app.test:") {
+            http.Error(w, "wrong host "+r.Host, http.StatusMisdirectedRequest)
+        }
+    }))
+    defer server.Close()
+
+    local, elsewhere, empty := &fakeResolver{}, &fakeResolver{}, &fakeResolver{}
+    local.set(DNSRecord{Type: "A", Value: "127.0.0.1"}, DNSRecord{Type: "TXT", Value: "ignored"})
+    elsewhere.set(DNSRecord{Type: "A", Value: "127.0.0.2"})
+    um := NewUptimeMonitor(WithComparisonResolvers(map[string]DNSResolver{
+        "isp":    local,
+        "public": local,
+        "stale":  elsewhere,
+        "broken": empty,
+    }))
+
+    target := strings.Replace(server.URL, "127.0.0.1", "app.test", 1) + "/"
+    comparison, err := um.CompareResolvers(target)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if comparison.Consistent || len(comparison.Results) != 4 {
+        t.Fatalf("comparison = %+v, want 4 inconsistent results", comparison)
+    }
+
+    results := make(map[string]ResolverResult)
+    for _, result := range comparison.Results {
+        results[result.Resolver] = result
+    }
+    for _, name := range []string{"isp", "public"} {
+        if result := results[name]; !result.Success || result.Differs || len(result.IPs) != 1 || result.StatusCode != http.StatusOK {
+            t.Errorf("%s = %+v, want the baseline success", name, result)
+        }
+    }
+    if result := results["stale"]; !result.Differs || result.IPs[0] != "127.0.0.2" {
+        t.Errorf("stale = %+v, want it to differ", result)
+    }
+    if result := results["broken"]; !result.Differs || result.Error == "" {
+        t.Errorf("broken = %+v, want an error", result)
+    }
+}
+
+func TestCompareResolversNotConfigured(t *testing.T) {
+    if _, err := NewUptimeMonitor().CompareResolvers("https://example.com/"); err == nil {
+        t.Error("expected an error without comparison resolvers")
+    }
+}
@@ -0,0 +1,126 @@
+package entity
+
+import (
+    "context"
+    "encoding/json"
+    "log/slog"
+)
+
+// SyslogWriter is the subset of *syslog.Writer used for check results and server logs
+type SyslogWriter interface {
+    Debug(msg string) error
+    Info(msg string) error
+    Warning(msg string) error
+    Err(msg string) error
+}
+
+// WithSyslog sends every check result to syslog, as info when it succeeded and as a warning
+// when it failed. Server logs are mirrored separately with NewSyslogHandler.
+func WithSyslog(writer SyslogWriter) Option {
+    return func(um *UptimeMonitor) {
+        um.syslog = writer
+    }
+}
+
+// NewSyslogHandler returns a handler writing every record it is given to w as JSON, with the
+// syslog priority matching the record's level: debug, info, warning or err.
+func NewSyslogHandler(w SyslogWriter) slog.Handler {
+    handler := func(write func(string) error) slog.Handler {
+        return slog.NewJSONHandler(priorityWriter(write), &slog.HandlerOptions{Level: slog.LevelDebug})
+    }
+    return syslogHandler{debug: handler(w.Debug), info: handler(w.Info), warn: handler(w.Warning), err: handler(w.Err)}
+}
+
+// priorityWriter writes each JSON record through one of a SyslogWriter's priority methods
+type priorityWriter func(string) error
+
+func (w priorityWriter) Write(p []byte) (int, error) {
+    if err := w(string(p)); err != nil {
+        return 0, err
+    }
+    return len(p), nil
+}
+
+// syslogHandler passes records to the JSON handler writing at the priority of their level
+type syslogHandler struct {
+    debug, info, warn, err slog.Handler
+}
+
+func (h syslogHandler) forLevel(level slog.Level) slog.Handler {
+    switch {
+    case level >= slog.LevelError:
+        return h.err
+    case level >= slog.LevelWarn:
+        return h.warn
+    case level >= slog.LevelInfo:
+        return h.info
+    }
+    return h.debug
+}
+
+func (h syslogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+    return true
+}
+
+func (h syslogHandler) Handle(ctx context.Context, record slog.Record) error {
+    return h.forLevel(record.Level).Handle(ctx, record)
+}
+
+func (h syslogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+    return syslogHandler{debug: h.debug.WithAttrs(attrs), info: h.info.WithAttrs(attrs), warn: h.warn.WithAttrs(attrs), err: h.err.WithAttrs(attrs)}
+}
+
+func (h syslogHandler) WithGroup(name string) slog.Handler {
+    return syslogHandler{debug: h.debug.WithGroup(name), info: h.info.WithGroup(name), warn: h.warn.WithGroup(name), err: h.err.WithGroup(name)}
+}
+
+// NewTeeHandler returns a handler passing records to primary and copying them to mirror, e.g.
+// a syslog handler. The primary handler decides which levels are enabled.
+func NewTeeHandler(primary, mirror slog.Handler) slog.Handler {
+    return teeHandler{primary: primary, mirror: mirror}
+}
+
+// teeHandler passes records to primary and copies them to mirror
+type teeHandler struct {
+    primary slog.Handler
+    mirror  slog.Handler
+}
+
+func (h teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+    return h.primary.Enabled(ctx, level)
+}
+
+func (h teeHandler) Handle(ctx context.Context, record slog.Record) error {
+    err := h.primary.Handle(ctx, record.Clone())
+    // A failing mirror must not lose the primary log line
+    h.mirror.Handle(ctx, record)
+    return err
+}
+
+func (h teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+    return teeHandler{primary: h.primary.WithAttrs(attrs), mirror: h.mirror.WithAttrs(attrs)}
+}
+
+func (h teeHandler) WithGroup(name string) slog.Handler {
+    return teeHandler{primary: h.primary.WithGroup(name), mirror: h.mirror.WithGroup(name)}
+}
+
+// emitResult forwards a completed check to the configured sinks. It must be called without um.mu held.
+func (um *UptimeMonitor) emitResult(entry LogEntry) {
+    if um.syslog == nil {
+        return
+    }
+
+    data, err := json.Marshal(entry)
+    if err != nil {
+        return
+    }
+    if entry.Success {
+        err = um.syslog.Info(string(data))
+    } else {
+        err = um.syslog.Warning(string(data))
+    }
+    if err != nil {
+        slog.Warn("Failed to write check result to syslog", "error", err)
+    }
+}
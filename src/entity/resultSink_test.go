@@ -0,0 +1,30 @@
+package entity
+
+import (
+    "bytes"
+    "log/slog"
+    "strings"
+    "testing"
+)
+
+func TestTeeHandlerCopiesRecords(t *testing.T) {
+    var primary, mirror bytes.Buffer
+    logger := slog.New(NewTeeHandler(
+        slog.NewJSONHandler(&primary, &slog.HandlerOptions{Level: slog.LevelInfo}),
+        slog.NewJSONHandler(&mirror, &slog.HandlerOptions{Level: slog.LevelDebug}),
+    ))
+
+    logger.With("component", "test").Info("Monitor added", "url", "https://example.com/")
+    logger.Debug("Check result")
+
+    for name, out := range map[string]string{"primary": primary.String(), "mirror": mirror.String()} {
+        for _, want := range []string{`"msg":"Monitor added"`, `"component":"test"`, `"url":"https://example.com/"`} {
+            if !strings.Contains(out, want) {
+                t.Errorf("%s output %q does not contain %s", name, out, want)
+            }
+        }
+        if strings.Contains(out, "Check result") {
+            t.Errorf("%s output contains a record below the primary handler's level", name)
+        }
+    }
+}
@@ -0,0 +1,102 @@
+package entity
+
+import (
+    "log/slog"
+    "runtime"
+    "time"
+)
+
+// Memory usage, as a fraction of RetentionPolicy.MemoryLimit, above which retention shrinks
+// and below which it grows again
+const (
+    highMemoryUsage = 0.9
+    lowMemoryUsage  = 0.6
+)
+
+// defaultRetentionInterval is how often memory is sampled when the policy sets no interval
+const defaultRetentionInterval = 10 * time.Second
+
+// RetentionPolicy adapts the number of log entries retained per URL to memory pressure
+// within [MinLogs, MaxLogs]. The global cap set with WithMaxLogs still applies.
+type RetentionPolicy struct {
+    MinLogs     int           // per URL
+    MaxLogs     int           // per URL
+    MemoryLimit uint64        // heap bytes considered full
+    Interval    time.Duration // how often memory is sampled; zero or negative means 10 seconds
+
+    // ReadMemory returns the current heap usage in bytes; nil reads the runtime's heap statistics
+    ReadMemory func() uint64
+}
+
+func heapAlloc() uint64 {
+    var mem runtime.MemStats
+    runtime.ReadMemStats(&mem)
+    return mem.HeapAlloc
+}
+
+// AdaptRetention periodically samples memory usage until stop is closed, halving the
+// per-URL retention cap under pressure and growing it by a quarter while memory is plentiful
+func (um *UptimeMonitor) AdaptRetention(policy RetentionPolicy, stop <-chan struct{}) {
+    if policy.ReadMemory == nil {
+        policy.ReadMemory = heapAlloc
+    }
+    if policy.MaxLogs < policy.MinLogs {
+        policy.MaxLogs = policy.MinLogs
+    }
+    if policy.Interval <= 0 {
+        policy.Interval = defaultRetentionInterval
+    }
+    go func() {
+        ticker := time.NewTicker(policy.Interval)
+        defer ticker.Stop()
+
+        for {
+            select {
+            case <-stop:
+                return
+            case <-ticker.C:
+                um.adjustRetention(policy, policy.ReadMemory())
+            }
+        }
+    }()
+}
+
+// adjustRetention applies one step of the policy for the given heap usage and returns the new per-URL cap
+func (um *UptimeMonitor) adjustRetention(policy RetentionPolicy, heap uint64) int {
+    um.mu.Lock()
+    defer um.mu.Unlock()
+
+    current := um.urlLogCap
+    if current <= 0 {
+        current = policy.MaxLogs
+    }
+
+    usage := float64(heap) / float64(policy.MemoryLimit)
+    next := current
+    switch {
+    case usage >= highMemoryUsage:
+        next = max(current/2, policy.MinLogs)
+    case usage < lowMemoryUsage:
+        next = min(current+max(current/4, 1), policy.MaxLogs)
+    }
+
+    if next != um.urlLogCap {
+        slog.Info("Log retention adjusted", "maxLogsPerUrl", next, "memoryUsagePercent", int(usage*100))
+        um.urlLogCap = next
+        trimmed := false
+        for url, logs := range um.logs {
+            if len(logs) > next {
+                um.trimURLLogsLocked(url, next)
+                trimmed = true
+            }
+        }
+        if trimmed {
+            // Copy after trimming so the evicted entries can be collected right away
+            um.compactLogOrderLocked()
+            for url, logs := range um.logs {
+                um.logs[url] = append([]LogEntry(nil), logs...)
+            }
+        }
+    }
+    return next
+}
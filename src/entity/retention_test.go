@@ -0,0 +1,109 @@
+package entity
+
+import (
+    "testing"
+    "time"
+)
+
+func TestAdaptiveRetentionCapsEachURL(t *testing.T) {
+    const busy, quiet = "https://busy.example.com/", "https://quiet.example.com/"
+    um := NewUptimeMonitor(WithMaxLogs(0))
+    start := time.Now()
+    checks := 0
+    record := func(url string, n int) {
+        um.mu.Lock()
+        defer um.mu.Unlock()
+        for i := 0; i < n; i++ {
+            um.retainLogLocked(LogEntry{URL: url, Timestamp: start.Add(time.Duration(checks) * time.Second)})
+            checks++
+        }
+    }
+    record(busy, 10)
+    record(quiet, 2)
+
+    policy := RetentionPolicy{MinLogs: 2, MaxLogs: 8, MemoryLimit: 100}
+    if got := um.adjustRetention(policy, 95); got != 4 {
+        t.Fatalf("cap under pressure = %d, want 4", got)
+    }
+    record(busy, 3)
+
+    um.mu.RLock()
+    defer um.mu.RUnlock()
+    if n := len(um.logs[busy]); n != 4 {
+        t.Errorf("busy URL keeps %d entries, want 4", n)
+    }
+    if n := len(um.logs[quiet]); n != 2 {
+        t.Errorf("quiet URL keeps %d entries, want both", n)
+    }
+    if n := um.logCount; n != 6 {
+        t.Errorf("global order holds %d entries, want 6", n)
+    }
+    all := um.allLogsLocked()
+    for i := 1; i < len(all); i++ {
+        if all[i].Timestamp.Before(all[i-1].Timestamp) {
+            t.Fatalf("logs out of order after trimming: %v before %v", all[i-1].Timestamp, all[i].Timestamp)
+        }
+    }
+    if last := all[len(all)-1]; last.URL != busy || !last.Timestamp.Equal(start.Add(14*time.Second)) {
+        t.Errorf("newest entry = %s at %v, want the latest busy check", last.URL, last.Timestamp)
+    }
+}
+
+func TestAdaptiveRetentionBounds(t *testing.T) {
+    um := NewUptimeMonitor()
+    policy := RetentionPolicy{MinLogs: 10, MaxLogs: 40, MemoryLimit: 100}
+    for _, step := range []struct {
+        heap uint64
+        want int
+    }{
+        {95, 20}, {95, 10}, {95, 10}, // halved down to MinLogs
+        {70, 10}, // between the thresholds nothing changes
+        {10, 12}, {10, 15}, {10, 18}, {10, 22}, {10, 27}, {10, 33}, {10, 40}, {10, 40},
+    } {
+        if got := um.adjustRetention(policy, step.heap); got != step.want {
+            t.Fatalf("heap %d: cap = %d, want %d", step.heap, got, step.want)
+        }
+    }
+}
+
+func TestAdaptRetentionDefaultsInterval(t *testing.T) {
+    um := NewUptimeMonitor()
+    stop := make(chan struct{})
+    defer close(stop)
+
+    // A zero interval would make the ticker panic
+    um.AdaptRetention(RetentionPolicy{MinLogs: 1, MaxLogs: 10, MemoryLimit: 100}, stop)
+    um.AdaptRetention(RetentionPolicy{MinLogs: 1, MaxLogs: 10, MemoryLimit: 100, Interval: -time.Second}, stop)
+}
+
+func TestURLLogCapCompactsGlobalOrder(t *testing.T) {
+    const busy, quiet = "https://busy.example.com/", "https://quiet.example.com/"
+    um := NewUptimeMonitor(WithMaxLogs(5))
+    start := time.Now()
+
+    um.mu.Lock()
+    defer um.mu.Unlock()
+    um.urlLogCap = 2
+    um.retainLogLocked(LogEntry{URL: quiet, Timestamp: start})
+    for i := 1; i <= 100; i++ {
+        um.retainLogLocked(LogEntry{URL: busy, Timestamp: start.Add(time.Duration(i) * time.Second)})
+        if len(um.logOrder) > 2*um.logCount {
+            t.Fatalf("global order holds %d refs for %d retained entries", len(um.logOrder), um.logCount)
+        }
+    }
+
+    all := um.allLogsLocked()
+    if len(all) != 3 || all[0].URL != quiet || !all[2].Timestamp.Equal(start.Add(100*time.Second)) {
+        t.Fatalf("logs = %+v, want the quiet check and the two latest busy checks", all)
+    }
+
+    // The global cap skips refs the URL's cap already evicted
+    um.urlLogCap = 0
+    for i := 101; i <= 104; i++ {
+        um.retainLogLocked(LogEntry{URL: busy, Timestamp: start.Add(time.Duration(i) * time.Second)})
+    }
+    all = um.allLogsLocked()
+    if len(all) != 5 || all[0].URL != busy || !all[0].Timestamp.Equal(start.Add(100*time.Second)) {
+        t.Errorf("logs = %+v, want the five latest busy checks", all)
+    }
+}
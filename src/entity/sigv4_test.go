@@ -0,0 +1,150 @@
+package entity
+
+import (
+    "context"
+    "encoding/hex"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+)
+
+// Credentials and scope shared by the AWS Signature Version 4 test suite
+var sigV4SuiteConfig = &SigV4Config{
+    AccessKeyID:     "AKIDEXAMPLE",
+    SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+    Region:          "us-east-1",
+    Service:         "service",
+}
+
+const sigV4SuiteDate = "20150830T123600Z"
+
+func TestSigV4SigningKey(t *testing.T) {
+    // Example from the AWS documentation on deriving a signing key
+    key := sigV4SigningKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+    want := "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d"
+    if got := hex.EncodeToString(key); got != want {
+        t.Errorf("signing key = %s, want %s", got, want)
+    }
+}
+
+func TestSigV4TestSuite(t *testing.T) {
+    tests := []struct {
+        name        string
+        method      string
+        target      string
+        contentType string
+        body        string
+        canonical   string
+        signature   string
+    }{
+        {
+            name:   "get-vanilla",
+            method: "GET",
+            target: "https://example.amazonaws.com/",
+            canonical: "GET\n/\n\nhost:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\nhost;x-amz-date\n" +
+                "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+            signature: "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+        },
+        {
+            name:   "get-vanilla-query-order-key-case",
+            method: "GET",
+            target: "https://example.amazonaws.com/?Param2=value2&Param1=value1",
+            canonical: "GET\n/\nParam1=value1&Param2=value2\nhost:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\nhost;x-amz-date\n" +
+                "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+            signature: "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+        },
+        {
+            name:   "post-vanilla",
+            method: "POST",
+            target: "https://example.amazonaws.com/",
+            canonical: "POST\n/\n\nhost:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\nhost;x-amz-date\n" +
+                "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+            signature: "5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b",
+        },
+        {
+            name:        "post-x-www-form-urlencoded",
+            method:      "POST",
+            target:      "https://example.amazonaws.com/",
+            contentType: "application/x-www-form-urlencoded",
+            body:        "Param1=value1",
+            canonical: "POST\n/\n\ncontent-type:application/x-www-form-urlencoded\nhost:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\n" +
+                "content-type;host;x-amz-date\n9095672bbd1f56dfc5b65f3e153adc8731a4a654192329106275f4c7b24d0b6e",
+            signature: "ff11897932ad3f4e8b18135d722051e5ac45fc38421b1da7b9d196a0fe09473a",
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            req := httptest.NewRequest(tt.method, tt.target, nil)
+            req.Header = http.Header{"X-Amz-Date": {sigV4SuiteDate}}
+            if tt.contentType != "" {
+                req.Header.Set("Content-Type", tt.contentType)
+            }
+
+            canonical, signedHeaders := sigV4CanonicalRequest(req, sha256Hex(tt.body))
+            if canonical != tt.canonical {
+                t.Fatalf("canonical request =\n%s\nwant\n%s", canonical, tt.canonical)
+            }
+
+            // The suite signs without X-Amz-Content-Sha256, so sign the canonical request directly
+            scope := "20150830/us-east-1/service/aws4_request"
+            stringToSign := strings.Join([]string{sigV4Algorithm, sigV4SuiteDate, scope, sha256Hex(canonical)}, "\n")
+            key := sigV4SigningKey(sigV4SuiteConfig.SecretAccessKey, "20150830", sigV4SuiteConfig.Region, sigV4SuiteConfig.Service)
+            if got := hex.EncodeToString(hmacSHA256(key, stringToSign)); got != tt.signature {
+                t.Errorf("signature = %s, want %s (signed headers %s)", got, tt.signature, signedHeaders)
+            }
+        })
+    }
+}
+
+func TestSignSigV4SetsHeaders(t *testing.T) {
+    now, _ := time.Parse(sigV4TimeFormat, sigV4SuiteDate)
+    req := httptest.NewRequest("POST", "https://example.amazonaws.com/upload", nil)
+    req.Header = http.Header{}
+    cfg := *sigV4SuiteConfig
+    cfg.SessionToken = "session"
+
+    signSigV4(req, &cfg, sigV4UnsignedPayload, now)
+
+    if got := req.Header.Get("X-Amz-Content-Sha256"); got != sigV4UnsignedPayload {
+        t.Errorf("X-Amz-Content-Sha256 = %q, want %q", got, sigV4UnsignedPayload)
+    }
+    if got := req.Header.Get("X-Amz-Security-Token"); got != "session" {
+        t.Errorf("X-Amz-Security-Token = %q, want the session token", got)
+    }
+    auth := req.Header.Get("Authorization")
+    wantPrefix := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+        "SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token, Signature="
+    if !strings.HasPrefix(auth, wantPrefix) {
+        t.Errorf("Authorization = %q, want prefix %q", auth, wantPrefix)
+    }
+}
+
+func TestCheckRequestSignsPayload(t *testing.T) {
+    um := NewUptimeMonitor()
+    tests := []struct {
+        name    string
+        monitor Monitor
+        want    string
+    }{
+        {"body", Monitor{URL: "https://example.com/", Body: "Param1=value1", SigV4: sigV4SuiteConfig}, sha256Hex("Param1=value1")},
+        {"empty", Monitor{URL: "https://example.com/", SigV4: sigV4SuiteConfig}, sha256Hex("")},
+        {"streamed upload", Monitor{URL: "https://example.com/", UploadBytes: 1 << 20, SigV4: sigV4SuiteConfig}, sigV4UnsignedPayload},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if err := validateUpload(tt.monitor); err != nil {
+                t.Fatalf("validateUpload: %v", err)
+            }
+            req, err := um.newCheckRequest(context.Background(), tt.monitor)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if got := req.Header.Get("X-Amz-Content-Sha256"); got != tt.want {
+                t.Errorf("X-Amz-Content-Sha256 = %q, want %q", got, tt.want)
+            }
+        })
+    }
+}
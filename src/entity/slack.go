@@ -0,0 +1,76 @@
+package entity
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "time"
+)
+
+// SlackAlerter posts events to a Slack incoming webhook as human-readable messages
+type SlackAlerter struct {
+    WebhookURL string
+    Client     *http.Client
+}
+
+// NewSlackAlerter returns an alerter posting to a Slack incoming webhook with a 10 second timeout
+func NewSlackAlerter(webhookURL string) *SlackAlerter {
+    return &SlackAlerter{WebhookURL: webhookURL, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// WithSlack posts down, up and other events to a Slack incoming webhook
+func WithSlack(webhookURL string) Option {
+    return func(um *UptimeMonitor) {
+        um.alerters = append(um.alerters, NewSlackAlerter(webhookURL))
+    }
+}
+
+// Name omits the webhook URL, which carries its secret in the path
+func (a *SlackAlerter) Name() string {
+    return "slack"
+}
+
+func (a *SlackAlerter) Notify(event DowntimeEvent) error {
+    payload, err := json.Marshal(map[string]string{"text": slackText(event)})
+    if err != nil {
+        return err
+    }
+    resp, err := a.Client.Post(a.WebhookURL, "application/json", bytes.NewReader(payload))
+    if err != nil {
+        return err
+    }
+    resp.Body.Close()
+    if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+        return fmt.Errorf("slack answered %s", resp.Status)
+    }
+    return nil
+}
+
+// slackText formats an event as a Slack message, preferring the monitor's rendered template
+func slackText(event DowntimeEvent) string {
+    if event.Message != "" {
+        return event.Message
+    }
+
+    reason := event.Error
+    if reason == "" && event.StatusCode != 0 {
+        reason = fmt.Sprintf("HTTP %d", event.StatusCode)
+    }
+    switch event.Type {
+    case EventDown:
+        if reason == "" {
+            return fmt.Sprintf(":red_circle: %s is down", event.URL)
+        }
+        return fmt.Sprintf(":red_circle: %s is down: %s", event.URL, reason)
+    case EventUp:
+        return fmt.Sprintf(":large_green_circle: %s is back up after %s", event.URL, event.Duration)
+    case EventRemoved:
+        return fmt.Sprintf(":wastebasket: %s was removed after failing for %s", event.URL, event.Duration)
+    default:
+        if reason == "" {
+            reason = event.Type
+        }
+        return fmt.Sprintf(":warning: %s: %s", event.URL, reason)
+    }
+}
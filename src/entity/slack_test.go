@@ -0,0 +1,57 @@
+package entity
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+)
+
+func TestSlackText(t *testing.T) {
+    const url = "https://example.com/"
+    tests := []struct {
+        event DowntimeEvent
+        want  string
+    }{
+        {DowntimeEvent{Type: EventDown, URL: url, StatusCode: 503}, ":red_circle: https://example.com/ is down: HTTP 503"},
+        {DowntimeEvent{Type: EventDown, URL: url, Error: "connection refused"}, ":red_circle: https://example.com/ is down: connection refused"},
+        {DowntimeEvent{Type: EventDown, URL: url}, ":red_circle: https://example.com/ is down"},
+        {DowntimeEvent{Type: EventUp, URL: url, Duration: "5m0s"}, ":large_green_circle: https://example.com/ is back up after 5m0s"},
+        {DowntimeEvent{Type: EventRemoved, URL: url, Duration: "24h0m0s"}, ":wastebasket: https://example.com/ was removed after failing for 24h0m0s"},
+        {DowntimeEvent{Type: EventCacheHitRateLow, URL: url, Error: "cache hit rate 10%"}, ":warning: https://example.com/: cache hit rate 10%"},
+        {DowntimeEvent{Type: EventDown, URL: url, Message: "see the runbook"}, "see the runbook"},
+    }
+    for _, test := range tests {
+        if got := slackText(test.event); got != test.want {
+            t.Errorf("slackText(%+v) = %q, want %q", test.event, got, test.want)
+        }
+    }
+}
+
+func TestSlackAlerter(t *testing.T) {
+    texts := make(chan string, 1)
+    status := http.StatusOK
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        var payload map[string]string
+        json.NewDecoder(r.Body).Decode(&payload)
+        texts <- payload["text"]
+        w.WriteHeader(status)
+    }))
+    defer server.Close()
+
+    alerter := NewSlackAlerter(server.URL + "/services/T000/B000/secret")
+    if err := alerter.Notify(DowntimeEvent{Type: EventDown, URL: "https://example.com/", StatusCode: 500}); err != nil {
+        t.Fatal(err)
+    }
+    if text := <-texts; text != ":red_circle: https://example.com/ is down: HTTP 500" {
+        t.Errorf("posted %q", text)
+    }
+    if alerter.Name() != "slack" {
+        t.Errorf("name = %q, want one without the webhook secret", alerter.Name())
+    }
+
+    status = http.StatusForbidden
+    if err := alerter.Notify(DowntimeEvent{Type: EventUp, URL: "https://example.com/"}); err == nil {
+        t.Error("expected an error for a rejected post")
+    }
+}
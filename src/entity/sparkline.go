@@ -0,0 +1,159 @@
+package entity
+
+import (
+    "encoding/json"
+    "net/http"
+    "strconv"
+    "time"
+)
+
+// Sparkline period states in addition to StateUp and StateDown
+const (
+    StateDegraded = "degraded" // some checks failed or a downtime covered part of the period
+    StateNoData   = "no-data"  // nothing was recorded during the period
+)
+
+const (
+    defaultSparklineBuckets = 30
+    maxSparklineBuckets     = 1000
+    defaultSparklineRange   = 24 * time.Hour
+)
+
+// Sparkline is a fixed-length series of period states for rendering a status bar
+type Sparkline struct {
+    URL    string    `json:"url"`
+    Since  time.Time `json:"since"`
+    Until  time.Time `json:"until"`
+    Period string    `json:"period"`
+    States []string  `json:"states"` // oldest period first
+}
+
+// GetSparkline splits [since, until) into n equal periods and classifies each one from the URL's
+// checks and downtimes. A period is down when every check in it failed or a downtime spans all of
+// it, degraded when only part of it was affected, up when it had only successful checks, and
+// no-data otherwise. Retried attempts are ignored, only the final attempt of a check counts.
+func (um *UptimeMonitor) GetSparkline(url string, since, until time.Time, n int) Sparkline {
+    url = monitorKey(url)
+
+    um.mu.RLock()
+    defer um.mu.RUnlock()
+
+    period := until.Sub(since) / time.Duration(n)
+    sparkline := Sparkline{URL: url, Since: since, Until: until, Period: period.String(), States: make([]string, n)}
+    if period <= 0 {
+        for i := range sparkline.States {
+            sparkline.States[i] = StateNoData
+        }
+        return sparkline
+    }
+
+    bucketOf := func(t time.Time) int {
+        if t.Before(since) || !t.Before(until) {
+            return -1
+        }
+        i := int(t.Sub(since) / period)
+        if i >= n {
+            i = n - 1
+        }
+        return i
+    }
+
+    succeeded := make([]int, n)
+    failed := make([]int, n)
+    for _, log := range um.logs[url] {
+        if log.Retried {
+            // A retried attempt is superseded by the check's final attempt
+            continue
+        }
+        if i := bucketOf(log.Timestamp); i >= 0 {
+            if log.Success {
+                succeeded[i]++
+            } else {
+                failed[i]++
+            }
+        }
+    }
+
+    // Time each period spent inside a downtime
+    down := make([]time.Duration, n)
+    now := um.now()
+    for _, downtime := range um.downtimes[url] {
+        end := downtime.EndTime
+        if end.IsZero() {
+            end = now
+        }
+        for i := range down {
+            start, stop := since.Add(time.Duration(i)*period), since.Add(time.Duration(i+1)*period)
+            if downtime.StartTime.After(start) {
+                start = downtime.StartTime
+            }
+            if end.Before(stop) {
+                stop = end
+            }
+            if stop.After(start) {
+                down[i] += stop.Sub(start)
+            }
+        }
+    }
+
+    for i := range sparkline.States {
+        switch {
+        case down[i] >= period || failed[i] > 0 && succeeded[i] == 0:
+            sparkline.States[i] = StateDown
+        case down[i] > 0 || failed[i] > 0:
+            sparkline.States[i] = StateDegraded
+        case succeeded[i] > 0:
+            sparkline.States[i] = StateUp
+        default:
+            sparkline.States[i] = StateNoData
+        }
+    }
+    return sparkline
+}
+
+// HandleGetSparkline serves /monitor/sparkline?url=...&since=...&until=...&buckets=N.
+// The range defaults to the last 24 hours and buckets to 30.
+func (um *UptimeMonitor) HandleGetSparkline(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    url := r.URL.Query().Get("url")
+    if url == "" {
+        writeError(w, "URL parameter is required", http.StatusBadRequest)
+        return
+    }
+
+    since, err := parseTimeParam(r, "since")
+    if err != nil {
+        writeError(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    until, err := parseTimeParam(r, "until")
+    if err != nil {
+        writeError(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    if until.IsZero() {
+        until = um.now()
+    }
+    if since.IsZero() {
+        since = until.Add(-defaultSparklineRange)
+    }
+    if !since.Before(until) {
+        writeError(w, "since must be before until", http.StatusBadRequest)
+        return
+    }
+
+    buckets := defaultSparklineBuckets
+    if value := r.URL.Query().Get("buckets"); value != "" {
+        buckets, err = strconv.Atoi(value)
+        if err != nil || buckets < 1 || buckets > maxSparklineBuckets {
+            writeError(w, "buckets must be between 1 and "+strconv.Itoa(maxSparklineBuckets), http.StatusBadRequest)
+            return
+        }
+    }
+
+    json.NewEncoder(w).Encode(um.GetSparkline(url, since, until, buckets))
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+    "slices"
+    "testing"
+    "time"
+)
+
+func TestSparklineIgnoresRetriedAttempts(t *testing.T) {
+    const url = "https://example.com/"
+    since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    um := NewUptimeMonitor(WithClock(func() time.Time { return since.Add(3 * time.Hour) }))
+    um.mu.Lock()
+    um.logs[url] = []LogEntry{
+        // A failed attempt that succeeded on retry is an up period
+        {URL: url, Timestamp: since.Add(10 * time.Minute), Retried: true},
+        {URL: url, Timestamp: since.Add(11 * time.Minute), Success: true},
+        // Retried and final attempts both failed: down, not degraded by the retry bookkeeping
+        {URL: url, Timestamp: since.Add(70 * time.Minute), Retried: true},
+        {URL: url, Timestamp: since.Add(71 * time.Minute)},
+        // Only a retried attempt was retained: nothing to show
+        {URL: url, Timestamp: since.Add(130 * time.Minute), Retried: true},
+    }
+    um.mu.Unlock()
+
+    sparkline := um.GetSparkline(url, since, since.Add(3*time.Hour), 3)
+    want := []string{StateUp, StateDown, StateNoData}
+    if !slices.Equal(sparkline.States, want) {
+        t.Errorf("states = %v, want %v", sparkline.States, want)
+    }
+}
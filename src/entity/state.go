@@ -0,0 +1,111 @@
+package entity
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io/fs"
+    "log/slog"
+    "os"
+    "path/filepath"
+    "sort"
+)
+
+// savedState is the on-disk form of a monitor's state
+type savedState struct {
+    Monitors  []MonitorConfig `json:"monitors"`
+    Archived  []MonitorConfig `json:"archived,omitempty"`
+    Logs      []LogEntry      `json:"logs"`
+    Downtimes []DowntimeEntry `json:"downtimes"`
+
+    // Notified holds the last down or up alert sent per URL, so a restart does not repeat it
+    Notified map[string]string `json:"notified,omitempty"`
+}
+
+// SaveState writes the monitors, logs and downtimes to path as JSON. The file is replaced
+// atomically, so a crash while saving leaves the previous state intact. Monitor credentials are
+// stored in plaintext, so the file is created readable by its owner only.
+func (um *UptimeMonitor) SaveState(path string) error {
+    um.mu.RLock()
+    state := savedState{Logs: um.allLogsLocked(), Notified: um.notified}
+    for _, downtimes := range um.downtimes {
+        state.Downtimes = append(state.Downtimes, downtimes...)
+    }
+    sort.SliceStable(state.Downtimes, func(i, j int) bool {
+        return state.Downtimes[i].StartTime.Before(state.Downtimes[j].StartTime)
+    })
+    for _, monitor := range um.monitors {
+        state.Monitors = append(state.Monitors, monitor.Config())
+    }
+    for _, monitor := range um.archived {
+        state.Archived = append(state.Archived, monitor.Config())
+    }
+    data, err := json.Marshal(state)
+    um.mu.RUnlock()
+    if err != nil {
+        return err
+    }
+
+    tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+    if err != nil {
+        return err
+    }
+    defer os.Remove(tmp.Name())
+    if _, err := tmp.Write(data); err != nil {
+        tmp.Close()
+        return err
+    }
+    if err := tmp.Close(); err != nil {
+        return err
+    }
+    return os.Rename(tmp.Name(), path)
+}
+
+// LoadState restores a monitor saved with SaveState and restarts checks of its monitors.
+// A missing file yields an empty monitor. Counters behind stats are rebuilt from the logs of
+// final attempts; retried attempts are restored to the log only, as they were when recorded.
+func LoadState(path string, opts ...Option) (*UptimeMonitor, error) {
+    um := NewUptimeMonitor(opts...)
+
+    data, err := os.ReadFile(path)
+    if errors.Is(err, fs.ErrNotExist) {
+        return um, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    var state savedState
+    if err := json.Unmarshal(data, &state); err != nil {
+        return nil, fmt.Errorf("parsing state file %s: %w", path, err)
+    }
+
+    um.mu.Lock()
+    defer um.mu.Unlock()
+
+    for _, entry := range state.Logs {
+        if entry.Retried {
+            um.retainLogLocked(entry)
+            continue
+        }
+        um.appendLogLocked(entry)
+    }
+    for _, downtime := range state.Downtimes {
+        um.appendDowntimeLocked(downtime)
+    }
+    for url, event := range state.Notified {
+        um.notified[url] = event
+    }
+    for _, config := range state.Archived {
+        monitor := config.ToMonitor()
+        um.archived[monitor.URL] = monitor
+    }
+    for _, config := range state.Monitors {
+        monitor := config.ToMonitor()
+        if err := normalizeMonitor(&monitor); err != nil {
+            slog.Warn("Skipping invalid saved monitor", "url", monitor.URL, "error", err)
+            continue
+        }
+        um.startLocked(monitor)
+    }
+    return um, nil
+}
@@ -0,0 +1,109 @@
+package entity
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "path/filepath"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+func TestRestartDoesNotRepeatDownAlert(t *testing.T) {
+    var healthy atomic.Bool
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if !healthy.Load() {
+            w.WriteHeader(http.StatusServiceUnavailable)
+        }
+    }))
+    defer server.Close()
+    path := filepath.Join(t.TempDir(), "state.json")
+
+    before := NewUptimeMonitor()
+    alerter := newRecordingAlerter()
+    before.AddAlerter(alerter)
+    if err := before.AddMonitor(server.URL, time.Hour); err != nil {
+        t.Fatal(err)
+    }
+    monitor := before.ListMonitors()[0]
+    before.checkURL(monitor)
+    alerter.expect(t, EventDown)
+    if err := before.SaveState(path); err != nil {
+        t.Fatal(err)
+    }
+    before.RemoveMonitor(monitor.URL)
+
+    // The restarted monitor finds the downtime still open and already notified
+    after, err := LoadState(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    restarted := newRecordingAlerter()
+    after.AddAlerter(restarted)
+    defer after.RemoveMonitor(monitor.URL)
+
+    after.checkURL(monitor)
+    restarted.expectNone(t)
+    if downtimes := after.GetDowntimes(monitor.URL); len(downtimes) != 1 || !downtimes[0].Ongoing {
+        t.Fatalf("downtimes after restart = %+v, want the single open downtime", downtimes)
+    }
+
+    healthy.Store(true)
+    after.checkURL(monitor)
+    restarted.expect(t, EventUp)
+}
+
+func TestRestartKeepsLastNotifiedWithoutDowntime(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "state.json")
+    const url = "http://example.com/"
+
+    before := NewUptimeMonitor()
+    before.mu.Lock()
+    before.notifyLocked(DowntimeEvent{Type: EventDown, URL: url, Timestamp: time.Now()})
+    before.mu.Unlock()
+    if err := before.SaveState(path); err != nil {
+        t.Fatal(err)
+    }
+
+    after, err := LoadState(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    alerter := newRecordingAlerter()
+    after.AddAlerter(alerter)
+
+    // A down alert rediscovered after the restart is not repeated
+    after.mu.Lock()
+    after.notifyLocked(DowntimeEvent{Type: EventDown, URL: url, Timestamp: time.Now()})
+    after.mu.Unlock()
+    alerter.expectNone(t)
+}
+
+func TestLoadStateSkipsRetriedAttemptsInCounters(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "state.json")
+    const url = "http://example.com/"
+    now := time.Now()
+
+    before := NewUptimeMonitor()
+    before.mu.Lock()
+    before.retainLogLocked(LogEntry{URL: url, Timestamp: now, Attempt: 1, Retried: true})
+    before.appendLogLocked(LogEntry{URL: url, Timestamp: now, Attempt: 2, Success: true})
+    before.mu.Unlock()
+    want := before.GetStats(url)
+    if err := before.SaveState(path); err != nil {
+        t.Fatal(err)
+    }
+
+    after, err := LoadState(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    got := after.GetStats(url)
+    if got.TotalChecks != want.TotalChecks || got.FailureCount != want.FailureCount ||
+        got.ConsecutiveSuccesses != want.ConsecutiveSuccesses {
+        t.Errorf("stats after restart = %+v, want %+v", got, want)
+    }
+    if logs := after.GetLogs(url); len(logs) != 2 {
+        t.Errorf("restored %d log entries, want both attempts", len(logs))
+    }
+}
@@ -0,0 +1,133 @@
+package entity
+
+import (
+    "encoding/json"
+    "net/http"
+    "time"
+)
+
+// Stats summarizes a URL. Check counts and uptime come from running counters, so they also
+// cover checks whose logs were not retained; response times are in milliseconds and
+// aggregate the retained logs only.
+type Stats struct {
+    URL             string  `json:"url"`
+    TotalChecks     int     `json:"totalChecks"`
+    SuccessCount    int     `json:"successCount"`
+    FailureCount    int     `json:"failureCount"`
+    UptimePercent   float64 `json:"uptimePercent"`
+    MinResponseTime int64   `json:"minResponseTime"`
+    AvgResponseTime int64   `json:"avgResponseTime"`
+    MaxResponseTime int64   `json:"maxResponseTime"`
+    MinTTFB         int64   `json:"minTtfb"`
+    AvgTTFB         int64   `json:"avgTtfb"`
+    MaxTTFB         int64   `json:"maxTtfb"`
+
+    // Checks in a row with the latest check's outcome; the other streak is zero
+    ConsecutiveSuccesses int `json:"consecutiveSuccesses"`
+    ConsecutiveFailures  int `json:"consecutiveFailures"`
+
+    // Percentiles of successful checks over the window requested from the stats endpoint
+    Percentiles *LatencyPercentiles `json:"percentiles,omitempty"`
+
+    // Share of checks served from cache, for monitors with a cache header
+    CacheHitRate float64 `json:"cacheHitRate,omitempty"` // percent
+
+    // Approximate traffic of every check, including those whose logs were not retained
+    BytesSent     int64 `json:"bytesSent"`
+    BytesReceived int64 `json:"bytesReceived"`
+
+    // RemoteAddrs counts checks per resolved IP:port, showing load balancer or DNS distribution
+    RemoteAddrs map[string]int `json:"remoteAddrs,omitempty"`
+
+    // POPs counts checks per CDN edge location, for monitors with a POP header
+    POPs map[string]int `json:"pops,omitempty"`
+}
+
+// GetStats computes statistics for a URL from its counters and a single pass over its logs.
+// All values are zero when the URL has not been checked yet.
+func (um *UptimeMonitor) GetStats(url string) Stats {
+    url = monitorKey(url)
+
+    um.mu.RLock()
+    defer um.mu.RUnlock()
+
+    stats := Stats{URL: url}
+    if state, ok := um.states[url]; ok {
+        stats.TotalChecks = int(state.checks)
+        stats.FailureCount = int(state.failures)
+        stats.SuccessCount = stats.TotalChecks - stats.FailureCount
+        stats.ConsecutiveSuccesses = state.successStreak
+        stats.ConsecutiveFailures = state.failureStreak
+        stats.BytesSent = state.bytesSent
+        stats.BytesReceived = state.bytesReceived
+        if state.cacheLookups > 0 {
+            stats.CacheHitRate = float64(state.cacheHits) / float64(state.cacheLookups) * 100
+        }
+    }
+
+    var retained int
+    var totalResponseTime, totalTTFB int64
+    for _, log := range um.logs[url] {
+        if retained == 0 || log.ResponseTime < stats.MinResponseTime {
+            stats.MinResponseTime = log.ResponseTime
+        }
+        if retained == 0 || log.TTFB < stats.MinTTFB {
+            stats.MinTTFB = log.TTFB
+        }
+        if log.ResponseTime > stats.MaxResponseTime {
+            stats.MaxResponseTime = log.ResponseTime
+        }
+        if log.TTFB > stats.MaxTTFB {
+            stats.MaxTTFB = log.TTFB
+        }
+        totalResponseTime += log.ResponseTime
+        totalTTFB += log.TTFB
+        if log.RemoteAddr != "" {
+            if stats.RemoteAddrs == nil {
+                stats.RemoteAddrs = make(map[string]int)
+            }
+            stats.RemoteAddrs[log.RemoteAddr]++
+        }
+        if log.POP != "" {
+            if stats.POPs == nil {
+                stats.POPs = make(map[string]int)
+            }
+            stats.POPs[log.POP]++
+        }
+        retained++
+    }
+
+    if stats.TotalChecks > 0 {
+        stats.UptimePercent = float64(stats.SuccessCount) / float64(stats.TotalChecks) * 100
+    }
+    if retained > 0 {
+        stats.AvgResponseTime = totalResponseTime / int64(retained)
+        stats.AvgTTFB = totalTTFB / int64(retained)
+    }
+    return stats
+}
+
+func (um *UptimeMonitor) HandleGetStats(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    url := r.URL.Query().Get("url")
+    if url == "" {
+        writeError(w, "URL parameter is required", http.StatusBadRequest)
+        return
+    }
+
+    stats := um.GetStats(url)
+    if value := r.URL.Query().Get("window"); value != "" {
+        window, err := time.ParseDuration(value)
+        if err != nil || window <= 0 {
+            writeError(w, "window must be a positive duration, e.g. 1h", http.StatusBadRequest)
+            return
+        }
+        p50, p95, p99 := um.GetLatencyPercentiles(url, window)
+        stats.Percentiles = &LatencyPercentiles{Window: window.String(), P50: p50, P95: p95, P99: p99}
+    }
+    json.NewEncoder(w).Encode(stats)
+}
@@ -0,0 +1,59 @@
+package entity
+
+import (
+    "testing"
+    "time"
+)
+
+func TestStreaks(t *testing.T) {
+    const url = "https://example.com/"
+    um := NewUptimeMonitor()
+    um.mu.Lock()
+    um.monitors[url] = Monitor{URL: url}
+    um.mu.Unlock()
+
+    start := time.Now()
+    steps := []struct {
+        success             bool
+        retried             bool
+        successes, failures int
+    }{
+        {success: true, successes: 1},
+        {success: true, successes: 2},
+        {failures: 1},
+        // A retried attempt is superseded by the final one and does not extend the streak
+        {retried: true, failures: 1},
+        {failures: 2},
+        {success: true, successes: 1},
+        {success: true, successes: 2},
+        {success: true, successes: 3},
+    }
+    for i, step := range steps {
+        entry := LogEntry{URL: url, Timestamp: start.Add(time.Duration(i) * time.Minute), Success: step.success}
+        if step.retried {
+            um.recordAttempt(entry)
+        } else {
+            um.mu.Lock()
+            um.appendLogLocked(entry)
+            um.mu.Unlock()
+        }
+
+        stats := um.GetStats(url)
+        if stats.ConsecutiveSuccesses != step.successes || stats.ConsecutiveFailures != step.failures {
+            t.Fatalf("after step %d: streaks = %d successes, %d failures, want %d and %d",
+                i+1, stats.ConsecutiveSuccesses, stats.ConsecutiveFailures, step.successes, step.failures)
+        }
+        summary := um.GetSummary()[0]
+        if summary.ConsecutiveSuccesses != step.successes || summary.ConsecutiveFailures != step.failures {
+            t.Fatalf("after step %d: summary streaks = %d successes, %d failures, want %d and %d",
+                i+1, summary.ConsecutiveSuccesses, summary.ConsecutiveFailures, step.successes, step.failures)
+        }
+    }
+}
+
+func TestStatsBeforeFirstCheck(t *testing.T) {
+    stats := NewUptimeMonitor().GetStats("https://example.com/")
+    if stats.TotalChecks != 0 || stats.ConsecutiveSuccesses != 0 || stats.ConsecutiveFailures != 0 {
+        t.Errorf("stats = %+v, want zeros", stats)
+    }
+}
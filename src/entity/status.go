@@ -0,0 +1,71 @@
+package entity
+
+import (
+    "encoding/json"
+    "net/http"
+    "time"
+)
+
+// Monitor states reported by GetStatus
+const (
+    StateUp      = "up"
+    StateDown    = "down"
+    StateStale   = "stale"   // the latest check is older than the configured staleness threshold
+    StateUnknown = "unknown" // no check has completed yet
+    StatePaused  = "paused"  // checks are suspended by PauseMonitor
+)
+
+// Status describes the current state of a monitored URL
+type Status struct {
+    URL       string    `json:"url"`
+    State     string    `json:"state"`
+    LastCheck time.Time `json:"lastCheck,omitempty"`
+}
+
+func (um *UptimeMonitor) GetStatus(url string) Status {
+    url = monitorKey(url)
+
+    um.mu.RLock()
+    defer um.mu.RUnlock()
+
+    return um.statusLocked(url, um.now())
+}
+
+// statusLocked derives the current state from the cached latest check; callers must hold um.mu
+func (um *UptimeMonitor) statusLocked(url string, now time.Time) Status {
+    status := Status{URL: url, State: StateUnknown}
+    if um.monitors[url].Paused {
+        status.State = StatePaused
+    }
+    state, ok := um.states[url]
+    if !ok {
+        return status
+    }
+
+    status.LastCheck = state.lastCheck
+    switch {
+    case status.State == StatePaused:
+    case um.staleAfter > 0 && now.Sub(state.lastCheck) > um.staleAfter:
+        status.State = StateStale
+    case state.lastSuccess:
+        status.State = StateUp
+    default:
+        status.State = StateDown
+    }
+    return status
+}
+
+func (um *UptimeMonitor) HandleGetStatus(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    url := r.URL.Query().Get("url")
+    if url == "" {
+        writeError(w, "URL parameter is required", http.StatusBadRequest)
+        return
+    }
+
+    json.NewEncoder(w).Encode(um.GetStatus(url))
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+// statusRange is an inclusive range of HTTP status codes
+type statusRange struct {
+    min, max int
+}
+
+// parseStatusRanges parses a comma-separated list of status codes and ranges, e.g. "200-299,304"
+func parseStatusRanges(expr string) ([]statusRange, error) {
+    var ranges []statusRange
+    for _, part := range strings.Split(expr, ",") {
+        part = strings.TrimSpace(part)
+        low, high, isRange := strings.Cut(part, "-")
+        if !isRange {
+            high = low
+        }
+        min, errMin := strconv.Atoi(strings.TrimSpace(low))
+        max, errMax := strconv.Atoi(strings.TrimSpace(high))
+        if errMin != nil || errMax != nil || min < 100 || max > 599 || min > max {
+            return nil, fmt.Errorf("acceptStatus: invalid status code or range %q", part)
+        }
+        ranges = append(ranges, statusRange{min: min, max: max})
+    }
+    return ranges, nil
+}
+
+// acceptsStatus reports whether expr, validated when the monitor was added, accepts code
+func acceptsStatus(expr string, code int) bool {
+    ranges, _ := parseStatusRanges(expr)
+    for _, r := range ranges {
+        if code >= r.min && code <= r.max {
+            return true
+        }
+    }
+    return false
+}
+
+// acceptsRedirect reports whether expr accepts any 3xx status code
+func acceptsRedirect(expr string) bool {
+    ranges, _ := parseStatusRanges(expr)
+    for _, r := range ranges {
+        if r.min < 400 && r.max >= 300 {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,58 @@
+package entity
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+)
+
+func TestParseStatusRanges(t *testing.T) {
+    ranges, err := parseStatusRanges(" 200-299, 304,418 - 418")
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := []statusRange{{200, 299}, {304, 304}, {418, 418}}
+    if len(ranges) != len(want) {
+        t.Fatalf("ranges = %v, want %v", ranges, want)
+    }
+    for i := range want {
+        if ranges[i] != want[i] {
+            t.Errorf("range %d = %v, want %v", i, ranges[i], want[i])
+        }
+    }
+
+    for _, expr := range []string{"", "200-", "299-200", "99", "200-600", "2xx", "200,,204"} {
+        if _, err := parseStatusRanges(expr); err == nil {
+            t.Errorf("parseStatusRanges(%q) succeeded, want an error", expr)
+        }
+    }
+}
+
+func TestAcceptStatus(t *testing.T) {
+    monitor := Monitor{AcceptStatus: "200-299,404"}
+    for code, want := range map[int]bool{200: true, 204: true, 299: true, 404: true, 301: false, 403: false, 500: false} {
+        if got := monitor.statusOK(code); got != want {
+            t.Errorf("statusOK(%d) = %v, want %v", code, got, want)
+        }
+    }
+    if monitor.stopsAtRedirect() {
+        t.Error("ranges without a 3xx code stop at redirects")
+    }
+    if !(Monitor{AcceptStatus: "200,301-302"}).stopsAtRedirect() {
+        t.Error("ranges accepting a 3xx code follow redirects")
+    }
+}
+
+func TestAcceptStatusCheck(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        http.Redirect(w, r, "/elsewhere", http.StatusFound)
+    }))
+    defer server.Close()
+    um := NewUptimeMonitor()
+
+    // The redirect itself is the response, as the accepted codes include 302
+    if entry := um.attemptCheck(context.Background(), Monitor{URL: server.URL + "/", AcceptStatus: "200,302"}); !entry.Success || entry.StatusCode != http.StatusFound {
+        t.Errorf("check = %+v, want the accepted 302", entry)
+    }
+}
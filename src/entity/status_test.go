@@ -0,0 +1,60 @@
+package entity
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+)
+
+func TestGetStatus(t *testing.T) {
+    now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+    um := NewUptimeMonitor(WithStaleAfter(5*time.Minute), WithClock(func() time.Time { return now }))
+    const url = "https://example.com/"
+    um.monitors[url] = Monitor{URL: url}
+
+    if status := um.GetStatus(url); status.State != StateUnknown {
+        t.Errorf("state before the first check = %s, want unknown", status.State)
+    }
+
+    um.mu.Lock()
+    um.appendLogLocked(LogEntry{URL: url, Success: true, Timestamp: now.Add(-time.Minute)})
+    um.mu.Unlock()
+    if status := um.GetStatus(url); status.State != StateUp || !status.LastCheck.Equal(now.Add(-time.Minute)) {
+        t.Errorf("status = %+v, want up as of a minute ago", status)
+    }
+
+    um.mu.Lock()
+    um.appendLogLocked(LogEntry{URL: url, Success: false, Timestamp: now})
+    um.mu.Unlock()
+    if status := um.GetStatus(url); status.State != StateDown {
+        t.Errorf("state = %s, want down", status.State)
+    }
+
+    now = now.Add(10 * time.Minute)
+    if status := um.GetStatus(url); status.State != StateStale {
+        t.Errorf("state ten minutes after the latest check = %s, want stale", status.State)
+    }
+
+    um.monitors[url] = Monitor{URL: url, Paused: true}
+    if status := um.GetStatus(url); status.State != StatePaused {
+        t.Errorf("state of a paused monitor = %s, want paused", status.State)
+    }
+}
+
+func TestHandleGetStatus(t *testing.T) {
+    um := NewUptimeMonitor()
+    rec := httptest.NewRecorder()
+    um.HandleGetStatus(rec, httptest.NewRequest(http.MethodGet, "/monitor/status?url=https://EXAMPLE.com", nil))
+    var status Status
+    if err := json.NewDecoder(rec.Body).Decode(&status); err != nil || status.URL != "https://example.com/" || status.State != StateUnknown {
+        t.Errorf("status = %+v, %v, want the normalized URL in state unknown", status, err)
+    }
+
+    rec = httptest.NewRecorder()
+    um.HandleGetStatus(rec, httptest.NewRequest(http.MethodGet, "/monitor/status", nil))
+    if rec.Code != http.StatusBadRequest {
+        t.Errorf("status without a URL = %d, want 400", rec.Code)
+    }
+}
@@ -0,0 +1,77 @@
+package entity
+
+import (
+    "encoding/json"
+    "net/http"
+    "sort"
+)
+
+// MonitorSummary is the current state of one monitor as shown on a dashboard
+type MonitorSummary struct {
+    URL              string `json:"url"`
+    State            string `json:"state"` // as reported by GetStatus
+    Up               bool   `json:"up"`    // the latest check succeeded
+    DownFor          string `json:"downFor,omitempty"`
+    LastResponseTime int64  `json:"lastResponseTime"` // in milliseconds
+
+    ConsecutiveSuccesses int `json:"consecutiveSuccesses"`
+    ConsecutiveFailures  int `json:"consecutiveFailures"`
+
+    // UptimePercent covers the retained logs only, excluding retried attempts
+    UptimePercent float64 `json:"uptimePercent"`
+
+    // Up/down changes within the flap detection window, and whether they reached its threshold
+    Transitions int  `json:"transitions"`
+    Flapping    bool `json:"flapping"`
+}
+
+// GetSummary summarizes every monitor, sorted by URL, from a single snapshot under the lock
+func (um *UptimeMonitor) GetSummary() []MonitorSummary {
+    um.mu.RLock()
+    defer um.mu.RUnlock()
+
+    now := um.now()
+    summaries := make([]MonitorSummary, 0, len(um.monitors))
+    for url := range um.monitors {
+        summary := MonitorSummary{URL: url, State: um.statusLocked(url, now).State}
+        if state, ok := um.states[url]; ok {
+            summary.Up = state.lastSuccess
+            summary.LastResponseTime = state.lastResponseTime
+            summary.ConsecutiveSuccesses = state.successStreak
+            summary.ConsecutiveFailures = state.failureStreak
+        }
+        if downtime := um.getLastDowntime(url); downtime != nil && downtime.EndTime.IsZero() {
+            summary.DownFor = now.Sub(downtime.StartTime).String()
+        }
+
+        var checks, successes int
+        for _, log := range um.logs[url] {
+            if log.Retried {
+                continue
+            }
+            checks++
+            if log.Success {
+                successes++
+            }
+        }
+        if checks > 0 {
+            summary.UptimePercent = float64(successes) / float64(checks) * 100
+        }
+        summary.Transitions = um.flapTransitionsLocked(url, now)
+        summary.Flapping = um.flapLimit > 0 && summary.Transitions >= um.flapLimit
+        summaries = append(summaries, summary)
+    }
+    sort.Slice(summaries, func(i, j int) bool {
+        return summaries[i].URL < summaries[j].URL
+    })
+    return summaries
+}
+
+func (um *UptimeMonitor) HandleGetSummary(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    json.NewEncoder(w).Encode(um.GetSummary())
+}
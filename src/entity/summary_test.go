@@ -0,0 +1,61 @@
+package entity
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+)
+
+func TestGetSummary(t *testing.T) {
+    now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+    um := NewUptimeMonitor(WithFlapDetection(time.Hour, 3), WithClock(func() time.Time { return now }))
+    const up, down, fresh = "https://a.example.com/", "https://b.example.com/", "https://c.example.com/"
+    for _, url := range []string{down, fresh, up} {
+        um.monitors[url] = Monitor{URL: url}
+    }
+
+    um.recordCheck(LogEntry{URL: up, Success: true, ResponseTime: 120, Timestamp: now.Add(-3 * time.Minute)})
+    um.recordAttempt(LogEntry{URL: up, Success: false, Timestamp: now.Add(-2 * time.Minute)})
+    um.recordCheck(LogEntry{URL: up, Success: true, ResponseTime: 80, Timestamp: now.Add(-2 * time.Minute)})
+    for i, success := range []bool{true, false, true, false} {
+        um.recordCheck(LogEntry{URL: down, Success: success, StatusCode: 500, Timestamp: now.Add(time.Duration(i-10) * time.Minute)})
+    }
+
+    summaries := um.GetSummary()
+    if len(summaries) != 3 || summaries[0].URL != up || summaries[1].URL != down || summaries[2].URL != fresh {
+        t.Fatalf("summaries = %+v, want all three sorted by URL", summaries)
+    }
+
+    if s := summaries[0]; !s.Up || s.State != StateUp || s.LastResponseTime != 80 || s.ConsecutiveSuccesses != 2 || s.UptimePercent != 100 || s.DownFor != "" {
+        t.Errorf("up summary = %+v, want the retried attempt ignored", s)
+    }
+    if s := summaries[1]; s.Up || s.State != StateDown || s.ConsecutiveFailures != 1 || s.UptimePercent != 50 || s.DownFor != "7m0s" {
+        t.Errorf("down summary = %+v", s)
+    }
+    if s := summaries[1]; s.Transitions != 3 || !s.Flapping {
+        t.Errorf("down summary has %d transitions, flapping %v, want 3 and flapping", s.Transitions, s.Flapping)
+    }
+    if s := summaries[2]; s.State != StateUnknown || s.Up || s.Transitions != 0 {
+        t.Errorf("unchecked summary = %+v", s)
+    }
+}
+
+func TestHandleGetSummary(t *testing.T) {
+    um := NewUptimeMonitor()
+    um.monitors["https://example.com/"] = Monitor{URL: "https://example.com/"}
+
+    rec := httptest.NewRecorder()
+    um.HandleGetSummary(rec, httptest.NewRequest(http.MethodGet, "/monitor/summary", nil))
+    var summaries []MonitorSummary
+    if err := json.NewDecoder(rec.Body).Decode(&summaries); err != nil || len(summaries) != 1 {
+        t.Errorf("summary = %+v, %v", summaries, err)
+    }
+
+    rec = httptest.NewRecorder()
+    um.HandleGetSummary(rec, httptest.NewRequest(http.MethodPost, "/monitor/summary", nil))
+    if rec.Code != http.StatusMethodNotAllowed {
+        t.Errorf("POST = %d, want 405", rec.Code)
+    }
+}
@@ -0,0 +1,36 @@
+//go:build !windows && !plan9
+
+package entity
+
+import (
+    "fmt"
+    "log/syslog"
+    "strings"
+)
+
+var syslogFacilities = map[string]syslog.Priority{
+    "kern":   syslog.LOG_KERN,
+    "user":   syslog.LOG_USER,
+    "daemon": syslog.LOG_DAEMON,
+    "local0": syslog.LOG_LOCAL0,
+    "local1": syslog.LOG_LOCAL1,
+    "local2": syslog.LOG_LOCAL2,
+    "local3": syslog.LOG_LOCAL3,
+    "local4": syslog.LOG_LOCAL4,
+    "local5": syslog.LOG_LOCAL5,
+    "local6": syslog.LOG_LOCAL6,
+    "local7": syslog.LOG_LOCAL7,
+}
+
+// DialSyslog connects to a syslog daemon, logging with the given facility, e.g. "daemon" or
+// "local0". An empty network and address use the local daemon.
+func DialSyslog(network, addr, facility string) (SyslogWriter, error) {
+    if facility == "" {
+        facility = "daemon"
+    }
+    priority, ok := syslogFacilities[strings.ToLower(facility)]
+    if !ok {
+        return nil, fmt.Errorf("unknown syslog facility %q", facility)
+    }
+    return syslog.Dial(network, addr, priority|syslog.LOG_INFO, "urlmonitor")
+}
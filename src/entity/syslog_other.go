@@ -0,0 +1,10 @@
+//go:build windows || plan9
+
+package entity
+
+import "errors"
+
+// DialSyslog reports that syslog is not available on this platform
+func DialSyslog(network, addr, facility string) (SyslogWriter, error) {
+    return nil, errors.New("syslog is not supported on this platform")
+}
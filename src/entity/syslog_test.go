@@ -0,0 +1,92 @@
+//go:build !windows && !plan9
+
+package entity
+
+import (
+    "context"
+    "log/slog"
+    "net"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestDialSyslogUnknownFacility(t *testing.T) {
+    if _, err := DialSyslog("udp", "127.0.0.1:514", "local9"); err == nil {
+        t.Error("expected an error for an unknown facility")
+    }
+}
+
+func TestSyslogResults(t *testing.T) {
+    conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer conn.Close()
+
+    writer, err := DialSyslog("udp", conn.LocalAddr().String(), "LOCAL3")
+    if err != nil {
+        t.Fatal(err)
+    }
+    um := NewUptimeMonitor()
+    um.syslog = writer
+
+    receive := func() string {
+        t.Helper()
+        buf := make([]byte, 4096)
+        conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+        n, _, err := conn.ReadFrom(buf)
+        if err != nil {
+            t.Fatal(err)
+        }
+        return string(buf[:n])
+    }
+
+    // local3 is facility 19, so info is priority 158 and warning 156
+    um.emitResult(LogEntry{URL: "https://example.com/", Success: true})
+    if message := receive(); !strings.HasPrefix(message, "<158>") || !strings.Contains(message, `"success":true`) {
+        t.Errorf("successful check sent as %q", message)
+    }
+    um.emitResult(LogEntry{URL: "https://example.com/", Success: false, Error: "timeout"})
+    if message := receive(); !strings.HasPrefix(message, "<156>") || !strings.Contains(message, `"error":"timeout"`) {
+        t.Errorf("failed check sent as %q", message)
+    }
+}
+
+func TestSyslogHandlerMapsLevels(t *testing.T) {
+    conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer conn.Close()
+
+    writer, err := DialSyslog("udp", conn.LocalAddr().String(), "local3")
+    if err != nil {
+        t.Fatal(err)
+    }
+    logger := slog.New(NewSyslogHandler(writer)).With("component", "test")
+
+    // local3 is facility 19, so the priorities are 152 plus the severity
+    tests := []struct {
+        level    slog.Level
+        priority string
+    }{
+        {slog.LevelDebug, "<159>"},
+        {slog.LevelInfo, "<158>"},
+        {slog.LevelWarn, "<156>"},
+        {slog.LevelError, "<155>"},
+    }
+    buf := make([]byte, 4096)
+    for _, tt := range tests {
+        logger.Log(context.Background(), tt.level, "Monitor added")
+        conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+        n, _, err := conn.ReadFrom(buf)
+        if err != nil {
+            t.Fatal(err)
+        }
+        message := string(buf[:n])
+        if !strings.HasPrefix(message, tt.priority) || !strings.Contains(message, `"component":"test"`) {
+            t.Errorf("%s record sent as %q, want priority %s", tt.level, message, tt.priority)
+        }
+    }
+}
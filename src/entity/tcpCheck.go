@@ -0,0 +1,49 @@
+package entity
+
+import (
+    "context"
+    "fmt"
+    "net"
+    "net/url"
+    "time"
+)
+
+// validateTCP requires TCP monitors to name both host and port
+func validateTCP(monitor Monitor) error {
+    target, err := url.Parse(monitor.URL)
+    if err != nil || target.Scheme != "tcp" || target.Port() == "" {
+        return fmt.Errorf("TCP monitor requires a tcp://host:port URL")
+    }
+    return nil
+}
+
+// checkTCP opens a connection to the host and port of a tcp:// monitor, e.g. a database or
+// SMTP server, and closes it again. ResponseTime is the connect time; there is no status code.
+func (um *UptimeMonitor) checkTCP(ctx context.Context, monitor Monitor) LogEntry {
+    entry := LogEntry{URL: monitor.URL}
+
+    target, err := url.Parse(monitor.URL)
+    if err != nil {
+        entry.Timestamp = um.now()
+        entry.Error = err.Error()
+        return entry
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, monitor.timeout())
+    defer cancel()
+
+    start := time.Now()
+    var dialer net.Dialer
+    conn, err := dialer.DialContext(ctx, "tcp", target.Host)
+    entry.Timestamp = um.now()
+    entry.ResponseTime = time.Since(start).Milliseconds()
+    if err != nil {
+        entry.Error = err.Error()
+        entry.ErrorClass = classifyError(err)
+        return entry
+    }
+    entry.RemoteAddr = conn.RemoteAddr().String()
+    conn.Close()
+    entry.Success = true
+    return entry
+}
@@ -0,0 +1,52 @@
+package entity
+
+import (
+    "context"
+    "net"
+    "testing"
+    "time"
+)
+
+func TestCheckTCP(t *testing.T) {
+    listener, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    addr := listener.Addr().String()
+    go func() {
+        for {
+            conn, err := listener.Accept()
+            if err != nil {
+                return
+            }
+            conn.Close()
+        }
+    }()
+    um := NewUptimeMonitor()
+
+    monitor := Monitor{URL: "tcp://" + addr, Type: MonitorTypeTCP}
+    entry := um.attemptCheck(context.Background(), monitor)
+    if !entry.Success || entry.RemoteAddr != addr || entry.StatusCode != 0 {
+        t.Errorf("check = %+v, want a connection to %s", entry, addr)
+    }
+
+    listener.Close()
+    entry = um.attemptCheck(context.Background(), monitor)
+    if entry.Success || entry.ErrorClass != ErrorClassConnRefused {
+        t.Errorf("check of a closed port = %+v, want the connection refused", entry)
+    }
+}
+
+func TestValidateTCP(t *testing.T) {
+    um := NewUptimeMonitor()
+    defer um.Close()
+
+    for _, url := range []string{"tcp://db.example.com", "https://db.example.com:5432"} {
+        if err := um.AddMonitorConfig(Monitor{URL: url, Type: MonitorTypeTCP, Interval: time.Hour}); err == nil {
+            t.Errorf("added TCP monitor %s, want an error", url)
+        }
+    }
+    if err := um.AddMonitorConfig(Monitor{URL: "tcp://db.example.com:5432", Type: MonitorTypeTCP, Interval: time.Hour}); err != nil {
+        t.Error(err)
+    }
+}
@@ -0,0 +1,130 @@
+package entity
+
+import (
+    "crypto/ecdsa"
+    "crypto/ed25519"
+    "crypto/rsa"
+    "crypto/tls"
+    "fmt"
+    "time"
+)
+
+var tlsVersions = map[string]uint16{
+    "1.0": tls.VersionTLS10,
+    "1.1": tls.VersionTLS11,
+    "1.2": tls.VersionTLS12,
+    "1.3": tls.VersionTLS13,
+}
+
+// parseTLSVersion converts a version string such as "1.2" into its tls constant
+func parseTLSVersion(version string) (uint16, error) {
+    v, ok := tlsVersions[version]
+    if !ok {
+        return 0, fmt.Errorf("unsupported TLS version %q (expected 1.0, 1.1, 1.2 or 1.3)", version)
+    }
+    return v, nil
+}
+
+// isWeakCipher reports whether the cipher suite is one Go considers insecure
+func isWeakCipher(id uint16) bool {
+    for _, suite := range tls.InsecureCipherSuites() {
+        if suite.ID == id {
+            return true
+        }
+    }
+    return false
+}
+
+// recordTLS stores the negotiated TLS parameters on the log entry
+func recordTLS(entry *LogEntry, state *tls.ConnectionState) {
+    if state == nil {
+        return
+    }
+    entry.TLSVersion = tls.VersionName(state.Version)
+    entry.CipherSuite = tls.CipherSuiteName(state.CipherSuite)
+    entry.KeyType, entry.KeyBits = peerKeySize(state)
+    if expiry := certExpiry(state); !expiry.IsZero() {
+        entry.CertExpiryDays = int(expiry.Sub(entry.Timestamp).Hours() / 24)
+    }
+}
+
+// certExpiry returns the earliest NotAfter of the presented certificate chain
+func certExpiry(state *tls.ConnectionState) time.Time {
+    var earliest time.Time
+    for _, cert := range state.PeerCertificates {
+        if earliest.IsZero() || cert.NotAfter.Before(earliest) {
+            earliest = cert.NotAfter
+        }
+    }
+    return earliest
+}
+
+// checkCertExpiry returns an error when the certificate chain expires within the monitor's threshold
+func checkCertExpiry(monitor Monitor, state *tls.ConnectionState, entry *LogEntry) error {
+    if state == nil || monitor.CertExpiryThreshold <= 0 || len(state.PeerCertificates) == 0 {
+        return nil
+    }
+    if entry.CertExpiryDays < monitor.CertExpiryThreshold {
+        return fmt.Errorf("certificate expires in %d days, within the %d day threshold", entry.CertExpiryDays, monitor.CertExpiryThreshold)
+    }
+    return nil
+}
+
+// peerKeySize returns the type and size in bits of the leaf certificate's public key
+func peerKeySize(state *tls.ConnectionState) (string, int) {
+    if len(state.PeerCertificates) == 0 {
+        return "", 0
+    }
+    switch key := state.PeerCertificates[0].PublicKey.(type) {
+    case *rsa.PublicKey:
+        return "RSA", key.N.BitLen()
+    case *ecdsa.PublicKey:
+        return "ECDSA", key.Curve.Params().BitSize
+    case ed25519.PublicKey:
+        return "Ed25519", 256
+    }
+    return "unknown", 0
+}
+
+// checkKeySize returns an error when the leaf certificate's key is smaller than the configured minimum
+func checkKeySize(monitor Monitor, state *tls.ConnectionState) error {
+    if state == nil {
+        return nil
+    }
+
+    keyType, bits := peerKeySize(state)
+    var min int
+    switch keyType {
+    case "RSA":
+        min = monitor.MinRSAKeyBits
+    case "ECDSA":
+        min = monitor.MinECDSAKeyBits
+    }
+    if min > 0 && bits < min {
+        return fmt.Errorf("certificate %s key is %d bits, below the minimum of %d", keyType, bits, min)
+    }
+    return nil
+}
+
+// checkTLSPolicy returns an error when the negotiated connection violates the monitor's TLS policy.
+// Plain HTTP responses are not subject to the policy.
+func checkTLSPolicy(monitor Monitor, state *tls.ConnectionState) error {
+    if state == nil {
+        return nil
+    }
+
+    if monitor.MinTLSVersion != "" {
+        min, err := parseTLSVersion(monitor.MinTLSVersion)
+        if err != nil {
+            return err
+        }
+        if state.Version < min {
+            return fmt.Errorf("negotiated %s is below minimum TLS %s", tls.VersionName(state.Version), monitor.MinTLSVersion)
+        }
+    }
+
+    if monitor.RejectWeakCiphers && isWeakCipher(state.CipherSuite) {
+        return fmt.Errorf("negotiated weak cipher suite %s", tls.CipherSuiteName(state.CipherSuite))
+    }
+    return nil
+}
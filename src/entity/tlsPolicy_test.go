@@ -0,0 +1,77 @@
+package entity
+
+import (
+    "context"
+    "crypto/tls"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+// newTLS12Server starts an HTTPS server that negotiates at most TLS 1.2
+func newTLS12Server(t *testing.T) *httptest.Server {
+    t.Helper()
+    server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+    server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+    server.StartTLS()
+    t.Cleanup(server.Close)
+    return server
+}
+
+func TestRecordTLS(t *testing.T) {
+    server := newTLS12Server(t)
+    entry := NewUptimeMonitor().attemptCheck(context.Background(), Monitor{URL: server.URL + "/", InsecureSkipVerify: true})
+    if !entry.Success {
+        t.Fatalf("check failed: %s", entry.Error)
+    }
+    if entry.TLSVersion != "TLS 1.2" || entry.CipherSuite == "" || entry.KeyType != "RSA" || entry.KeyBits < 2048 {
+        t.Errorf("recorded TLS %q, cipher %q, key %s %d", entry.TLSVersion, entry.CipherSuite, entry.KeyType, entry.KeyBits)
+    }
+    if entry.CertExpiryDays <= 0 {
+        t.Errorf("certificate expires in %d days", entry.CertExpiryDays)
+    }
+}
+
+func TestTLSPolicy(t *testing.T) {
+    server := newTLS12Server(t)
+    um := NewUptimeMonitor()
+    tests := []struct {
+        monitor Monitor
+        wantErr string
+    }{
+        {Monitor{MinTLSVersion: "1.2"}, ""},
+        {Monitor{MinTLSVersion: "1.3"}, "below minimum TLS 1.3"},
+        {Monitor{MinRSAKeyBits: 1024}, ""},
+        {Monitor{MinRSAKeyBits: 8192}, "below the minimum of 8192"},
+        {Monitor{MinECDSAKeyBits: 384}, ""}, // the server's key is RSA
+        {Monitor{CertExpiryThreshold: 30}, ""},
+        {Monitor{CertExpiryThreshold: 1000000}, "within the 1000000 day threshold"},
+    }
+    for i, test := range tests {
+        monitor := test.monitor
+        monitor.URL = server.URL + "/"
+        monitor.InsecureSkipVerify = true
+        entry := um.attemptCheck(context.Background(), monitor)
+        if test.wantErr == "" && !entry.Success {
+            t.Errorf("check %d failed: %s", i, entry.Error)
+        }
+        if test.wantErr != "" && (entry.Success || !strings.Contains(entry.Error, test.wantErr)) {
+            t.Errorf("check %d = %q, want an error containing %q", i, entry.Error, test.wantErr)
+        }
+    }
+}
+
+func TestCheckTLSPolicyWeakCipher(t *testing.T) {
+    weak := tls.InsecureCipherSuites()[0]
+    state := &tls.ConnectionState{Version: tls.VersionTLS12, CipherSuite: weak.ID}
+    if err := checkTLSPolicy(Monitor{RejectWeakCiphers: true}, state); err == nil {
+        t.Errorf("%s was accepted, want it rejected as weak", weak.Name)
+    }
+    if err := checkTLSPolicy(Monitor{RejectWeakCiphers: true}, &tls.ConnectionState{CipherSuite: tls.TLS_AES_128_GCM_SHA256}); err != nil {
+        t.Error(err)
+    }
+    if err := checkTLSPolicy(Monitor{MinTLSVersion: "1.3"}, nil); err != nil {
+        t.Errorf("plain HTTP response = %v, want no policy", err)
+    }
+}
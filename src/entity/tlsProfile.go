@@ -0,0 +1,109 @@
+package entity
+
+import (
+    "crypto/tls"
+    "fmt"
+    "net/http"
+    "slices"
+    "strings"
+)
+
+// TLSProfile customises the client hello a monitor presents, e.g. to debug endpoints that
+// fingerprint clients (JA3). Go's TLS stack decides the order of cipher suites and curves and
+// which other extensions are sent, so the profile restricts what is offered rather than
+// reproducing a browser byte for byte.
+type TLSProfile struct {
+    CipherSuites []string `json:"cipherSuites,omitempty"` // IANA names, e.g. "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"
+    Curves       []string `json:"curves,omitempty"`       // "X25519", "P256", "P384" or "P521"
+    ALPN         []string `json:"alpn,omitempty"`         // e.g. "h2", "http/1.1"
+    ServerName   string   `json:"serverName,omitempty"`   // SNI override
+    MinVersion   string   `json:"minVersion,omitempty"`   // e.g. "1.2"
+    MaxVersion   string   `json:"maxVersion,omitempty"`
+}
+
+var tlsCurves = map[string]tls.CurveID{
+    "X25519": tls.X25519,
+    "P256":   tls.CurveP256,
+    "P384":   tls.CurveP384,
+    "P521":   tls.CurveP521,
+}
+
+func (p *TLSProfile) validate() error {
+    _, err := p.config(nil)
+    return err
+}
+
+// config applies the profile on top of base, which may be nil
+func (p *TLSProfile) config(base *tls.Config) (*tls.Config, error) {
+    config := &tls.Config{}
+    if base != nil {
+        config = base.Clone()
+    }
+    if p.ServerName != "" {
+        config.ServerName = p.ServerName
+    }
+    if len(p.ALPN) > 0 {
+        config.NextProtos = p.ALPN
+    }
+
+    if len(p.CipherSuites) > 0 {
+        suites := make(map[string]uint16)
+        for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+            suites[suite.Name] = suite.ID
+        }
+        for _, name := range p.CipherSuites {
+            id, ok := suites[name]
+            if !ok {
+                return nil, fmt.Errorf("unknown cipher suite %q", name)
+            }
+            config.CipherSuites = append(config.CipherSuites, id)
+        }
+    }
+
+    for _, name := range p.Curves {
+        id, ok := tlsCurves[strings.ToUpper(name)]
+        if !ok {
+            return nil, fmt.Errorf("unknown curve %q (expected X25519, P256, P384 or P521)", name)
+        }
+        config.CurvePreferences = append(config.CurvePreferences, id)
+    }
+
+    var err error
+    if p.MinVersion != "" {
+        if config.MinVersion, err = parseTLSVersion(p.MinVersion); err != nil {
+            return nil, err
+        }
+    }
+    if p.MaxVersion != "" {
+        if config.MaxVersion, err = parseTLSVersion(p.MaxVersion); err != nil {
+            return nil, err
+        }
+    }
+    if config.MinVersion != 0 && config.MaxVersion != 0 && config.MinVersion > config.MaxVersion {
+        return nil, fmt.Errorf("tls profile minVersion %s is above maxVersion %s", p.MinVersion, p.MaxVersion)
+    }
+    return config, nil
+}
+
+// profileTransport clones base with the profile's TLS settings. Keep-alives are disabled so the
+// per-check transport does not leave idle connections behind, and every check performs a fresh
+// handshake with the profile.
+func profileTransport(base http.RoundTripper, profile *TLSProfile) (*http.Transport, error) {
+    t, ok := base.(*http.Transport)
+    if !ok || t == nil {
+        t = http.DefaultTransport.(*http.Transport)
+    }
+    config, err := profile.config(t.TLSClientConfig)
+    if err != nil {
+        return nil, err
+    }
+    transport := t.Clone()
+    transport.TLSClientConfig = config
+    transport.DisableKeepAlives = true
+    // An HTTP/2 capable transport appends "h2" to the offered protocols, so a profile listing
+    // its own ALPN only gets HTTP/2 when it offers h2 itself
+    if len(profile.ALPN) > 0 {
+        transport.ForceAttemptHTTP2 = slices.Contains(profile.ALPN, "h2")
+    }
+    return transport, nil
+}
@@ -0,0 +1,86 @@
+package entity
+
+import (
+    "context"
+    "crypto/tls"
+    "net/http"
+    "net/http/httptest"
+    "slices"
+    "testing"
+)
+
+func TestTLSProfileClientHello(t *testing.T) {
+    hellos := make(chan *tls.ClientHelloInfo, 1)
+    server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+    server.TLS = &tls.Config{
+        GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+            hellos <- hello
+            return nil, nil
+        },
+    }
+    server.StartTLS()
+    defer server.Close()
+
+    profile := &TLSProfile{
+        CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+        Curves:       []string{"p256"},
+        ALPN:         []string{"http/1.1"},
+        ServerName:   "fingerprint.example.com",
+        MaxVersion:   "1.2",
+    }
+    entry := NewUptimeMonitor().attemptCheck(context.Background(), Monitor{URL: server.URL + "/", InsecureSkipVerify: true, TLSProfile: profile})
+    if !entry.Success || entry.TLSVersion != "TLS 1.2" || entry.CipherSuite != "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256" {
+        t.Fatalf("check = %+v, want TLS 1.2 with the only offered suite", entry)
+    }
+
+    hello := <-hellos
+    if hello.ServerName != "fingerprint.example.com" {
+        t.Errorf("SNI = %q", hello.ServerName)
+    }
+    if !slices.Equal(hello.CipherSuites, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}) {
+        t.Errorf("offered cipher suites = %x", hello.CipherSuites)
+    }
+    if !slices.Equal(hello.SupportedCurves, []tls.CurveID{tls.CurveP256}) {
+        t.Errorf("offered curves = %v", hello.SupportedCurves)
+    }
+    if !slices.Equal(hello.SupportedProtos, []string{"http/1.1"}) {
+        t.Errorf("offered ALPN = %v", hello.SupportedProtos)
+    }
+}
+
+func TestTLSProfileValidate(t *testing.T) {
+    for _, profile := range []TLSProfile{
+        {CipherSuites: []string{"TLS_MADE_UP"}},
+        {Curves: []string{"P224"}},
+        {MinVersion: "1.4"},
+        {MinVersion: "1.3", MaxVersion: "1.2"},
+    } {
+        if err := profile.validate(); err == nil {
+            t.Errorf("validate(%+v) succeeded, want an error", profile)
+        }
+    }
+    if err := (&TLSProfile{Curves: []string{"x25519"}, MinVersion: "1.2", MaxVersion: "1.3"}).validate(); err != nil {
+        t.Error(err)
+    }
+}
+
+func TestProfileTransportHTTP2(t *testing.T) {
+    transport, err := profileTransport(nil, &TLSProfile{ALPN: []string{"h2", "http/1.1"}})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !transport.ForceAttemptHTTP2 || !transport.DisableKeepAlives {
+        t.Errorf("transport forces HTTP/2 %v, disables keep-alives %v, want both", transport.ForceAttemptHTTP2, transport.DisableKeepAlives)
+    }
+    if transport == http.DefaultTransport {
+        t.Error("the default transport was modified")
+    }
+
+    transport, err = profileTransport(nil, &TLSProfile{ALPN: []string{"http/1.1"}})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if transport.ForceAttemptHTTP2 {
+        t.Error("transport attempts HTTP/2 for a profile that does not offer h2")
+    }
+}
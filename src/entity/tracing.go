@@ -0,0 +1,64 @@
+package entity
+
+import (
+    "context"
+
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/codes"
+    "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+    "go.opentelemetry.io/otel/sdk/resource"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    "go.opentelemetry.io/otel/trace"
+    "go.opentelemetry.io/otel/trace/noop"
+)
+
+const tracerName = "urlmonitor"
+
+// WithTracerProvider emits an OpenTelemetry span for every check
+func WithTracerProvider(provider trace.TracerProvider) Option {
+    return func(um *UptimeMonitor) {
+        um.tracer = provider.Tracer(tracerName)
+    }
+}
+
+// NewOTLPTracerProvider batches check spans to an OTLP/HTTP collector, e.g. http://localhost:4318
+func NewOTLPTracerProvider(ctx context.Context, endpointURL string) (*sdktrace.TracerProvider, error) {
+    exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpointURL))
+    if err != nil {
+        return nil, err
+    }
+    return sdktrace.NewTracerProvider(
+        sdktrace.WithBatcher(exporter),
+        sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", tracerName))),
+    ), nil
+}
+
+func defaultTracer() trace.Tracer {
+    return noop.NewTracerProvider().Tracer(tracerName)
+}
+
+// startCheckSpan opens the span covering one check of the monitor
+func (um *UptimeMonitor) startCheckSpan(monitor Monitor) (context.Context, trace.Span) {
+    return um.tracer.Start(context.Background(), "check",
+        trace.WithSpanKind(trace.SpanKindClient),
+        trace.WithAttributes(
+            attribute.String("url.full", monitor.URL),
+            attribute.String("monitor.type", monitor.Type),
+        ))
+}
+
+// traceResult records the outcome of a check on its span and passes the entry through
+func traceResult(span trace.Span, entry LogEntry) LogEntry {
+    span.SetAttributes(
+        attribute.Int("http.response.status_code", entry.StatusCode),
+        attribute.Int64("check.response_time_ms", entry.ResponseTime),
+        attribute.Bool("check.success", entry.Success),
+    )
+    if !entry.Success {
+        if entry.ErrorClass != "" {
+            span.SetAttributes(attribute.String("error.type", entry.ErrorClass))
+        }
+        span.SetStatus(codes.Error, entry.Error)
+    }
+    return entry
+}
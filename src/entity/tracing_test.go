@@ -0,0 +1,33 @@
+package entity
+
+import (
+    "context"
+    "testing"
+
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    "go.opentelemetry.io/otel/trace"
+)
+
+// spanRecordingResolver remembers the span of the context its lookups run in
+type spanRecordingResolver struct {
+    span trace.SpanContext
+}
+
+func (r *spanRecordingResolver) LookupRecords(ctx context.Context, host string) ([]DNSRecord, error) {
+    r.span = trace.SpanContextFromContext(ctx)
+    return []DNSRecord{{Type: "A", Value: "192.0.2.1"}}, nil
+}
+
+func TestDNSCheckRunsInCheckSpan(t *testing.T) {
+    provider := sdktrace.NewTracerProvider()
+    defer provider.Shutdown(context.Background())
+    resolver := &spanRecordingResolver{}
+    um := NewUptimeMonitor(WithDNSResolver(resolver), WithTracerProvider(provider))
+
+    if entry := um.checkURL(Monitor{URL: "dns://example.com", Type: MonitorTypeDNS}); !entry.Success {
+        t.Fatalf("check failed: %s", entry.Error)
+    }
+    if !resolver.span.IsValid() {
+        t.Error("DNS lookup did not run in the check's span")
+    }
+}
@@ -0,0 +1,87 @@
+package entity
+
+import (
+    "encoding/json"
+    "net/http"
+    "time"
+)
+
+// maxTransitions bounds the state changes kept per URL; the oldest are dropped first
+const maxTransitions = 1000
+
+// Transition marks a point where a URL's state changed
+type Transition struct {
+    Timestamp time.Time `json:"timestamp"`
+    State     string    `json:"state"` // StateUp or StateDown
+}
+
+// observeTransition records the check's state when it is the first check or differs from
+// the previous one. Only final checks are observed, so retried attempts are not state changes.
+func (s *urlState) observeTransition(entry LogEntry) {
+    transition := Transition{Timestamp: entry.Timestamp, State: StateDown}
+    if entry.Success {
+        transition.State = StateUp
+    }
+    switch {
+    case s.checks == 0:
+        s.firstCheck = transition
+    case entry.Success != s.lastSuccess:
+        if len(s.transitions) >= maxTransitions {
+            s.transitions = s.transitions[1:]
+        }
+        s.transitions = append(s.transitions, transition)
+    }
+}
+
+// GetTransitions returns the state changes for a URL within [since, until]. A zero bound is open.
+// The first check ever recorded counts as a transition out of the unknown state. Failed attempts
+// that were retried are not state changes. Transitions are tracked as checks are recorded, so
+// they include recoveries whose successful checks are not retained in the log; after a restart
+// they are rebuilt from the saved log.
+func (um *UptimeMonitor) GetTransitions(url string, since, until time.Time) []Transition {
+    url = monitorKey(url)
+
+    um.mu.RLock()
+    defer um.mu.RUnlock()
+
+    transitions := make([]Transition, 0)
+    state, ok := um.states[url]
+    if !ok || state.checks == 0 {
+        return transitions
+    }
+    for _, transition := range append([]Transition{state.firstCheck}, state.transitions...) {
+        if !until.IsZero() && transition.Timestamp.After(until) {
+            break
+        }
+        if since.IsZero() || !transition.Timestamp.Before(since) {
+            transitions = append(transitions, transition)
+        }
+    }
+    return transitions
+}
+
+func (um *UptimeMonitor) HandleGetTransitions(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    url := r.URL.Query().Get("url")
+    if url == "" {
+        writeError(w, "URL parameter is required", http.StatusBadRequest)
+        return
+    }
+
+    since, err := parseTimeParam(r, "since")
+    if err != nil {
+        writeError(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    until, err := parseTimeParam(r, "until")
+    if err != nil {
+        writeError(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+
+    json.NewEncoder(w).Encode(um.GetTransitions(url, since, until))
+}
@@ -0,0 +1,108 @@
+package entity
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "net/url"
+    "testing"
+    "time"
+)
+
+func TestGetTransitions(t *testing.T) {
+    um := NewUptimeMonitor()
+    const target = "https://example.com/"
+    start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+    um.mu.Lock()
+    for _, entry := range []LogEntry{
+        {Success: true, Timestamp: at(0)},
+        {Success: true, Timestamp: at(1)},
+        {Success: false, Retried: true, Timestamp: at(2)},
+        {Success: true, Timestamp: at(2)},
+        {Success: false, Timestamp: at(3)},
+        {Success: false, Timestamp: at(4)},
+        {Success: true, Timestamp: at(5)},
+    } {
+        entry.URL = target
+        if entry.Retried {
+            um.retainLogLocked(entry)
+        } else {
+            um.appendLogLocked(entry)
+        }
+    }
+    um.mu.Unlock()
+
+    tests := []struct {
+        since, until time.Time
+        want         []Transition
+    }{
+        {want: []Transition{{at(0), StateUp}, {at(3), StateDown}, {at(5), StateUp}}},
+        {since: at(1), want: []Transition{{at(3), StateDown}, {at(5), StateUp}}},
+        {since: at(4), want: []Transition{{at(5), StateUp}}},
+        {until: at(4), want: []Transition{{at(0), StateUp}, {at(3), StateDown}}},
+        {since: at(1), until: at(2), want: []Transition{}},
+    }
+    for _, test := range tests {
+        got := um.GetTransitions(target, test.since, test.until)
+        if len(got) != len(test.want) {
+            t.Errorf("transitions in [%v, %v] = %v, want %v", test.since, test.until, got, test.want)
+            continue
+        }
+        for i := range got {
+            if !got[i].Timestamp.Equal(test.want[i].Timestamp) || got[i].State != test.want[i].State {
+                t.Errorf("transitions in [%v, %v] = %v, want %v", test.since, test.until, got, test.want)
+                break
+            }
+        }
+    }
+}
+
+func TestTransitionsWithoutSuccessLogs(t *testing.T) {
+    const target = "https://example.com/"
+    start := time.Now().Add(-10 * time.Minute)
+    at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+    um := NewUptimeMonitor(WithoutSuccessLogs(), WithClock(func() time.Time { return at(10) }), WithFlapDetection(time.Hour, 3))
+
+    um.mu.Lock()
+    um.monitors[target] = Monitor{URL: target}
+    for i, success := range []bool{true, false, true, false, true} {
+        um.appendLogLocked(LogEntry{URL: target, Success: success, Timestamp: at(i)})
+    }
+    um.mu.Unlock()
+
+    if logs := um.GetLogs(target); len(logs) != 2 {
+        t.Fatalf("retained %d logs, want only the 2 failures", len(logs))
+    }
+    want := []Transition{{at(0), StateUp}, {at(1), StateDown}, {at(2), StateUp}, {at(3), StateDown}, {at(4), StateUp}}
+    got := um.GetTransitions(target, time.Time{}, time.Time{})
+    if len(got) != len(want) {
+        t.Fatalf("transitions = %v, want %v including the recoveries", got, want)
+    }
+    for i := range got {
+        if !got[i].Timestamp.Equal(want[i].Timestamp) || got[i].State != want[i].State {
+            t.Fatalf("transitions = %v, want %v including the recoveries", got, want)
+        }
+    }
+    if summary := um.GetSummary()[0]; summary.Transitions != 4 || !summary.Flapping {
+        t.Errorf("transitions = %d, flapping = %v, want 4 and flapping", summary.Transitions, summary.Flapping)
+    }
+}
+
+func TestHandleGetTransitions(t *testing.T) {
+    um := NewUptimeMonitor()
+    rec := httptest.NewRecorder()
+    um.HandleGetTransitions(rec, httptest.NewRequest(http.MethodGet, "/monitor/transitions?url=https://example.com/", nil))
+    if rec.Code != http.StatusOK || rec.Body.String() != "[]\n" {
+        t.Errorf("transitions without logs = %d %q, want an empty list", rec.Code, rec.Body.String())
+    }
+
+    rec = httptest.NewRecorder()
+    query := url.Values{"url": {"https://example.com/"}, "since": {"yesterday"}}
+    um.HandleGetTransitions(rec, httptest.NewRequest(http.MethodGet, "/monitor/transitions?"+query.Encode(), nil))
+    var body map[string]interface{}
+    if rec.Code != http.StatusBadRequest || json.NewDecoder(rec.Body).Decode(&body) != nil {
+        t.Errorf("invalid since = %d, want a JSON 400", rec.Code)
+    }
+}
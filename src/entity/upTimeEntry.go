@@ -1,69 +1,426 @@
 package entity
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
+// retryDelay is the minimum pause between attempts of a single check
+const retryDelay = 200 * time.Millisecond
+
+// defaultTimeout bounds a request attempt of monitors without their own Timeout
+const defaultTimeout = 10 * time.Second
+
+// checkMethods are the HTTP methods a monitor may use
+var checkMethods = map[string]bool{
+    http.MethodGet:     true,
+    http.MethodHead:    true,
+    http.MethodPost:    true,
+    http.MethodPut:     true,
+    http.MethodPatch:   true,
+    http.MethodDelete:  true,
+    http.MethodOptions: true,
+}
+
+// defaultMaxLogs is the number of log entries retained across all URLs
+const defaultMaxLogs = 10000
+
+// maxBodyBytes caps how much of a response body is read per check
+const maxBodyBytes = 1 << 20
+
 type UptimeMonitor struct {
 	monitors     map[string]Monitor
-	logs         []LogEntry
-	downtimes    []DowntimeEntry
+	logs         map[string][]LogEntry      // retained checks per URL, oldest first
+	logSeq       map[string]int             // per URL, sequence number of its oldest retained check
+	logOrder     []logRef                   // every retained check, oldest first, for global eviction
+	logCount     int                        // retained checks across all URLs
+	downtimes    map[string][]DowntimeEntry // per URL, oldest first
 	stopChannels map[string]chan struct{}
+	archived     map[string]Monitor // stopped monitors kept for revival
+	checking     map[string]int     // checks in flight per URL; scheduled and on-demand checks may overlap
+	inFlight     int                // checks in flight across all URLs
+	states       map[string]*urlState
+	notified     map[string]string // last down or up event sent per URL, persisted to suppress repeats
+	activeLoops  int64             // running monitorURL goroutines, accessed atomically
 	mu           sync.RWMutex
 	client       *http.Client
+	dnsResolver  DNSResolver
+	debugToken   string
+	mergeGap     time.Duration
+	staleAfter   time.Duration
+	flapWindow   time.Duration // recent period in which state changes count towards flapping
+	flapLimit    int           // state changes within flapWindow marking a monitor as flapping
+	skipSuccess  bool          // do not retain logs of successful checks
+	maxLogs      int           // oldest log entries beyond this are evicted; zero keeps everything
+	urlLogCap    int           // oldest entries of a URL beyond this are evicted; zero disables, set by AdaptRetention
+	maxInFlight  int           // AddMonitor is rejected while this many checks run; zero disables
+	checkSlots   chan struct{} // semaphore bounding concurrent check attempts; nil is unlimited
+	ready        chan struct{} // closed once checks may start
+	readyCheck   ReadinessCheck
+	readyTimeout time.Duration
+	syslog       SyslogWriter
+	alerters     []Alerter
+	deadLetters  *deadLetterQueue // notifications that exhausted every attempt
+	notifyTries  int
+	notifyWait   time.Duration
+	logContext   int       // recent log entries attached to notifications
+	buckets      []float64 // latency histogram bounds in milliseconds
+	now          func() time.Time
+	retryJitter  time.Duration
+	randMu       sync.Mutex
+	rand         *rand.Rand
+	tracer       trace.Tracer
+	incidents    IncidentManager
+	compareVia   map[string]DNSResolver // named resolvers used by CompareResolvers
+	agents       []RemoteAgent
+	samples      *bodySampleStore
+	digest       *digestBuffer // batches events into periodic digests when set
+	started      time.Time
+	closed       chan struct{} // closed by Close to stop background work
+	closeOnce    sync.Once
+	insecureOnce sync.Once
+	insecure     *http.Transport // shared by monitors skipping certificate verification
 }
 
-func NewUptimeMonitor() *UptimeMonitor {
-    return &UptimeMonitor{
+func NewUptimeMonitor(opts ...Option) *UptimeMonitor {
+    um := &UptimeMonitor{
         monitors:     make(map[string]Monitor),
-        logs:         make([]LogEntry, 0),
-        downtimes:    make([]DowntimeEntry, 0),
+        logs:         make(map[string][]LogEntry),
+        logSeq:       make(map[string]int),
+        downtimes:    make(map[string][]DowntimeEntry),
         stopChannels: make(map[string]chan struct{}),
-        client: &http.Client{
-            Timeout: 10 * time.Second,
-        },
+        archived:     make(map[string]Monitor),
+        checking:     make(map[string]int),
+        states:       make(map[string]*urlState),
+        notified:     make(map[string]string),
+        ready:        make(chan struct{}),
+        closed:       make(chan struct{}),
+        started:      time.Now(),
+        tracer:       defaultTracer(),
+        now:          time.Now,
+        retryJitter:  retryDelay,
+        logContext:   defaultNotifyLogContext,
+        maxLogs:      defaultMaxLogs,
+        buckets:      defaultLatencyBuckets,
+        deadLetters:  &deadLetterQueue{nextID: 1},
+        notifyTries:  defaultNotifyAttempts,
+        notifyWait:   defaultNotifyBackoff,
+        flapWindow:   defaultFlapWindow,
+        flapLimit:    defaultFlapThreshold,
+        rand:         rand.New(rand.NewSource(time.Now().UnixNano())),
+        dnsResolver:  systemResolver{},
+        // Timeouts are applied per request from each monitor's configuration
+        client: &http.Client{},
+    }
+    for _, opt := range opts {
+        opt(um)
+    }
+    if um.digest != nil {
+        go um.runDigest()
+    }
+    if um.readyCheck != nil {
+        go um.awaitReady()
+    } else {
+        close(um.ready)
     }
+    return um
+}
+
+// Close stops every monitor's checks and the background work started by NewUptimeMonitor,
+// sending the events collected for a pending digest. The monitor must not be used afterwards.
+func (um *UptimeMonitor) Close() {
+    um.closeOnce.Do(func() {
+        um.mu.Lock()
+        for url := range um.stopChannels {
+            um.stopLocked(url)
+        }
+        um.mu.Unlock()
+        close(um.closed)
+    })
 }
 
 func (um *UptimeMonitor) AddMonitor(url string, interval time.Duration) error {
+    return um.AddMonitorConfig(Monitor{URL: url, Interval: interval})
+}
+
+// AddMonitorConfig starts monitoring with the full per-monitor configuration
+func (um *UptimeMonitor) AddMonitorConfig(monitor Monitor) error {
     um.mu.Lock()
     defer um.mu.Unlock()
 
-    if interval == 0 {
-        interval = 30 * time.Second
+    if err := normalizeMonitor(&monitor); err != nil {
+        return err
     }
 
-    if _, exists := um.monitors[url]; exists {
-        return fmt.Errorf("URL %s is already being monitored", url)
+    if _, exists := um.monitors[monitor.URL]; exists {
+        return fmt.Errorf("URL %s is already being monitored", monitor.URL)
+    }
+    if _, archived := um.archived[monitor.URL]; archived {
+        return fmt.Errorf("URL %s is archived, unarchive it instead", monitor.URL)
+    }
+    if um.saturatedLocked() {
+        return ErrSaturated
     }
 
-    um.monitors[url] = Monitor{URL: url, Interval: interval}
-    stopChan := make(chan struct{})
-    um.stopChannels[url] = stopChan
+    um.startLocked(monitor)
+    slog.Info("Monitor added", "url", monitor.URL, "interval", monitor.Interval.String())
+    return nil
+}
 
-    go um.monitorURL(url, interval, stopChan)
+// normalizeMonitor applies defaults and validates a monitor's configuration
+func normalizeMonitor(monitor *Monitor) error {
+    normalized, err := normalizeURL(monitor.URL)
+    if err != nil {
+        return err
+    }
+    monitor.URL = normalized
+    for i, dependency := range monitor.DependsOn {
+        if monitor.DependsOn[i], err = normalizeURL(dependency); err != nil {
+            return fmt.Errorf("dependsOn: %w", err)
+        }
+    }
+    if monitor.Interval == 0 {
+        monitor.Interval = 30 * time.Second
+    }
+    if monitor.Interval < minInterval {
+        return fmt.Errorf("interval must be at least %s", minInterval)
+    }
+    switch monitor.Type {
+    case "", MonitorTypeHTTP:
+        monitor.Type = MonitorTypeHTTP
+        if !strings.HasPrefix(monitor.URL, "http://") && !strings.HasPrefix(monitor.URL, "https://") {
+            return fmt.Errorf("HTTP monitor requires an http:// or https:// URL")
+        }
+    case MonitorTypeFTP:
+        if !strings.HasPrefix(monitor.URL, "ftp://") && !strings.HasPrefix(monitor.URL, "ftps://") {
+            return fmt.Errorf("FTP monitor requires an ftp:// or ftps:// URL")
+        }
+    case MonitorTypeDNS:
+        if !strings.HasPrefix(monitor.URL, "dns://") {
+            return fmt.Errorf("DNS monitor requires a dns://hostname URL")
+        }
+    case MonitorTypeHTTP3:
+        if !strings.HasPrefix(monitor.URL, "https://") {
+            return fmt.Errorf("HTTP/3 monitor requires an https:// URL")
+        }
+    case MonitorTypeTCP:
+        if err := validateTCP(*monitor); err != nil {
+            return err
+        }
+    default:
+        return fmt.Errorf("unknown monitor type %q", monitor.Type)
+    }
+    if monitor.Retries < 0 {
+        return fmt.Errorf("retries must not be negative")
+    }
+    if monitor.RecoverySuccesses < 0 || monitor.RecoveryCooldown < 0 {
+        return fmt.Errorf("recoverySuccesses and recoveryCooldown must not be negative")
+    }
+    if monitor.AutoRemoveAfter < 0 {
+        return fmt.Errorf("autoRemoveAfter must not be negative")
+    }
+    if monitor.ExpectedStatus != 0 && (monitor.ExpectedStatus < 100 || monitor.ExpectedStatus > 599) {
+        return fmt.Errorf("expectedStatus %d is not a valid HTTP status code", monitor.ExpectedStatus)
+    }
+    if monitor.AcceptStatus != "" {
+        if monitor.ExpectedStatus != 0 {
+            return fmt.Errorf("expectedStatus and acceptStatus are mutually exclusive")
+        }
+        if _, err := parseStatusRanges(monitor.AcceptStatus); err != nil {
+            return err
+        }
+    }
+    if monitor.CertExpiryThreshold < 0 {
+        return fmt.Errorf("certExpiryThreshold must not be negative")
+    }
+    if monitor.MaxRedirects < 0 {
+        return fmt.Errorf("maxRedirects must not be negative")
+    }
+    if monitor.Timeout < 0 {
+        return fmt.Errorf("timeout must not be negative")
+    }
+    if monitor.CheckBudget < 0 {
+        return fmt.Errorf("checkBudget must not be negative")
+    }
+    if monitor.MinTLSVersion != "" {
+        if _, err := parseTLSVersion(monitor.MinTLSVersion); err != nil {
+            return err
+        }
+    }
+    if monitor.Method != "" {
+        monitor.Method = strings.ToUpper(monitor.Method)
+        if !checkMethods[monitor.Method] {
+            return fmt.Errorf("unsupported method %q", monitor.Method)
+        }
+    }
+    if monitor.EchoMode != "" && monitor.EchoMode != EchoExact && monitor.EchoMode != EchoContains {
+        return fmt.Errorf("echoMode must be %q or %q", EchoExact, EchoContains)
+    }
+    for _, expr := range monitor.JSONAssertions {
+        if _, err := parseJSONAssertion(expr); err != nil {
+            return err
+        }
+    }
+    if err := validateAuth(*monitor); err != nil {
+        return err
+    }
+    if err := validateDependencies(*monitor); err != nil {
+        return err
+    }
+    if err := validateUpload(*monitor); err != nil {
+        return err
+    }
+    if err := validateCache(*monitor); err != nil {
+        return err
+    }
+    for _, assertion := range monitor.ArrayLengths {
+        if err := assertion.validate(); err != nil {
+            return err
+        }
+    }
+    if monitor.CertFingerprint != "" {
+        if _, err := parseFingerprint(monitor.CertFingerprint); err != nil {
+            return err
+        }
+    }
+    if monitor.SigV4 != nil {
+        if err := monitor.SigV4.validate(); err != nil {
+            return err
+        }
+    }
+    if err := validatePOP(*monitor); err != nil {
+        return err
+    }
+    if err := validateBodyChecksum(*monitor); err != nil {
+        return err
+    }
+    if err := validateMessageTemplate(monitor.MessageTemplate); err != nil {
+        return err
+    }
+    if err := validatePorts(*monitor); err != nil {
+        return err
+    }
+    if err := validateLocales(*monitor); err != nil {
+        return err
+    }
+    if monitor.TLSProfile != nil {
+        if err := monitor.TLSProfile.validate(); err != nil {
+            return err
+        }
+    }
     return nil
 }
 
+// startLocked registers a monitor and launches its goroutine unless it is paused; callers must hold um.mu
+func (um *UptimeMonitor) startLocked(monitor Monitor) {
+    um.monitors[monitor.URL] = monitor
+    if monitor.Paused {
+        return
+    }
+    stopChan := make(chan struct{})
+    um.stopChannels[monitor.URL] = stopChan
+
+    go um.monitorURL(monitor, stopChan)
+}
+
 func (um *UptimeMonitor) RemoveMonitor(url string) error {
+    url = monitorKey(url)
+
     um.mu.Lock()
     defer um.mu.Unlock()
 
-    if stopChan, exists := um.stopChannels[url]; exists {
+    if _, archived := um.archived[url]; archived {
+        delete(um.archived, url)
+        slog.Info("Monitor removed", "url", url, "archived", true)
+        return nil
+    }
+    if err := um.removeLocked(url); err != nil {
+        return err
+    }
+    slog.Info("Monitor removed", "url", url)
+    return nil
+}
+
+// UpdateInterval restarts a monitor's ticker with a new interval, keeping its logs and downtimes
+func (um *UptimeMonitor) UpdateInterval(url string, interval time.Duration) error {
+    url = monitorKey(url)
+    if interval < minInterval {
+        return fmt.Errorf("interval must be at least %s", minInterval)
+    }
+
+    um.mu.Lock()
+    defer um.mu.Unlock()
+
+    monitor, exists := um.monitors[url]
+    if !exists {
+        return fmt.Errorf("URL %s is not being monitored", url)
+    }
+    um.stopLocked(url)
+    monitor.Interval = interval
+    um.startLocked(monitor)
+    return nil
+}
+
+// removeLocked stops a monitor's goroutine and forgets its configuration; callers must hold um.mu
+func (um *UptimeMonitor) removeLocked(url string) error {
+    if _, exists := um.monitors[url]; !exists {
+        return fmt.Errorf("URL %s is not being monitored", url)
+    }
+    um.stopLocked(url)
+    delete(um.monitors, url)
+    return nil
+}
+
+// stopLocked stops a monitor's goroutine if one is running; callers must hold um.mu
+func (um *UptimeMonitor) stopLocked(url string) {
+    if stopChan, running := um.stopChannels[url]; running {
         close(stopChan)
         delete(um.stopChannels, url)
-        delete(um.monitors, url)
-        return nil
     }
-    return fmt.Errorf("URL %s is not being monitored", url)
 }
 
-func (um *UptimeMonitor) monitorURL(url string, interval time.Duration, stop chan struct{}) {
-    ticker := time.NewTicker(interval)
+// ListMonitors returns a snapshot of the active and paused monitors sorted by URL
+func (um *UptimeMonitor) ListMonitors() []Monitor {
+    um.mu.RLock()
+    defer um.mu.RUnlock()
+
+    monitors := make([]Monitor, 0, len(um.monitors))
+    for _, monitor := range um.monitors {
+        monitors = append(monitors, monitor)
+    }
+    sort.Slice(monitors, func(i, j int) bool {
+        return monitors[i].URL < monitors[j].URL
+    })
+    return monitors
+}
+
+func (um *UptimeMonitor) monitorURL(monitor Monitor, stop chan struct{}) {
+    atomic.AddInt64(&um.activeLoops, 1)
+    defer atomic.AddInt64(&um.activeLoops, -1)
+
+    // Checks begin only once dependencies are ready
+    select {
+    case <-stop:
+        return
+    case <-um.ready:
+    }
+
+    ticker := time.NewTicker(monitor.Interval)
     defer ticker.Stop()
 
     for {
@@ -71,180 +428,815 @@ func (um *UptimeMonitor) monitorURL(url string, interval time.Duration, stop cha
         case <-stop:
             return
         case <-ticker.C:
-            um.checkURL(url)
+            done := um.beginCheck(monitor.URL)
+            um.checkURL(monitor)
+            done()
+        }
+    }
+}
+
+// beginCheck counts a check of url as in flight and returns the function ending it
+func (um *UptimeMonitor) beginCheck(url string) (done func()) {
+    um.mu.Lock()
+    defer um.mu.Unlock()
+
+    um.checking[url]++
+    um.inFlight++
+    return func() {
+        um.mu.Lock()
+        defer um.mu.Unlock()
+
+        um.inFlight--
+        if um.checking[url]--; um.checking[url] <= 0 {
+            delete(um.checking, url)
+        }
+    }
+}
+
+// checkURL runs one check of the monitor, records it and returns the result. A failed check
+// is retried up to monitor.Retries times unless its failure is not retryable; superseded
+// attempts are logged but only the final one counts towards stats and downtimes.
+func (um *UptimeMonitor) checkURL(monitor Monitor) LogEntry {
+    ctx, span := um.startCheckSpan(monitor)
+    defer span.End()
+
+    // The budget bounds every attempt, the pauses between them and the body transfer
+    if monitor.CheckBudget > 0 {
+        var cancel context.CancelFunc
+        ctx, cancel = context.WithTimeout(ctx, monitor.CheckBudget)
+        defer cancel()
+    }
+
+    entry := um.attemptCheck(ctx, monitor)
+    for attempt := 1; !entry.Success && attempt <= monitor.Retries && isRetryable(monitor, entry); attempt++ {
+        backoff := um.retryBackoff()
+        if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+            entry.Error = fmt.Sprintf("check budget of %s exhausted after %d attempts: %s", monitor.CheckBudget, attempt, entry.Error)
+            break
         }
+        entry.Attempt = attempt
+        um.recordAttempt(entry)
+        time.Sleep(backoff)
+
+        entry = um.attemptCheck(ctx, monitor)
+        entry.Attempt = attempt + 1
+    }
+
+    entry = traceResult(span, entry)
+    um.recordCheck(entry)
+    return entry
+}
+
+// attemptCheck performs a single attempt of the monitor's check. Every request holds a check
+// slot, so a check of several ports or locales takes one per port or locale.
+func (um *UptimeMonitor) attemptCheck(ctx context.Context, monitor Monitor) LogEntry {
+    switch {
+    case monitor.Type == MonitorTypeFTP:
+        return um.withCheckSlot(ctx, monitor, um.checkFTP)
+    case monitor.Type == MonitorTypeDNS:
+        return um.withCheckSlot(ctx, monitor, um.checkDNS)
+    case monitor.Type == MonitorTypeHTTP3:
+        return um.withCheckSlot(ctx, monitor, um.checkHTTP3)
+    case monitor.Type == MonitorTypeTCP:
+        return um.withCheckSlot(ctx, monitor, um.checkTCP)
+    case len(monitor.Ports) > 0:
+        return um.checkPorts(ctx, monitor)
+    case len(monitor.Locales) > 0:
+        return um.checkLocales(ctx, monitor)
+    default:
+        return um.withCheckSlot(ctx, monitor, um.checkHTTP)
     }
 }
 
-func (um *UptimeMonitor) checkURL(url string) {
-    start := time.Now()
-    resp, err := um.client.Get(url)
-    responseTime := time.Since(start).Milliseconds()
+// checkHTTP performs one HTTP request of the monitor's URL and applies the response assertions
+func (um *UptimeMonitor) checkHTTP(ctx context.Context, monitor Monitor) LogEntry {
+    url := monitor.URL
+
+    // The deadline also covers reading the body below
+    ctx, cancel := context.WithTimeout(ctx, monitor.timeout())
+    defer cancel()
+
+    trace := newCheckTrace()
+    resp, err := um.doCheckRequest(ctx, monitor, trace)
+
+    // The response time covers the body transfer, so read it before stopping the clock
+    var body []byte
+    var partial *http.Response
+    if err == nil {
+        defer resp.Body.Close()
+        body, err = io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+        if err != nil {
+            // The status line and headers arrived but the body did not complete
+            partial = resp
+            err = fmt.Errorf("reading response body after %d bytes: %w", len(body), err)
+        }
+    }
+    responseTime := time.Since(trace.start).Milliseconds()
 
     entry := LogEntry{
-        Timestamp:    time.Now(),
+        Timestamp:    um.now(),
         URL:          url,
         ResponseTime: responseTime,
+        TTFB:         trace.firstByte.Milliseconds(),
+        RemoteAddr:   trace.remoteAddr,
+    }
+    if resp != nil {
+        entry.BytesSent = requestSize(resp.Request)
+        entry.BytesReceived = responseSize(resp, int64(len(body)))
+        if um.samples != nil {
+            um.samples.record(url, resp.StatusCode, body, entry.Timestamp)
+        }
+    }
+    if monitor.UploadBytes > 0 && trace.wroteRequest > 0 {
+        entry.UploadBytes = monitor.UploadBytes
+        entry.UploadThroughput = uploadThroughput(monitor.UploadBytes, trace.wroteRequest-trace.connected)
     }
 
     if err != nil {
         entry.Success = false
         entry.Error = err.Error()
-        um.handleFailure(entry)
-        return
+        entry.ErrorClass = classifyError(err)
+        if partial != nil {
+            recordPartialResponse(&entry, partial)
+        }
+        return entry
     }
-    defer resp.Body.Close()
 
     entry.StatusCode = resp.StatusCode
-    entry.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+    entry.Success = monitor.statusOK(resp.StatusCode)
+    if monitor.FailOnRedirect && resp.StatusCode >= 300 && resp.StatusCode < 400 {
+        entry.Error = fmt.Sprintf("unexpected redirect to %s", resp.Header.Get("Location"))
+    }
+
+    if finalURL := resp.Request.URL.String(); finalURL != url {
+        entry.FinalURL = finalURL
+    }
+    entry.Redirects = redirectCount(resp)
+    if monitor.AcceptLanguage != "" {
+        entry.ContentLanguage = resp.Header.Get("Content-Language")
+    }
+    if monitor.MaxRedirects > 0 && entry.Redirects > monitor.MaxRedirects {
+        err := fmt.Errorf("took more than %d redirects", monitor.MaxRedirects)
+        if monitor.WarnOnRedirects {
+            entry.Warnings = append(entry.Warnings, err.Error())
+        } else {
+            entry.Success = false
+            entry.Error = err.Error()
+        }
+    }
+    entry.POP = edgePOP(monitor, resp.Header)
+    recordTLS(&entry, resp.TLS)
+    if entry.Success {
+        if err := verifyResponse(monitor, resp, body, &entry); err != nil {
+            entry.Success = false
+            entry.Error = err.Error()
+        }
+    }
+    return entry
+}
+
+// recordAttempt logs a failed attempt that is about to be retried. It does not count
+// towards stats or open a downtime, as the retry decides the outcome of the check.
+func (um *UptimeMonitor) recordAttempt(entry LogEntry) {
+    entry.Retried = true
+    um.emitResult(entry)
 
     um.mu.Lock()
-    um.logs = append(um.logs, entry)
-    um.mu.Unlock()
+    defer um.mu.Unlock()
+    um.retainLogLocked(entry)
+}
+
+// recordCheck publishes a finished check and updates downtime tracking. It is the single
+// place check results are logged. Locking discipline: it is called without um.mu held, and
+// handleFailure and handleSuccess each log the entry and update the downtimes within one
+// critical section, so concurrent checks of a URL cannot interleave between the two steps.
+func (um *UptimeMonitor) recordCheck(entry LogEntry) {
+    um.emitResult(entry)
+    level := slog.LevelDebug
+    if !entry.Success {
+        level = slog.LevelWarn
+    }
+    slog.Log(context.Background(), level, "Check finished", "url", entry.URL, "success", entry.Success,
+        "status", entry.StatusCode, "responseTime", entry.ResponseTime, "error", entry.Error)
 
     if !entry.Success {
         um.handleFailure(entry)
-    } else {
-        um.handleSuccess(url)
+        return
+    }
+    um.handleSuccess(entry)
+}
+
+// retryBackoff returns the pause before the next attempt of a check. Random jitter keeps
+// monitors that failed together from retrying in lockstep against a recovering service.
+func (um *UptimeMonitor) retryBackoff() time.Duration {
+    if um.retryJitter <= 0 {
+        return retryDelay
+    }
+
+    um.randMu.Lock()
+    defer um.randMu.Unlock()
+    return retryDelay + time.Duration(um.rand.Int63n(int64(um.retryJitter)))
+}
+
+// recordPartialResponse keeps whatever was received before a check failed mid-response
+func recordPartialResponse(entry *LogEntry, resp *http.Response) {
+    entry.Partial = true
+    entry.StatusCode = resp.StatusCode
+    entry.Headers = make(map[string]string, len(resp.Header))
+    for name, values := range resp.Header {
+        entry.Headers[name] = strings.Join(values, ", ")
+    }
+}
+
+// doCheckRequest builds and sends the HTTP request for a single check attempt
+func (um *UptimeMonitor) doCheckRequest(ctx context.Context, monitor Monitor, trace *checkTrace) (*http.Response, error) {
+    req, err := um.newCheckRequest(ctx, monitor)
+    if err != nil {
+        return nil, err
     }
+    return um.clientFor(monitor).Do(trace.attach(req))
 }
 
+// newCheckRequest builds the (signed) request a check sends to the monitored URL
+func (um *UptimeMonitor) newCheckRequest(ctx context.Context, monitor Monitor) (*http.Request, error) {
+    method := http.MethodGet
+    var body io.Reader
+    if monitor.Body != "" {
+        method = http.MethodPost
+        body = strings.NewReader(monitor.Body)
+    }
+    if monitor.UploadBytes > 0 {
+        method = http.MethodPost
+    }
+    if monitor.Method != "" {
+        method = monitor.Method
+    }
+
+    req, err := http.NewRequestWithContext(ctx, method, monitor.URL, body)
+    if err != nil {
+        return nil, err
+    }
+    if monitor.UploadBytes > 0 {
+        req.Body = newUploadBody(monitor.UploadBytes)
+        req.ContentLength = monitor.UploadBytes
+        req.GetBody = func() (io.ReadCloser, error) {
+            return newUploadBody(monitor.UploadBytes), nil
+        }
+    }
+    for name, value := range monitor.Headers {
+        req.Header.Set(name, value)
+    }
+    setAuth(req, monitor)
+    if monitor.HostHeader != "" {
+        req.Host = monitor.HostHeader
+    }
+    for name, value := range monitor.NegotiationHeaders {
+        req.Header.Set(name, value)
+    }
+    if monitor.AcceptLanguage != "" {
+        req.Header.Set("Accept-Language", monitor.AcceptLanguage)
+    }
+    if monitor.VerifyEncoding {
+        req.Header.Set("Accept-Encoding", acceptedEncodings)
+    }
+    if monitor.SigV4 != nil {
+        // Generated upload bodies are streamed, so their hash is not computed up front
+        payloadHash := sha256Hex(monitor.Body)
+        if monitor.UploadBytes > 0 {
+            payloadHash = sigV4UnsignedPayload
+        }
+        signSigV4(req, monitor.SigV4, payloadHash, um.now())
+    }
+    return req, nil
+}
+
+// clientFor returns the HTTP client honoring the monitor's redirect policy
+func (um *UptimeMonitor) clientFor(monitor Monitor) *http.Client {
+    capRedirects := monitor.MaxRedirects > 0 && !monitor.WarnOnRedirects
+    if !monitor.stopsAtRedirect() && !capRedirects && monitor.TLSProfile == nil && !monitor.InsecureSkipVerify {
+        return um.client
+    }
+
+    // A shallow copy shares the transport and its connection pool
+    client := *um.client
+    if monitor.stopsAtRedirect() {
+        client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+            return http.ErrUseLastResponse
+        }
+    } else if capRedirects {
+        // Follow one redirect past the limit, enough to tell it was exceeded, and stop there
+        client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+            if len(via) > monitor.MaxRedirects+1 {
+                return http.ErrUseLastResponse
+            }
+            return nil
+        }
+    }
+    if monitor.InsecureSkipVerify {
+        client.Transport = um.insecureTransportFor()
+    }
+    if monitor.TLSProfile != nil {
+        // The profile was validated when the monitor was added
+        if transport, err := profileTransport(client.Transport, monitor.TLSProfile); err == nil {
+            client.Transport = transport
+        }
+    }
+    return &client
+}
+
+// redirectCount returns how many redirects were followed to obtain resp
+func redirectCount(resp *http.Response) int {
+    n := 0
+    for req := resp.Request; req != nil && req.Response != nil; req = req.Response.Request {
+        n++
+    }
+    return n
+}
+
+// verifyResponse applies the monitor's assertions to a response whose status already counts as success.
+// body holds at most maxBodyBytes of the response body. Assertions configured to warn rather than fail
+// record their findings in entry.Warnings.
+func verifyResponse(monitor Monitor, resp *http.Response, body []byte, entry *LogEntry) error {
+    if err := checkTLSPolicy(monitor, resp.TLS); err != nil {
+        return err
+    }
+    if err := checkCertPin(monitor, resp, entry); err != nil {
+        return err
+    }
+    if err := checkKeySize(monitor, resp.TLS); err != nil {
+        if !monitor.WarnOnWeakKey {
+            return err
+        }
+        entry.Warnings = append(entry.Warnings, err.Error())
+    }
+    if err := checkCertExpiry(monitor, resp.TLS, entry); err != nil {
+        if !monitor.WarnOnCertExpiry {
+            return err
+        }
+        entry.Warnings = append(entry.Warnings, err.Error())
+    }
+    if monitor.RequireHTTPS && resp.Request.URL.Scheme != "https" {
+        return fmt.Errorf("final URL %s is not served over https", resp.Request.URL)
+    }
+    if missing := missingHeaders(monitor.RequiredHeaders, resp.Header); len(missing) > 0 {
+        entry.MissingHeaders = missing
+        err := fmt.Errorf("missing required response headers: %s", strings.Join(missing, ", "))
+        if !monitor.WarnOnMissingHeaders {
+            return err
+        }
+        entry.Warnings = append(entry.Warnings, err.Error())
+    }
+
+    if err := checkCache(monitor, resp.Header.Get(monitor.CacheHeader), entry); err != nil {
+        return err
+    }
+
+    if monitor.VerifyEncoding {
+        entry.ContentEncoding = resp.Header.Get("Content-Encoding")
+        decoded, err := decodeContentEncoding(entry.ContentEncoding, body)
+        if err != nil {
+            return err
+        }
+        body = decoded
+    }
+
+    if monitor.ExpectedBody != "" && !bytes.Contains(body, []byte(monitor.ExpectedBody)) {
+        return fmt.Errorf("expected body substring %q not found", monitor.ExpectedBody)
+    }
+    if err := checkBodyChecksum(monitor, body, entry); err != nil {
+        return err
+    }
+    if len(monitor.JSONAssertions) > 0 {
+        if err := checkJSONAssertions(monitor.JSONAssertions, body); err != nil {
+            return err
+        }
+    }
+    if len(monitor.ArrayLengths) > 0 {
+        if err := checkArrayLengths(monitor.ArrayLengths, body); err != nil {
+            return err
+        }
+    }
+    if err := checkEcho(monitor, body); err != nil {
+        return err
+    }
+    return nil
+}
+
+// missingHeaders lists the required headers absent from the response
+func missingHeaders(required []string, header http.Header) []string {
+    var missing []string
+    for _, name := range required {
+        if header.Get(name) == "" {
+            missing = append(missing, name)
+        }
+    }
+    return missing
+}
+
+// checkEcho verifies that an echo service returned the request body
+func checkEcho(monitor Monitor, body []byte) error {
+    switch monitor.EchoMode {
+    case "":
+        return nil
+    case EchoExact:
+        if string(body) != monitor.Body {
+            return fmt.Errorf("response body does not echo the request body")
+        }
+    case EchoContains:
+        if !strings.Contains(string(body), monitor.Body) {
+            return fmt.Errorf("response body does not contain the request body")
+        }
+    }
+    return nil
+}
+
+// handleFailure logs a failed check and opens or extends its downtime. Remote agents are asked
+// to confirm a new downtime before um.mu is taken; the downtime state is then read again under
+// the lock, as another check may have changed it meanwhile.
 func (um *UptimeMonitor) handleFailure(entry LogEntry) {
+    // A failure that would open a downtime is first confirmed from the remote agents,
+    // without holding the lock during their checks
+    um.mu.RLock()
+    monitor := um.monitors[entry.URL]
+    previous := um.getLastDowntime(entry.URL)
+    opening := previous == nil || !previous.EndTime.IsZero()
+    um.mu.RUnlock()
+    if opening && monitor.ConfirmWithAgents && len(um.agents) > 0 && !um.confirmFailure(monitor, &entry) {
+        um.mu.Lock()
+        um.appendLogLocked(entry)
+        um.mu.Unlock()
+        return
+    }
+
     um.mu.Lock()
     defer um.mu.Unlock()
 
-    um.logs = append(um.logs, entry)
-    
+    um.appendLogLocked(entry)
+
     // Check if there's an ongoing downtime
     lastDowntime := um.getLastDowntime(entry.URL)
-    if lastDowntime == nil || !lastDowntime.EndTime.IsZero() {
+    if lastDowntime != nil && !lastDowntime.EndTime.IsZero() && um.mergeGap > 0 &&
+        entry.Timestamp.Sub(lastDowntime.EndTime) <= um.mergeGap {
+        // The URL recovered only briefly, treat this as the same incident
+        lastDowntime.EndTime = time.Time{}
+        lastDowntime.Duration = ""
+        slog.Warn("Downtime reopened", "url", entry.URL, "status", entry.StatusCode, "error", entry.Error)
+        if um.notifyDownLocked(entry) && um.incidents != nil {
+            // The external incident was resolved on recovery, so open a fresh one
+            go um.openIncident(*lastDowntime)
+        }
+    } else if lastDowntime == nil || !lastDowntime.EndTime.IsZero() {
         // Start new downtime
-        um.downtimes = append(um.downtimes, DowntimeEntry{
-            URL:         entry.URL,
-            StartTime:   entry.Timestamp,
-            StatusCode:  entry.StatusCode,
-            ErrorDetail: entry.Error,
-        })
+        downtime := DowntimeEntry{
+            URL:          entry.URL,
+            StartTime:    entry.Timestamp,
+            StatusCode:   entry.StatusCode,
+            ErrorDetail:  entry.Error,
+            UpstreamDown: um.downDependenciesLocked(entry.URL),
+        }
+        um.appendDowntimeLocked(downtime)
+        slog.Warn("Downtime started", "url", entry.URL, "status", entry.StatusCode, "error", entry.Error)
+        // Incidents follow alerts: none is opened when the down alert was suppressed
+        if um.notifyDownLocked(entry) && um.incidents != nil {
+            go um.openIncident(downtime)
+        }
+        return
+    }
+
+    um.autoRemoveLocked(entry, lastDowntime)
+}
+
+// autoRemoveLocked removes a monitor whose downtime has stayed open longer than its
+// AutoRemoveAfter threshold and sends a final notification; callers must hold um.mu
+func (um *UptimeMonitor) autoRemoveLocked(entry LogEntry, downtime *DowntimeEntry) {
+    monitor, exists := um.monitors[entry.URL]
+    if !exists || monitor.AutoRemoveAfter <= 0 {
+        return
+    }
+
+    openFor := entry.Timestamp.Sub(downtime.StartTime)
+    if openFor < monitor.AutoRemoveAfter {
+        return
     }
+
+    // Notify first, the monitor's message template is gone once it is removed
+    um.notifyLocked(DowntimeEvent{
+        Type:       EventRemoved,
+        URL:        entry.URL,
+        Timestamp:  entry.Timestamp,
+        StatusCode: entry.StatusCode,
+        Error:      entry.Error,
+        Duration:   openFor.String(),
+    })
+    um.removeLocked(entry.URL)
+    slog.Warn("Monitor removed", "url", entry.URL, "reason", "down for "+openFor.String())
 }
 
-func (um *UptimeMonitor) handleSuccess(url string) {
+// notifyDownLocked announces that entry's failure opened a downtime and reports whether the
+// alert was sent; callers must hold um.mu
+func (um *UptimeMonitor) notifyDownLocked(entry LogEntry) bool {
+    return um.notifyLocked(DowntimeEvent{
+        Type:       EventDown,
+        URL:        entry.URL,
+        Timestamp:  entry.Timestamp,
+        StatusCode: entry.StatusCode,
+        Error:      entry.Error,
+    })
+}
+
+// handleSuccess logs a successful check and closes the open downtime once the URL has recovered
+func (um *UptimeMonitor) handleSuccess(entry LogEntry) {
     um.mu.Lock()
     defer um.mu.Unlock()
 
-    lastDowntime := um.getLastDowntime(url)
+    um.appendLogLocked(entry)
+    lastDowntime := um.getLastDowntime(entry.URL)
     if lastDowntime != nil && lastDowntime.EndTime.IsZero() {
-        lastDowntime.EndTime = time.Now()
+        if !um.recoveredLocked(entry.URL) {
+            return
+        }
+        lastDowntime.EndTime = um.now()
         lastDowntime.Duration = lastDowntime.EndTime.Sub(lastDowntime.StartTime).String()
+        slog.Info("Downtime ended", "url", entry.URL, "status", entry.StatusCode, "duration", lastDowntime.Duration)
+        um.notifyLocked(DowntimeEvent{
+            Type:       EventUp,
+            URL:        entry.URL,
+            Timestamp:  lastDowntime.EndTime,
+            StatusCode: entry.StatusCode,
+            Duration:   lastDowntime.Duration,
+        })
+        if um.incidents != nil && lastDowntime.IncidentID != "" {
+            go um.resolveIncident(*lastDowntime)
+        }
     }
 }
 
+// recoveredLocked reports whether the URL's success streak is long enough to close its
+// downtime under the monitor's recovery settings; callers must hold um.mu
+func (um *UptimeMonitor) recoveredLocked(url string) bool {
+    monitor := um.monitors[url]
+    state := um.stateLocked(url)
+    if state.successStreak < monitor.RecoverySuccesses {
+        return false
+    }
+    return monitor.RecoveryCooldown <= 0 || um.now().Sub(state.streakStart) >= monitor.RecoveryCooldown
+}
+
+// getLastDowntime returns the URL's most recent downtime, or nil; callers must hold um.mu
 func (um *UptimeMonitor) getLastDowntime(url string) *DowntimeEntry {
-    for i := len(um.downtimes) - 1; i >= 0; i-- {
-        if um.downtimes[i].URL == url {
-            return &um.downtimes[i]
-        }
+    downtimes := um.downtimes[url]
+    if len(downtimes) == 0 {
+        return nil
     }
-    return nil
+    return &downtimes[len(downtimes)-1]
 }
 
 func (um *UptimeMonitor) GetLogs(url string) []LogEntry {
+    url = monitorKey(url)
+
     um.mu.RLock()
     defer um.mu.RUnlock()
 
-    var urlLogs []LogEntry
-    for _, log := range um.logs {
-        if log.URL == url {
-            urlLogs = append(urlLogs, log)
+    return append([]LogEntry(nil), um.logs[url]...)
+}
+
+// GetLogsFiltered returns a page of the URL's logs within [since, until], oldest first, along
+// with the number of entries in the window. A zero bound is open. Paging starts from the newest
+// entry: offset skips that many of the most recent entries and limit caps the page, zero
+// meaning no limit, so without an offset the most recent limit entries are returned.
+func (um *UptimeMonitor) GetLogsFiltered(url string, since, until time.Time, limit, offset int) ([]LogEntry, int) {
+    url = monitorKey(url)
+
+    um.mu.RLock()
+    defer um.mu.RUnlock()
+
+    var window []LogEntry
+    for _, log := range um.logs[url] {
+        if !since.IsZero() && log.Timestamp.Before(since) || !until.IsZero() && log.Timestamp.After(until) {
+            continue
         }
+        window = append(window, log)
     }
-    return urlLogs
+
+    total := len(window)
+    end := max(total-offset, 0)
+    start := 0
+    if limit > 0 {
+        start = max(end-limit, 0)
+    }
+    return window[start:end], total
 }
 
+// GetDowntimes returns the URL's downtimes, oldest first; ongoing ones report their duration so far
 func (um *UptimeMonitor) GetDowntimes(url string) []DowntimeEntry {
+    url = monitorKey(url)
+
     um.mu.RLock()
     defer um.mu.RUnlock()
 
-    var urlDowntimes []DowntimeEntry
-    for _, downtime := range um.downtimes {
-        if downtime.URL == url {
-            urlDowntimes = append(urlDowntimes, downtime)
+    downtimes := append([]DowntimeEntry(nil), um.downtimes[url]...)
+    now := um.now()
+    for i := range downtimes {
+        if downtimes[i].EndTime.IsZero() {
+            // Only the copy gets the live duration, the stored entry stays open
+            downtimes[i].Ongoing = true
+            downtimes[i].Duration = now.Sub(downtimes[i].StartTime).String()
         }
     }
-    return urlDowntimes
+    return downtimes
 }
 
 // HTTP handlers
+
+// parseTimeParam reads an optional RFC3339 query parameter, returning the zero time when absent
+func parseTimeParam(r *http.Request, name string) (time.Time, error) {
+    value := r.URL.Query().Get(name)
+    if value == "" {
+        return time.Time{}, nil
+    }
+    t, err := time.Parse(time.RFC3339, value)
+    if err != nil {
+        return time.Time{}, fmt.Errorf("%s must be an RFC3339 timestamp", name)
+    }
+    return t, nil
+}
+
+// parseCountParam reads an optional non-negative integer query parameter, returning 0 when absent
+func parseCountParam(r *http.Request, name string) (int, error) {
+    value := r.URL.Query().Get(name)
+    if value == "" {
+        return 0, nil
+    }
+    n, err := strconv.Atoi(value)
+    if err != nil || n < 0 {
+        return 0, fmt.Errorf("%s must be a non-negative integer", name)
+    }
+    return n, nil
+}
+
 func (um *UptimeMonitor) HandleAddMonitor(w http.ResponseWriter, r *http.Request) {
     if r.Method != http.MethodPost {
-        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
         return
     }
 
-    var req struct {
-        URL      string `json:"url"`
-        Interval int    `json:"interval,omitempty"`
+    var req MonitorConfig
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeError(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+
+    if err := um.AddMonitorConfig(req.ToMonitor()); err != nil {
+        if errors.Is(err, ErrSaturated) {
+            w.Header().Set("Retry-After", strconv.Itoa(int(saturatedRetryAfter/time.Second)))
+            writeError(w, err.Error(), http.StatusServiceUnavailable)
+            return
+        }
+        writeError(w, err.Error(), http.StatusBadRequest)
+        return
     }
 
+    if req.InsecureSkipVerify {
+        slog.Warn("Monitor added with TLS certificate verification disabled", "url", req.URL)
+        w.Header().Set("Warning", insecureWarning)
+    }
+    w.WriteHeader(http.StatusCreated)
+}
+
+func (um *UptimeMonitor) HandleUpdateMonitor(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodPatch {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    var req struct {
+        URL      string         `json:"url"`
+        Interval ConfigDuration `json:"interval"`
+    }
     if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-        http.Error(w, err.Error(), http.StatusBadRequest)
+        writeError(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    if req.URL == "" {
+        writeError(w, "URL parameter is required", http.StatusBadRequest)
         return
     }
 
-    interval := time.Duration(req.Interval) * time.Second
-    if err := um.AddMonitor(req.URL, interval); err != nil {
-        http.Error(w, err.Error(), http.StatusBadRequest)
+    if err := um.UpdateInterval(req.URL, time.Duration(req.Interval)); err != nil {
+        status := http.StatusBadRequest
+        if time.Duration(req.Interval) >= minInterval {
+            status = http.StatusNotFound
+        }
+        writeError(w, err.Error(), status)
         return
     }
 
-    w.WriteHeader(http.StatusCreated)
+    w.WriteHeader(http.StatusOK)
 }
 
 func (um *UptimeMonitor) HandleRemoveMonitor(w http.ResponseWriter, r *http.Request) {
     if r.Method != http.MethodDelete {
-        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
         return
     }
 
     url := r.URL.Query().Get("url")
     if url == "" {
-        http.Error(w, "URL parameter is required", http.StatusBadRequest)
+        writeError(w, "URL parameter is required", http.StatusBadRequest)
         return
     }
 
     if err := um.RemoveMonitor(url); err != nil {
-        http.Error(w, err.Error(), http.StatusNotFound)
+        writeError(w, err.Error(), http.StatusNotFound)
         return
     }
 
     w.WriteHeader(http.StatusOK)
 }
 
+// HandleListMonitors lists the active and archived monitors, sorted by URL, in the format
+// accepted by the add endpoint. Archived monitors are flagged with archived: true, and
+// credentials are redacted as in /monitor/export.
+func (um *UptimeMonitor) HandleListMonitors(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    monitors := um.ListMonitors()
+    archived := um.GetArchivedMonitors()
+    configs := make([]MonitorConfig, 0, len(monitors)+len(archived))
+    for _, monitor := range monitors {
+        configs = append(configs, redactSecrets(monitor).Config())
+    }
+    for _, monitor := range archived {
+        config := redactSecrets(monitor).Config()
+        config.Archived = true
+        configs = append(configs, config)
+    }
+    sort.Slice(configs, func(i, j int) bool {
+        return configs[i].URL < configs[j].URL
+    })
+    json.NewEncoder(w).Encode(configs)
+}
+
 func (um *UptimeMonitor) HandleGetLogs(w http.ResponseWriter, r *http.Request) {
     if r.Method != http.MethodGet {
-        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    logs, ok := um.queryLogs(w, r)
+    if !ok {
         return
     }
+    json.NewEncoder(w).Encode(logs)
+}
 
+// queryLogs serves the url, since, until, limit and offset parameters shared by the log endpoints.
+// It sets X-Total-Count, or writes the error response and returns false.
+func (um *UptimeMonitor) queryLogs(w http.ResponseWriter, r *http.Request) ([]LogEntry, bool) {
     url := r.URL.Query().Get("url")
     if url == "" {
-        http.Error(w, "URL parameter is required", http.StatusBadRequest)
-        return
+        writeError(w, "URL parameter is required", http.StatusBadRequest)
+        return nil, false
     }
 
-    logs := um.GetLogs(url)
-    json.NewEncoder(w).Encode(logs)
+    since, err := parseTimeParam(r, "since")
+    if err != nil {
+        writeError(w, err.Error(), http.StatusBadRequest)
+        return nil, false
+    }
+    until, err := parseTimeParam(r, "until")
+    if err != nil {
+        writeError(w, err.Error(), http.StatusBadRequest)
+        return nil, false
+    }
+    limit, err := parseCountParam(r, "limit")
+    if err != nil {
+        writeError(w, err.Error(), http.StatusBadRequest)
+        return nil, false
+    }
+    offset, err := parseCountParam(r, "offset")
+    if err != nil {
+        writeError(w, err.Error(), http.StatusBadRequest)
+        return nil, false
+    }
+
+    logs, total := um.GetLogsFiltered(url, since, until, limit, offset)
+    w.Header().Set("X-Total-Count", strconv.Itoa(total))
+    return logs, true
 }
 
 func (um *UptimeMonitor) HandleGetDowntimes(w http.ResponseWriter, r *http.Request) {
     if r.Method != http.MethodGet {
-        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
         return
     }
 
     url := r.URL.Query().Get("url")
     if url == "" {
-        http.Error(w, "URL parameter is required", http.StatusBadRequest)
+        writeError(w, "URL parameter is required", http.StatusBadRequest)
         return
     }
 
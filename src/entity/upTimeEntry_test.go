@@ -0,0 +1,89 @@
+package entity
+
+import (
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+// TestConcurrentChecksAndRemovals runs checks of the same URLs from many goroutines while the
+// monitors are removed and added again and their state is read. Run it with -race.
+func TestConcurrentChecksAndRemovals(t *testing.T) {
+    var requests atomic.Int64
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if requests.Add(1)%3 == 0 {
+            w.WriteHeader(http.StatusInternalServerError)
+        }
+    }))
+    defer server.Close()
+
+    um := NewUptimeMonitor(WithRetryJitter(0))
+    defer um.Close()
+    var monitors []Monitor
+    for i := 0; i < 4; i++ {
+        monitor := Monitor{URL: fmt.Sprintf("%s/service-%d", server.URL, i), Interval: time.Hour}
+        if err := um.AddMonitor(monitor.URL, monitor.Interval); err != nil {
+            t.Fatal(err)
+        }
+        monitors = append(monitors, monitor)
+    }
+
+    var wg sync.WaitGroup
+    for worker := 0; worker < 8; worker++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for i := 0; i < 25; i++ {
+                um.checkURL(monitors[(worker+i)%len(monitors)])
+            }
+        }()
+    }
+    for _, monitor := range monitors[:2] {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for i := 0; i < 20; i++ {
+                um.RemoveMonitor(monitor.URL)
+                um.AddMonitor(monitor.URL, monitor.Interval)
+            }
+        }()
+    }
+    wg.Add(1)
+    go func() {
+        defer wg.Done()
+        for i := 0; i < 50; i++ {
+            um.GetSummary()
+            um.CollectMetrics()
+            for _, monitor := range monitors {
+                um.GetStats(monitor.URL)
+                um.GetLogs(monitor.URL)
+                um.GetDowntimes(monitor.URL)
+            }
+        }
+    }()
+    wg.Wait()
+
+    um.mu.RLock()
+    defer um.mu.RUnlock()
+    retained := 0
+    for url, logs := range um.logs {
+        retained += len(logs)
+        if state := um.states[url]; state == nil || state.checks != int64(len(logs)) {
+            t.Errorf("%s: %d logs retained but the state counted %v checks", url, len(logs), state)
+        }
+    }
+    if retained != um.logCount {
+        t.Errorf("%d logs retained per URL but %d counted", retained, um.logCount)
+    }
+    for url, downtimes := range um.downtimes {
+        for i, downtime := range downtimes[:len(downtimes)-1] {
+            if downtime.EndTime.IsZero() {
+                t.Errorf("%s: downtime %d of %d is still open", url, i+1, len(downtimes))
+            }
+        }
+    }
+}
@@ -0,0 +1,51 @@
+package entity
+
+import (
+    "fmt"
+    "io"
+    "time"
+)
+
+// maxUploadBytes caps generated upload bodies so a typo cannot saturate the network
+const maxUploadBytes = 64 << 20
+
+// uploadPattern fills generated upload bodies; printable so it is readable in server logs
+const uploadPattern = "urlmonitor-upload-probe\n"
+
+// patternReader yields uploadPattern repeatedly without buffering the whole body
+type patternReader struct {
+    offset int
+}
+
+func (r *patternReader) Read(p []byte) (int, error) {
+    for i := range p {
+        p[i] = uploadPattern[r.offset]
+        r.offset = (r.offset + 1) % len(uploadPattern)
+    }
+    return len(p), nil
+}
+
+// newUploadBody streams a generated body of exactly size bytes
+func newUploadBody(size int64) io.ReadCloser {
+    return io.NopCloser(io.LimitReader(&patternReader{}, size))
+}
+
+func validateUpload(monitor Monitor) error {
+    switch {
+    case monitor.UploadBytes < 0:
+        return fmt.Errorf("uploadBytes must not be negative")
+    case monitor.UploadBytes > maxUploadBytes:
+        return fmt.Errorf("uploadBytes must not exceed %d", maxUploadBytes)
+    case monitor.UploadBytes > 0 && monitor.Body != "":
+        return fmt.Errorf("uploadBytes cannot be combined with body")
+    }
+    return nil
+}
+
+// uploadThroughput returns bytes per second over the time spent writing the request
+func uploadThroughput(size int64, elapsed time.Duration) float64 {
+    if elapsed <= 0 {
+        return 0
+    }
+    return float64(size) / elapsed.Seconds()
+}
@@ -0,0 +1,67 @@
+package entity
+
+import (
+    "context"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestUploadBody(t *testing.T) {
+    data, err := io.ReadAll(newUploadBody(100))
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := strings.Repeat(uploadPattern, 100/len(uploadPattern)+1)[:100]
+    if string(data) != want {
+        t.Errorf("upload body = %q, want the repeated pattern", data)
+    }
+}
+
+func TestUploadCheck(t *testing.T) {
+    received := make(chan int64, 1)
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        n, _ := io.Copy(io.Discard, r.Body)
+        if r.Method != http.MethodPost || r.ContentLength != n {
+            http.Error(w, "unexpected upload", http.StatusBadRequest)
+        }
+        received <- n
+    }))
+    defer server.Close()
+
+    const size = 1 << 20
+    entry := NewUptimeMonitor().attemptCheck(context.Background(), Monitor{URL: server.URL + "/", UploadBytes: size})
+    if !entry.Success {
+        t.Fatalf("upload check failed: %s", entry.Error)
+    }
+    if n := <-received; n != size {
+        t.Errorf("server received %d bytes, want %d", n, size)
+    }
+    if entry.UploadBytes != size || entry.UploadThroughput <= 0 || entry.BytesSent < size {
+        t.Errorf("upload recorded as %d bytes at %.0f B/s, %d bytes sent", entry.UploadBytes, entry.UploadThroughput, entry.BytesSent)
+    }
+}
+
+func TestValidateUpload(t *testing.T) {
+    for _, monitor := range []Monitor{
+        {UploadBytes: -1},
+        {UploadBytes: maxUploadBytes + 1},
+        {UploadBytes: 10, Body: "payload"},
+    } {
+        if err := validateUpload(monitor); err == nil {
+            t.Errorf("validateUpload(%d bytes, body %q) succeeded, want an error", monitor.UploadBytes, monitor.Body)
+        }
+    }
+    if err := validateUpload(Monitor{UploadBytes: maxUploadBytes}); err != nil {
+        t.Error(err)
+    }
+    if got := uploadThroughput(1000, 500*time.Millisecond); got != 2000 {
+        t.Errorf("throughput = %v, want 2000", got)
+    }
+    if got := uploadThroughput(1000, 0); got != 0 {
+        t.Errorf("throughput without elapsed time = %v, want 0", got)
+    }
+}
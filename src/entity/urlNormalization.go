@@ -0,0 +1,56 @@
+package entity
+
+import (
+    "fmt"
+    "net/url"
+    "strings"
+)
+
+// defaultPorts are the ports dropped from monitor URLs by normalizeURL, keyed by the supported schemes
+var defaultPorts = map[string]string{
+    "http":  "80",
+    "https": "443",
+    "ftp":   "21",
+    "ftps":  "990",
+    "dns":   "",
+    "tcp":   "",
+}
+
+// normalizeURL validates a monitor URL and returns its canonical form, so that spellings of one
+// address such as http://Example.com and http://example.com/ map to the same monitor
+func normalizeURL(rawURL string) (string, error) {
+    if strings.TrimSpace(rawURL) == "" {
+        return "", fmt.Errorf("URL is required")
+    }
+    u, err := url.Parse(strings.TrimSpace(rawURL))
+    if err != nil {
+        return "", fmt.Errorf("invalid URL: %w", err)
+    }
+    if u.Scheme == "" || u.Host == "" {
+        return "", fmt.Errorf("invalid URL %q: scheme and host are required", rawURL)
+    }
+    defaultPort, supported := defaultPorts[u.Scheme]
+    if !supported {
+        return "", fmt.Errorf("invalid URL %q: unsupported scheme %q", rawURL, u.Scheme)
+    }
+
+    u.Host = strings.ToLower(u.Host)
+    if port := u.Port(); port != "" && port == defaultPort {
+        u.Host = strings.TrimSuffix(u.Host, ":"+port)
+    }
+    if u.Path == "" && (u.Scheme == "http" || u.Scheme == "https") {
+        u.Path = "/"
+    }
+    u.Fragment = "" // never sent to the server
+    return u.String(), nil
+}
+
+// monitorKey returns the key a monitor given as rawURL is stored under, so that lookups accept
+// every spelling normalizeURL accepts. URLs that do not normalize are returned unchanged and
+// simply match nothing.
+func monitorKey(rawURL string) string {
+    if normalized, err := normalizeURL(rawURL); err == nil {
+        return normalized
+    }
+    return rawURL
+}
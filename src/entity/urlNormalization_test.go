@@ -0,0 +1,99 @@
+package entity
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+)
+
+func TestNormalizeURL(t *testing.T) {
+    tests := []struct {
+        in      string
+        want    string
+        wantErr bool
+    }{
+        {in: "http://example.com", want: "http://example.com/"},
+        {in: "  https://Example.COM/path  ", want: "https://example.com/path"},
+        {in: "http://example.com:80/a", want: "http://example.com/a"},
+        {in: "https://example.com:443", want: "https://example.com/"},
+        {in: "https://example.com:8443", want: "https://example.com:8443/"},
+        {in: "http://example.com/page#section", want: "http://example.com/page"},
+        {in: "http://example.com/search?q=Go", want: "http://example.com/search?q=Go"},
+        {in: "ftp://files.example.com:21", want: "ftp://files.example.com"},
+        {in: "dns://Example.com", want: "dns://example.com"},
+        {in: "tcp://db.example.com:5432", want: "tcp://db.example.com:5432"},
+        {in: "", wantErr: true},
+        {in: "example.com", wantErr: true},
+        {in: "http://", wantErr: true},
+        {in: "gopher://example.com", wantErr: true},
+        {in: "http://exa mple.com", wantErr: true},
+    }
+    for _, tt := range tests {
+        got, err := normalizeURL(tt.in)
+        if tt.wantErr {
+            if err == nil {
+                t.Errorf("normalizeURL(%q) = %q, want an error", tt.in, got)
+            }
+            continue
+        }
+        if err != nil || got != tt.want {
+            t.Errorf("normalizeURL(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+        }
+    }
+}
+
+func TestMonitorKey(t *testing.T) {
+    if got := monitorKey("HTTP://Example.com"); got != "http://example.com/" {
+        t.Errorf("monitorKey normalized to %q", got)
+    }
+    if got := monitorKey("not a url"); got != "not a url" {
+        t.Errorf("monitorKey(%q) = %q, want it unchanged", "not a url", got)
+    }
+}
+
+func TestLookupsAcceptUnnormalizedURL(t *testing.T) {
+    um := NewUptimeMonitor()
+    if err := um.AddMonitor("http://Example.com", time.Hour); err != nil {
+        t.Fatal(err)
+    }
+    um.handleFailure(LogEntry{URL: "http://example.com/", Timestamp: time.Now(), StatusCode: http.StatusBadGateway})
+
+    const raw = "http://example.com"
+    if logs := um.GetLogs(raw); len(logs) != 1 {
+        t.Errorf("GetLogs(%q) returned %d entries, want 1", raw, len(logs))
+    }
+    if downtimes := um.GetDowntimes(raw); len(downtimes) != 1 {
+        t.Errorf("GetDowntimes(%q) returned %d entries, want 1", raw, len(downtimes))
+    }
+    if stats := um.GetStats(raw); stats.TotalChecks != 1 {
+        t.Errorf("GetStats(%q) counted %d checks, want 1", raw, stats.TotalChecks)
+    }
+    if status := um.GetStatus(raw); status.State != StateDown {
+        t.Errorf("GetStatus(%q) = %s, want %s", raw, status.State, StateDown)
+    }
+    if err := um.PauseMonitor(raw); err != nil {
+        t.Errorf("PauseMonitor(%q): %v", raw, err)
+    }
+    if err := um.ResumeMonitor(raw); err != nil {
+        t.Errorf("ResumeMonitor(%q): %v", raw, err)
+    }
+    if err := um.UpdateInterval(raw, 2*time.Hour); err != nil {
+        t.Errorf("UpdateInterval(%q): %v", raw, err)
+    }
+    if err := um.ArchiveMonitor(raw); err != nil {
+        t.Errorf("ArchiveMonitor(%q): %v", raw, err)
+    }
+    if err := um.UnarchiveMonitor(raw); err != nil {
+        t.Errorf("UnarchiveMonitor(%q): %v", raw, err)
+    }
+
+    rec := httptest.NewRecorder()
+    um.HandleRemoveMonitor(rec, httptest.NewRequest(http.MethodDelete, "/monitor?url="+raw, nil))
+    if rec.Code != http.StatusOK {
+        t.Fatalf("DELETE /monitor?url=%s = %d: %s", raw, rec.Code, rec.Body)
+    }
+    if monitors := um.ListMonitors(); len(monitors) != 0 {
+        t.Errorf("monitors left after removal: %+v", monitors)
+    }
+}
@@ -0,0 +1,177 @@
+package entity
+
+import "time"
+
+// urlState caches the latest known state of a URL so that current-state
+// lookups do not have to scan the log and downtime history. It is updated
+// on every recorded check and must only be accessed with um.mu held.
+type urlState struct {
+    lastCheck        time.Time
+    lastSuccess      bool
+    lastResponseTime int64
+
+    // Current run of consecutive successful checks and when it began, or of failed checks;
+    // one of the two streaks is always zero
+    successStreak int
+    streakStart   time.Time
+    failureStreak int
+
+    // Running totals since the URL was first checked
+    checks    int64
+    failures  int64
+    incidents int64
+
+    bytesSent     int64
+    bytesReceived int64
+
+    // Cache hit tracking for monitors with a cache header
+    cacheLookups int64
+    cacheHits    int64
+    recentCache  []bool // latest observations, oldest first, at most cacheHitWindow
+    cacheAlerted bool   // a low hit rate alert was sent and the rate has not recovered since
+
+    latency    *latencyHistogram
+    dnsRecords []DNSRecord // records seen by the previous final DNS check
+
+    // The first check's state and the up/down changes since, oldest first, at most
+    // maxTransitions. They are kept here as the log may leave out successful checks.
+    firstCheck  Transition
+    transitions []Transition
+}
+
+// stateLocked returns the cached state for url, creating it on first use; callers must hold um.mu
+func (um *UptimeMonitor) stateLocked(url string) *urlState {
+    state, ok := um.states[url]
+    if !ok {
+        state = &urlState{latency: newLatencyHistogram(um.buckets)}
+        um.states[url] = state
+    }
+    return state
+}
+
+// appendLogLocked records a check result and updates the cached state; callers must hold um.mu.
+// Successful entries are only counted, not retained, when success logging is disabled.
+func (um *UptimeMonitor) appendLogLocked(entry LogEntry) {
+    um.retainLogLocked(entry)
+
+    state := um.stateLocked(entry.URL)
+    state.observeTransition(entry)
+    state.lastCheck = entry.Timestamp
+    state.lastSuccess = entry.Success
+    state.lastResponseTime = entry.ResponseTime
+    state.checks++
+    state.bytesSent += entry.BytesSent
+    state.bytesReceived += entry.BytesReceived
+    state.latency.observe(um.buckets, entry.ResponseTime)
+    if !entry.Success {
+        state.failures++
+        state.successStreak = 0
+        state.failureStreak++
+    } else {
+        if state.successStreak == 0 {
+            state.streakStart = entry.Timestamp
+        }
+        state.successStreak++
+        state.failureStreak = 0
+    }
+    um.observeCacheLocked(state, entry)
+    if entry.DNSRecords != nil {
+        state.dnsRecords = entry.DNSRecords
+    }
+}
+
+// logRef identifies a check in the global log order by its URL and its sequence number
+// among that URL's checks. Refs to checks already evicted by the per-URL cap stay in the
+// order until it is compacted; their sequence number is below the URL's logSeq.
+type logRef struct {
+    url string
+    seq int
+}
+
+// retainLogLocked stores the entry in the log, subject to the retention settings; callers must hold um.mu
+func (um *UptimeMonitor) retainLogLocked(entry LogEntry) {
+    if entry.Success && (um.skipSuccess || um.monitors[entry.URL].SkipSuccessLogs) {
+        return
+    }
+    um.logOrder = append(um.logOrder, logRef{url: entry.URL, seq: um.logSeq[entry.URL] + len(um.logs[entry.URL])})
+    um.logs[entry.URL] = append(um.logs[entry.URL], entry)
+    um.logCount++
+    if um.urlLogCap > 0 && len(um.logs[entry.URL]) > um.urlLogCap {
+        um.trimURLLogsLocked(entry.URL, um.urlLogCap)
+    }
+    if um.maxLogs > 0 {
+        um.trimLogsLocked(um.maxLogs)
+    }
+}
+
+// trimURLLogsLocked evicts the oldest entries of url until at most keep remain. Their refs
+// in the global order are left behind, so eviction takes constant time; the order is
+// compacted once stale refs make up half of it. Callers must hold um.mu.
+func (um *UptimeMonitor) trimURLLogsLocked(url string, keep int) {
+    drop := len(um.logs[url]) - keep
+    if drop <= 0 {
+        return
+    }
+    um.logs[url] = um.logs[url][drop:]
+    um.logSeq[url] += drop
+    um.logCount -= drop
+    if len(um.logOrder) > 2*um.logCount {
+        um.compactLogOrderLocked()
+    }
+}
+
+// compactLogOrderLocked copies the refs of retained entries into a new global order, so the
+// stale refs and the evicted prefix can be collected; callers must hold um.mu
+func (um *UptimeMonitor) compactLogOrderLocked() {
+    order := make([]logRef, 0, um.logCount)
+    for _, ref := range um.logOrder {
+        if ref.seq >= um.logSeq[ref.url] {
+            order = append(order, ref)
+        }
+    }
+    um.logOrder = order
+    // Without refs left, a URL without logs may start its sequence over
+    for url := range um.logSeq {
+        if len(um.logs[url]) == 0 {
+            delete(um.logSeq, url)
+        }
+    }
+}
+
+// trimLogsLocked evicts the oldest entries across all URLs until at most keep remain. Reslicing
+// drops them without copying; the next reallocation by append releases the evicted prefix.
+// Callers must hold um.mu.
+func (um *UptimeMonitor) trimLogsLocked(keep int) {
+    for um.logCount > keep {
+        ref := um.logOrder[0]
+        um.logOrder = um.logOrder[1:]
+        if ref.seq < um.logSeq[ref.url] {
+            // Already evicted by the URL's cap
+            continue
+        }
+        if logs := um.logs[ref.url]; len(logs) > 1 {
+            um.logs[ref.url] = logs[1:]
+        } else {
+            delete(um.logs, ref.url)
+        }
+        um.logSeq[ref.url]++
+        um.logCount--
+    }
+}
+
+// allLogsLocked returns every retained entry in the order it was logged; callers must hold um.mu
+func (um *UptimeMonitor) allLogsLocked() []LogEntry {
+    logs := make([]LogEntry, 0, um.logCount)
+    for _, ref := range um.logOrder {
+        if i := ref.seq - um.logSeq[ref.url]; i >= 0 {
+            logs = append(logs, um.logs[ref.url][i])
+        }
+    }
+    return logs
+}
+
+// appendDowntimeLocked opens a new downtime and indexes it; callers must hold um.mu
+func (um *UptimeMonitor) appendDowntimeLocked(downtime DowntimeEntry) {
+    um.downtimes[downtime.URL] = append(um.downtimes[downtime.URL], downtime)
+    um.stateLocked(downtime.URL).incidents++
+}
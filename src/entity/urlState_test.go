@@ -0,0 +1,55 @@
+package entity
+
+import (
+    "testing"
+    "time"
+)
+
+func TestStateCountsUnretainedChecks(t *testing.T) {
+    um := NewUptimeMonitor(WithoutSuccessLogs())
+    const url = "https://example.com/"
+    now := time.Now()
+    um.mu.Lock()
+    um.appendLogLocked(LogEntry{URL: url, Success: true, ResponseTime: 10, BytesReceived: 100, Timestamp: now})
+    um.appendLogLocked(LogEntry{URL: url, Success: false, ResponseTime: 20, BytesReceived: 50, Timestamp: now.Add(time.Minute)})
+    um.appendLogLocked(LogEntry{URL: url, Success: true, ResponseTime: 30, BytesReceived: 100, Timestamp: now.Add(2 * time.Minute)})
+    um.mu.Unlock()
+
+    if logs := um.GetLogs(url); len(logs) != 1 || logs[0].Success {
+        t.Fatalf("retained %+v, want only the failure", logs)
+    }
+    stats := um.GetStats(url)
+    if stats.TotalChecks != 3 || stats.FailureCount != 1 || stats.BytesReceived != 250 || stats.ConsecutiveSuccesses != 1 {
+        t.Errorf("stats = %+v, want every check counted", stats)
+    }
+    if status := um.GetStatus(url); status.State != StateUp || !status.LastCheck.Equal(now.Add(2*time.Minute)) {
+        t.Errorf("status = %+v, want up from the unretained latest check", status)
+    }
+}
+
+func TestMaxLogsEvictsOldestAcrossURLs(t *testing.T) {
+    um := NewUptimeMonitor(WithMaxLogs(3))
+    now := time.Now()
+    um.mu.Lock()
+    for i, url := range []string{"https://a.example.com/", "https://b.example.com/", "https://a.example.com/", "https://c.example.com/", "https://b.example.com/"} {
+        um.appendLogLocked(LogEntry{URL: url, Success: true, Timestamp: now.Add(time.Duration(i) * time.Second)})
+    }
+    logs := um.allLogsLocked()
+    _, keptA := um.logs["https://a.example.com/"]
+    um.mu.Unlock()
+
+    if len(logs) != 3 {
+        t.Fatalf("retained %d logs, want 3", len(logs))
+    }
+    for i, want := range []string{"https://a.example.com/", "https://c.example.com/", "https://b.example.com/"} {
+        if logs[i].URL != want || !logs[i].Timestamp.Equal(now.Add(time.Duration(i+2)*time.Second)) {
+            t.Errorf("log %d = %s at %v, want the newest three in order", i, logs[i].URL, logs[i].Timestamp)
+        }
+    }
+    if !keptA {
+        t.Error("a.example.com lost its remaining log")
+    }
+    if stats := um.GetStats("https://a.example.com/"); stats.TotalChecks != 2 {
+        t.Errorf("a.example.com counted %d checks, want evicted ones still counted", stats.TotalChecks)
+    }
+}
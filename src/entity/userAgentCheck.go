@@ -0,0 +1,105 @@
+package entity
+
+import (
+    "context"
+    "encoding/json"
+    "io"
+    "net/http"
+    "time"
+)
+
+// UserAgentResult is the outcome of checking a URL with one User-Agent
+type UserAgentResult struct {
+    UserAgent    string `json:"userAgent"`
+    StatusCode   int    `json:"statusCode"`
+    ResponseTime int64  `json:"responseTime"` // in milliseconds
+    Success      bool   `json:"success"`
+    Error        string `json:"error,omitempty"`
+    Differs      bool   `json:"differs"` // the outcome differs from the most common one
+}
+
+// UserAgentComparison reports how a URL responds to several User-Agents
+type UserAgentComparison struct {
+    URL        string            `json:"url"`
+    Consistent bool              `json:"consistent"` // every User-Agent got the same outcome
+    Results    []UserAgentResult `json:"results"`
+}
+
+// CompareUserAgents checks url once per User-Agent, e.g. to detect WAF or CDN blocking.
+// These one-off checks are not recorded in the logs.
+func (um *UptimeMonitor) CompareUserAgents(url string, userAgents []string) UserAgentComparison {
+    comparison := UserAgentComparison{URL: url, Consistent: true}
+    outcomes := make(map[int]int)
+    for _, userAgent := range userAgents {
+        result := um.checkUserAgent(url, userAgent)
+        outcomes[result.StatusCode]++
+        comparison.Results = append(comparison.Results, result)
+    }
+
+    // The most common status code is the baseline; transport errors count as status 0
+    baseline, best := 0, -1
+    for code, count := range outcomes {
+        if count > best || count == best && code < baseline {
+            baseline, best = code, count
+        }
+    }
+    for i := range comparison.Results {
+        if comparison.Results[i].StatusCode != baseline {
+            comparison.Results[i].Differs = true
+            comparison.Consistent = false
+        }
+    }
+    return comparison
+}
+
+func (um *UptimeMonitor) checkUserAgent(url, userAgent string) UserAgentResult {
+    result := UserAgentResult{UserAgent: userAgent}
+
+    ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+    defer cancel()
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+    if err != nil {
+        result.Error = err.Error()
+        return result
+    }
+    req.Header.Set("User-Agent", userAgent)
+
+    start := time.Now()
+    resp, err := um.client.Do(req)
+    if err != nil {
+        result.ResponseTime = time.Since(start).Milliseconds()
+        result.Error = err.Error()
+        return result
+    }
+    io.Copy(io.Discard, io.LimitReader(resp.Body, maxBodyBytes))
+    resp.Body.Close()
+
+    result.ResponseTime = time.Since(start).Milliseconds()
+    result.StatusCode = resp.StatusCode
+    result.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+    return result
+}
+
+func (um *UptimeMonitor) HandleCompareUserAgents(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodPost {
+        writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    var req struct {
+        URL        string   `json:"url"`
+        UserAgents []string `json:"userAgents"`
+    }
+
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeError(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    if req.URL == "" || len(req.UserAgents) == 0 {
+        writeError(w, "url and userAgents are required", http.StatusBadRequest)
+        return
+    }
+
+    json.NewEncoder(w).Encode(um.CompareUserAgents(req.URL, req.UserAgents))
+}
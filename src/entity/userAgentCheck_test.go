@@ -0,0 +1,47 @@
+package entity
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+func TestCompareUserAgents(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if strings.Contains(r.UserAgent(), "curl") {
+            http.Error(w, "blocked", http.StatusForbidden)
+        }
+    }))
+    defer server.Close()
+    um := NewUptimeMonitor()
+
+    comparison := um.CompareUserAgents(server.URL, []string{"Mozilla/5.0", "Googlebot/2.1", "curl/8.0"})
+    if comparison.Consistent || len(comparison.Results) != 3 {
+        t.Fatalf("comparison = %+v, want 3 inconsistent results", comparison)
+    }
+    for i, want := range []struct {
+        status  int
+        differs bool
+    }{{http.StatusOK, false}, {http.StatusOK, false}, {http.StatusForbidden, true}} {
+        result := comparison.Results[i]
+        if result.StatusCode != want.status || result.Differs != want.differs || result.Success != (want.status == http.StatusOK) {
+            t.Errorf("%s = %+v, want status %d, differs %v", result.UserAgent, result, want.status, want.differs)
+        }
+    }
+
+    if comparison := um.CompareUserAgents(server.URL, []string{"Mozilla/5.0", "Safari"}); !comparison.Consistent {
+        t.Errorf("comparison = %+v, want it consistent", comparison)
+    }
+}
+
+func TestHandleCompareUserAgents(t *testing.T) {
+    um := NewUptimeMonitor()
+    for _, body := range []string{`{"url": "https://example.com/"}`, `{"userAgents": ["curl"]}`, `not json`} {
+        rec := httptest.NewRecorder()
+        um.HandleCompareUserAgents(rec, httptest.NewRequest(http.MethodPost, "/monitor/useragents", strings.NewReader(body)))
+        if rec.Code != http.StatusBadRequest {
+            t.Errorf("POST %s = %d, want 400", body, rec.Code)
+        }
+    }
+}
@@ -0,0 +1,48 @@
+package entity
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "time"
+)
+
+// WebhookAlerter POSTs each event as JSON to a URL. It uses its own client, so a slow
+// webhook endpoint never competes with checks for connections.
+type WebhookAlerter struct {
+    URL    string
+    Client *http.Client
+}
+
+// NewWebhookAlerter returns an alerter posting to url with a 10 second timeout
+func NewWebhookAlerter(url string) *WebhookAlerter {
+    return &WebhookAlerter{URL: url, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// WithWebhook posts down, up and other events to url
+func WithWebhook(url string) Option {
+    return func(um *UptimeMonitor) {
+        um.alerters = append(um.alerters, NewWebhookAlerter(url))
+    }
+}
+
+func (a *WebhookAlerter) Name() string {
+    return "webhook " + a.URL
+}
+
+func (a *WebhookAlerter) Notify(event DowntimeEvent) error {
+    payload, err := json.Marshal(event)
+    if err != nil {
+        return err
+    }
+    resp, err := a.Client.Post(a.URL, "application/json", bytes.NewReader(payload))
+    if err != nil {
+        return err
+    }
+    resp.Body.Close()
+    if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+        return fmt.Errorf("webhook %s answered %s", a.URL, resp.Status)
+    }
+    return nil
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+)
+
+func TestWebhookAlerter(t *testing.T) {
+    events := make(chan DowntimeEvent, 2)
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Header.Get("Content-Type") != "application/json" {
+            http.Error(w, "not JSON", http.StatusUnsupportedMediaType)
+            return
+        }
+        var event DowntimeEvent
+        if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+            http.Error(w, err.Error(), http.StatusBadRequest)
+            return
+        }
+        events <- event
+    }))
+    defer server.Close()
+
+    // Down and up transitions of a monitored URL reach the webhook
+    um := NewUptimeMonitor(WithWebhook(server.URL))
+    const url = "https://example.com/"
+    um.monitors[url] = Monitor{URL: url}
+    now := time.Now()
+    um.recordCheck(LogEntry{URL: url, Success: false, StatusCode: 503, Timestamp: now})
+    um.recordCheck(LogEntry{URL: url, Success: true, StatusCode: 200, Timestamp: now.Add(time.Minute)})
+
+    // Each event is delivered on its own, so they may arrive in either order
+    received := make(map[string]bool)
+    for i := 0; i < 2; i++ {
+        select {
+        case event := <-events:
+            if event.URL != url {
+                t.Errorf("webhook got a %s event for %s", event.Type, event.URL)
+            }
+            received[event.Type] = true
+        case <-time.After(2 * time.Second):
+            t.Fatalf("only %d events were posted", i)
+        }
+    }
+    if !received[EventDown] || !received[EventUp] {
+        t.Errorf("webhook got %v, want a down and an up event", received)
+    }
+}
+
+func TestWebhookAlerterRejected(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(http.StatusInternalServerError)
+    }))
+    defer server.Close()
+
+    if err := NewWebhookAlerter(server.URL).Notify(DowntimeEvent{Type: EventDown}); err == nil {
+        t.Error("expected an error for a failed post")
+    }
+    server.Close()
+    if err := NewWebhookAlerter(server.URL).Notify(DowntimeEvent{Type: EventDown}); err == nil {
+        t.Error("expected an error for an unreachable webhook")
+    }
+}